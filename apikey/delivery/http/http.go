@@ -0,0 +1,129 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	pkgCursor "github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
+)
+
+type apiKeyHandler struct {
+	service domain.APIKeyService
+}
+
+// keyResponse is an API key with its plain key, it is only returned on create and rotate
+type keyResponse struct {
+	domain.APIKey
+	Key string `json:"key"`
+}
+
+// AddAPIKeyHandler adds the API key admin handler
+func AddAPIKeyHandler(e *echo.Echo, service domain.APIKeyService) {
+	if service == nil {
+		panic("http: nil api key service")
+	}
+
+	handler := &apiKeyHandler{service}
+
+	e.POST("/api-keys", handler.Create)
+	e.GET("/api-keys/:id", handler.Get)
+	e.GET("/api-keys", handler.Fetch)
+	e.POST("/api-keys/:id/rotate", handler.Rotate)
+	e.DELETE("/api-keys/:id", handler.Revoke)
+}
+
+func (h apiKeyHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var k domain.APIKey
+	if err := c.Bind(&k); err != nil {
+		return err
+	}
+
+	if err := validator.Validate(k); err != nil {
+		return err
+	}
+
+	key, err := h.service.Create(ctx, &k)
+	if err != nil {
+		return errors.Wrap(err, "failed to create an api key")
+	}
+
+	return c.JSON(http.StatusCreated, keyResponse{APIKey: k, Key: key})
+}
+
+func (h apiKeyHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	k, err := h.service.Get(ctx, c.Param("id"))
+	if err != nil {
+		return errors.Wrap(err, "failed get an api key")
+	}
+
+	return c.JSON(http.StatusOK, k)
+}
+
+func (h apiKeyHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	num := 20
+	if numStr := c.QueryParam("num"); numStr != "" {
+		var err error
+		if num, err = strconv.Atoi(numStr); err != nil {
+			err = fmt.Errorf("num query-param is not valid. Got error when parsing value: %v", err)
+			return domain.ConstraintErrorf("%s", err)
+		}
+	}
+
+	withRevoked := false
+	if revokedStr := c.QueryParam("revoked"); revokedStr != "" {
+		var err error
+		if withRevoked, err = strconv.ParseBool(revokedStr); err != nil {
+			err = fmt.Errorf("revoked query-param is not valid. Got error when parsing value: %v", err)
+			return domain.ConstraintErrorf("%s", err)
+		}
+	}
+
+	res, pagination, err := h.service.Fetch(ctx, domain.APIKeyFilter{
+		Num:         num,
+		Cursor:      c.QueryParam("cursor"),
+		WithRevoked: withRevoked,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error fetch api keys")
+	}
+
+	c.Response().Header().Set("X-Cursor", pagination.NextCursor)
+	if link := pkgCursor.LinkHeader(*c.Request().URL, pagination.NextCursor, ""); link != "" {
+		c.Response().Header().Set("Link", link)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h apiKeyHandler) Rotate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	k, key, err := h.service.Rotate(ctx, c.Param("id"))
+	if err != nil {
+		return errors.Wrap(err, "failed to rotate an api key")
+	}
+
+	return c.JSON(http.StatusOK, keyResponse{APIKey: k, Key: key})
+}
+
+func (h apiKeyHandler) Revoke(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.service.Revoke(ctx, c.Param("id")); err != nil {
+		return errors.Wrap(err, "failed to revoke an api key")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,243 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/segmentio/ksuid"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// Repository implement all API key repository method from interface
+type Repository struct {
+	DB *sql.DB
+}
+
+// New return new API key repository
+func New(db *sql.DB) Repository {
+	return Repository{
+		DB: db,
+	}
+}
+
+var columns = []string{"id", "tenant_id", "name", "scopes", "hashed_secret", "last_used_time", "revoked_time", "created_time", "updated_time"}
+
+// Create is a repository to insert an API key of the tenant
+func (r Repository) Create(ctx context.Context, k *domain.APIKey) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if k.ID == "" {
+		k.ID = ksuid.New().String()
+	}
+
+	k.TenantID = domain.TenantFromContext(ctx)
+	k.CreatedTime = localTime
+	k.UpdatedTime = localTime
+
+	query, args, err := sq.Insert("api_keys").
+		Columns("id", "tenant_id", "name", "scopes", "hashed_secret", "created_time", "updated_time").
+		Values(k.ID, k.TenantID, k.Name, strings.Join(k.Scopes, ","), k.HashedSecret, k.CreatedTime, k.UpdatedTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	_, err = r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed insert an api key")
+		return
+	}
+
+	return
+}
+
+// Fetch is a repository to fetch API keys of the tenant, revoked keys are excluded unless requested
+func (r Repository) Fetch(ctx context.Context, filter domain.APIKeyFilter) (keys []domain.APIKey, pagination domain.Pagination, err error) {
+	qSelect := sq.Select(columns...).
+		From("api_keys").
+		Where(sq.Eq{"tenant_id": domain.TenantFromContext(ctx)}).
+		OrderBy("id desc")
+
+	if !filter.WithRevoked {
+		qSelect = qSelect.Where(sq.Eq{"revoked_time": nil})
+	}
+
+	if filter.Cursor != "" {
+		var decodedCursor string
+		if decodedCursor, err = cursor.DecodeBase64(filter.Cursor); err != nil {
+			err = domain.ConstraintErrorf("cursor is not valid")
+			return
+		}
+		qSelect = qSelect.Where(sq.Lt{"id": decodedCursor})
+	}
+
+	if filter.Num > 0 {
+		qSelect = qSelect.Limit(uint64(filter.Num))
+	}
+
+	query, args, err := qSelect.ToSql()
+	if err != nil {
+		return
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	keys = make([]domain.APIKey, 0)
+	for rows.Next() {
+		var k domain.APIKey
+		if k, err = scan(rows); err != nil {
+			return
+		}
+		keys = append(keys, k)
+	}
+
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	pagination.NextCursor = filter.Cursor
+	if len(keys) > 0 {
+		pagination.NextCursor = cursor.EncodeBase64(keys[len(keys)-1].ID)
+	}
+
+	return
+}
+
+// Get is a repository to get an API key of the tenant
+func (r Repository) Get(ctx context.Context, id string) (k domain.APIKey, err error) {
+	return r.get(ctx, sq.Eq{"id": id, "tenant_id": domain.TenantFromContext(ctx)})
+}
+
+// Lookup is a repository to get an API key of any tenant
+func (r Repository) Lookup(ctx context.Context, id string) (k domain.APIKey, err error) {
+	return r.get(ctx, sq.Eq{"id": id})
+}
+
+func (r Repository) get(ctx context.Context, where sq.Eq) (k domain.APIKey, err error) {
+	query, args, err := sq.Select(columns...).
+		From("api_keys").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	k, err = scan(r.DB.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+// UpdateSecret is a repository to replace the hashed secret of an API key which is not revoked
+func (r Repository) UpdateSecret(ctx context.Context, id, hashedSecret string) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	return r.update(ctx, id, sq.Eq{
+		"hashed_secret": hashedSecret,
+		"updated_time":  localTime,
+	})
+}
+
+// Revoke is a repository to revoke an API key which is not revoked yet
+func (r Repository) Revoke(ctx context.Context, id string, revokedTime time.Time) (err error) {
+	return r.update(ctx, id, sq.Eq{
+		"revoked_time": revokedTime,
+		"updated_time": revokedTime,
+	})
+}
+
+// Touch is a repository to record the last time an API key is used
+func (r Repository) Touch(ctx context.Context, id string, lastUsedTime time.Time) (err error) {
+	return r.update(ctx, id, sq.Eq{
+		"last_used_time": lastUsedTime,
+	})
+}
+
+func (r Repository) update(ctx context.Context, id string, values sq.Eq) (err error) {
+	query, args, err := sq.Update("api_keys").
+		SetMap(values).
+		Where(sq.Eq{"id": id, "tenant_id": domain.TenantFromContext(ctx), "revoked_time": nil}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed update an api key")
+		return
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if count == 0 {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (k domain.APIKey, err error) {
+	var (
+		scopes       string
+		lastUsedTime mysql.NullTime
+		revokedTime  mysql.NullTime
+	)
+
+	err = row.Scan(
+		&k.ID,
+		&k.TenantID,
+		&k.Name,
+		&scopes,
+		&k.HashedSecret,
+		&lastUsedTime,
+		&revokedTime,
+		&k.CreatedTime,
+		&k.UpdatedTime,
+	)
+	if err != nil {
+		return
+	}
+
+	k.Scopes = make([]string, 0)
+	if scopes != "" {
+		k.Scopes = strings.Split(scopes, ",")
+	}
+
+	if lastUsedTime.Valid {
+		k.LastUsedTime = &lastUsedTime.Time
+	}
+
+	if revokedTime.Valid {
+		k.RevokedTime = &revokedTime.Time
+	}
+
+	return
+}
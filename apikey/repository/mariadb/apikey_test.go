@@ -0,0 +1,128 @@
+package mariadb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	repo "github.com/milhamhidayat/golang-clean-code-v2/apikey/repository/mariadb"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	mariadb "github.com/milhamhidayat/golang-clean-code-v2/driver/mariadb"
+)
+
+type apiKeySuite struct {
+	mariadb.DBSuite
+}
+
+func TestAPIKeySuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped for short testing")
+	}
+	suite.Run(t, new(apiKeySuite))
+}
+
+func (a *apiKeySuite) SetupTest() {
+	_, err := a.DB.Exec("TRUNCATE api_keys")
+	require.NoError(a.T(), err)
+}
+
+func (a *apiKeySuite) TestCreate() {
+	apiKeyRepo := repo.New(a.DB)
+
+	k := domain.APIKey{
+		Name:         "payroll",
+		Scopes:       []string{domain.ScopeEmployeesRead, domain.ScopeDepartmentsRead},
+		HashedSecret: "hashed",
+	}
+	err := apiKeyRepo.Create(context.Background(), &k)
+	require.NoError(a.T(), err)
+	require.NotEmpty(a.T(), k.ID)
+
+	res, err := apiKeyRepo.Get(context.Background(), k.ID)
+	require.NoError(a.T(), err)
+	require.Equal(a.T(), k.Name, res.Name)
+	require.Equal(a.T(), k.Scopes, res.Scopes)
+	require.Equal(a.T(), k.HashedSecret, res.HashedSecret)
+	require.Nil(a.T(), res.LastUsedTime)
+	require.False(a.T(), res.IsRevoked())
+}
+
+func (a *apiKeySuite) TestRevoke() {
+	apiKeyRepo := repo.New(a.DB)
+
+	k := domain.APIKey{Name: "payroll", Scopes: []string{domain.ScopeEmployeesRead}, HashedSecret: "hashed"}
+	require.NoError(a.T(), apiKeyRepo.Create(context.Background(), &k))
+
+	a.T().Run("success", func(t *testing.T) {
+		err := apiKeyRepo.Revoke(context.Background(), k.ID, time.Now())
+		require.NoError(t, err)
+
+		res, err := apiKeyRepo.Get(context.Background(), k.ID)
+		require.NoError(t, err)
+		require.True(t, res.IsRevoked())
+	})
+
+	a.T().Run("revoked key is not updated", func(t *testing.T) {
+		err := apiKeyRepo.UpdateSecret(context.Background(), k.ID, "rotated")
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+
+	a.T().Run("revoked key is excluded from fetch", func(t *testing.T) {
+		keys, _, err := apiKeyRepo.Fetch(context.Background(), domain.APIKeyFilter{Num: 10})
+		require.NoError(t, err)
+		require.Len(t, keys, 0)
+
+		keys, _, err = apiKeyRepo.Fetch(context.Background(), domain.APIKeyFilter{Num: 10, WithRevoked: true})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+	})
+}
+
+func (a *apiKeySuite) TestTouch() {
+	apiKeyRepo := repo.New(a.DB)
+
+	k := domain.APIKey{Name: "payroll", Scopes: []string{domain.ScopeEmployeesRead}, HashedSecret: "hashed"}
+	require.NoError(a.T(), apiKeyRepo.Create(context.Background(), &k))
+
+	err := apiKeyRepo.Touch(context.Background(), k.ID, time.Now())
+	require.NoError(a.T(), err)
+
+	res, err := apiKeyRepo.Get(context.Background(), k.ID)
+	require.NoError(a.T(), err)
+	require.NotNil(a.T(), res.LastUsedTime)
+}
+
+func (a *apiKeySuite) TestTenant() {
+	apiKeyRepo := repo.New(a.DB)
+	acme := domain.NewContextWithTenant(context.Background(), "acme")
+	globex := domain.NewContextWithTenant(context.Background(), "globex")
+
+	k := domain.APIKey{Name: "payroll", Scopes: []string{domain.ScopeEmployeesRead}, HashedSecret: "hashed"}
+	require.NoError(a.T(), apiKeyRepo.Create(acme, &k))
+
+	a.T().Run("other tenant can't get the key", func(t *testing.T) {
+		_, err := apiKeyRepo.Get(globex, k.ID)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+
+	a.T().Run("other tenant can't fetch the key", func(t *testing.T) {
+		keys, _, err := apiKeyRepo.Fetch(globex, domain.APIKeyFilter{Num: 10, WithRevoked: true})
+		require.NoError(t, err)
+		require.Len(t, keys, 0)
+	})
+
+	a.T().Run("other tenant can't rotate or revoke the key", func(t *testing.T) {
+		require.Equal(t, domain.ErrNotFound, apiKeyRepo.UpdateSecret(globex, k.ID, "rotated"))
+		require.Equal(t, domain.ErrNotFound, apiKeyRepo.Revoke(globex, k.ID, time.Now()))
+	})
+
+	a.T().Run("lookup returns the key with its tenant", func(t *testing.T) {
+		res, err := apiKeyRepo.Lookup(globex, k.ID)
+		require.NoError(t, err)
+		require.Equal(t, "acme", res.TenantID)
+		require.Equal(t, "hashed", res.HashedSecret)
+	})
+}
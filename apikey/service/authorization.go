@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Authorization is an API key service which only allows admin to manage API keys.
+// Authenticate is not restricted since it is called before the caller is known
+type Authorization struct {
+	next domain.APIKeyService
+}
+
+// NewAuthorization will create an API key service which only allows admin to manage API keys
+func NewAuthorization(next domain.APIKeyService) Authorization {
+	return Authorization{
+		next: next,
+	}
+}
+
+func authorize(ctx context.Context) (err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	if !p.HasRole(domain.RoleAdmin) {
+		return domain.ErrForbidden
+	}
+
+	return
+}
+
+// Create will create an API key when the caller is admin
+func (a Authorization) Create(ctx context.Context, k *domain.APIKey) (key string, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Create(ctx, k)
+}
+
+// Fetch will fetch API keys when the caller is admin
+func (a Authorization) Fetch(ctx context.Context, filter domain.APIKeyFilter) (keys []domain.APIKey, pagination domain.Pagination, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Fetch(ctx, filter)
+}
+
+// Get will get an API key when the caller is admin
+func (a Authorization) Get(ctx context.Context, id string) (k domain.APIKey, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Get(ctx, id)
+}
+
+// Rotate will rotate an API key when the caller is admin
+func (a Authorization) Rotate(ctx context.Context, id string) (k domain.APIKey, key string, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Rotate(ctx, id)
+}
+
+// Revoke will revoke an API key when the caller is admin
+func (a Authorization) Revoke(ctx context.Context, id string) (err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Revoke(ctx, id)
+}
+
+// Authenticate will authenticate the plain key
+func (a Authorization) Authenticate(ctx context.Context, key string) (p domain.Principal, err error) {
+	return a.next.Authenticate(ctx, key)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// touchInterval is the minimum interval between two updates of the last used time of a key
+const touchInterval = time.Minute
+
+// SubjectPrefix is the prefix of the principal subject authenticated by an API key
+const SubjectPrefix = "apikey:"
+
+// Service is an API key service
+type Service struct {
+	repo domain.APIKeyRepository
+}
+
+// New will create a new API key service
+func New(repo domain.APIKeyRepository) Service {
+	return Service{
+		repo: repo,
+	}
+}
+
+// Create will create an API key and return its plain key, the key can't be retrieved afterward
+func (s Service) Create(ctx context.Context, k *domain.APIKey) (key string, err error) {
+	if err = validateScopes(k.Scopes); err != nil {
+		return
+	}
+
+	secret, hashed, err := generateSecret()
+	if err != nil {
+		return
+	}
+
+	k.HashedSecret = hashed
+	if err = s.repo.Create(ctx, k); err != nil {
+		err = errors.Wrap(err, "failed to create an api key")
+		return
+	}
+
+	key = k.ID + "." + secret
+	return
+}
+
+// Fetch will return API keys based on filter
+func (s Service) Fetch(ctx context.Context, filter domain.APIKeyFilter) (keys []domain.APIKey, pagination domain.Pagination, err error) {
+	return s.repo.Fetch(ctx, filter)
+}
+
+// Get will return an API key
+func (s Service) Get(ctx context.Context, id string) (k domain.APIKey, err error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Rotate will replace the secret of an API key and return its new plain key, the old key stops working immediately
+func (s Service) Rotate(ctx context.Context, id string) (k domain.APIKey, key string, err error) {
+	secret, hashed, err := generateSecret()
+	if err != nil {
+		return
+	}
+
+	if err = s.repo.UpdateSecret(ctx, id, hashed); err != nil {
+		return
+	}
+
+	if k, err = s.repo.Get(ctx, id); err != nil {
+		return
+	}
+
+	key = k.ID + "." + secret
+	return
+}
+
+// Revoke will revoke an API key
+func (s Service) Revoke(ctx context.Context, id string) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	return s.repo.Revoke(ctx, id, localTime)
+}
+
+// Authenticate will verify the plain key and return a principal granted the key scopes and bound to the key tenant
+func (s Service) Authenticate(ctx context.Context, key string) (p domain.Principal, err error) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	k, err := s.repo.Lookup(ctx, parts[0])
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = domain.ErrUnauthorized
+		}
+		return
+	}
+
+	if k.IsRevoked() || subtle.ConstantTimeCompare([]byte(hashSecret(parts[1])), []byte(k.HashedSecret)) != 1 {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	now, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if k.LastUsedTime == nil || now.Sub(*k.LastUsedTime) >= touchInterval {
+		if er := s.repo.Touch(domain.NewContextWithTenant(ctx, k.TenantID), k.ID, now); er != nil {
+			logger.FromContext(ctx).Errorf("failed to update last used time of api key %s: %v", k.ID, er)
+		}
+	}
+
+	p = domain.Principal{
+		Subject:  SubjectPrefix + k.ID,
+		Scopes:   k.Scopes,
+		TenantID: k.TenantID,
+	}
+
+	return
+}
+
+func validateScopes(scopes []string) (err error) {
+	if len(scopes) == 0 {
+		return domain.ConstraintErrorf("api key must have at least one scope")
+	}
+
+	for _, scope := range scopes {
+		valid := false
+		for _, v := range domain.APIKeyScopes {
+			if scope == v {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return domain.ConstraintErrorf("unknown scope %s", scope)
+		}
+	}
+
+	return
+}
+
+func generateSecret() (secret, hashed string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+
+	secret = base64.RawURLEncoding.EncodeToString(b)
+	hashed = hashSecret(secret)
+	return
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
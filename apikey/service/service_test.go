@@ -0,0 +1,228 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/apikey/service"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreate(t *testing.T) {
+	tests := map[string]struct {
+		scopes      []string
+		apiKeyRepo  testdata.FuncCall
+		expectedErr error
+	}{
+		"success": {
+			scopes: []string{domain.ScopeEmployeesRead},
+			apiKeyRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mock.Anything},
+				Output: []interface{}{nil},
+			},
+		},
+		"with unknown scope": {
+			scopes:      []string{"salaries:read"},
+			apiKeyRepo:  testdata.FuncCall{Called: false},
+			expectedErr: domain.ConstraintErrorf("unknown scope salaries:read"),
+		},
+		"with error create an api key": {
+			scopes: []string{domain.ScopeEmployeesRead},
+			apiKeyRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mock.Anything},
+				Output: []interface{}{errors.New("unexpected error")},
+			},
+			expectedErr: errors.New("unexpected error"),
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockAPIKeyRepo := new(mocks.APIKeyRepository)
+			if tc.apiKeyRepo.Called {
+				mockAPIKeyRepo.On("Create", tc.apiKeyRepo.Input...).
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.APIKey).ID = "1"
+					}).
+					Return(tc.apiKeyRepo.Output...).Once()
+			}
+
+			k := domain.APIKey{Name: "payroll", Scopes: tc.scopes}
+			key, err := service.New(mockAPIKeyRepo).Create(context.Background(), &k)
+
+			mockAPIKeyRepo.AssertExpectations(t)
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr.Error(), errors.Cause(err).Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(key, "1."))
+			require.Equal(t, hash(strings.TrimPrefix(key, "1.")), k.HashedSecret)
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	k := domain.APIKey{ID: "1", Name: "payroll", Scopes: []string{domain.ScopeEmployeesRead}}
+
+	mockAPIKeyRepo := new(mocks.APIKeyRepository)
+	var hashed string
+	mockAPIKeyRepo.On("UpdateSecret", mock.Anything, "1", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) {
+			hashed = args.String(2)
+		}).
+		Return(nil).Once()
+	mockAPIKeyRepo.On("Get", mock.Anything, "1").Return(k, nil).Once()
+
+	res, key, err := service.New(mockAPIKeyRepo).Rotate(context.Background(), "1")
+
+	mockAPIKeyRepo.AssertExpectations(t)
+	require.NoError(t, err)
+	require.Equal(t, k, res)
+	require.Equal(t, hash(strings.TrimPrefix(key, "1.")), hashed)
+}
+
+func TestAuthenticate(t *testing.T) {
+	recently := time.Now()
+	longAgo := time.Now().Add(-time.Hour)
+	revoked := time.Now().Add(-time.Minute)
+
+	active := domain.APIKey{ID: "1", TenantID: "acme", Scopes: []string{domain.ScopeEmployeesRead}, HashedSecret: hash("secret")}
+
+	usedRecently := active
+	usedRecently.LastUsedTime = &recently
+
+	usedLongAgo := active
+	usedLongAgo.LastUsedTime = &longAgo
+
+	revokedKey := active
+	revokedKey.RevokedTime = &revoked
+
+	tests := map[string]struct {
+		key               string
+		apiKeyRepo        map[string]testdata.FuncCall
+		expectedPrincipal domain.Principal
+		expectedErr       error
+	}{
+		"success": {
+			key: "1.secret",
+			apiKeyRepo: map[string]testdata.FuncCall{
+				"Lookup": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "1"},
+					Output: []interface{}{active, nil},
+				},
+				"Touch": testdata.FuncCall{
+					Called: true,
+					Input: []interface{}{mock.MatchedBy(func(ctx context.Context) bool {
+						return domain.TenantFromContext(ctx) == "acme"
+					}), "1", mock.Anything},
+					Output: []interface{}{nil},
+				},
+			},
+			expectedPrincipal: domain.Principal{Subject: "apikey:1", Scopes: active.Scopes, TenantID: "acme"},
+		},
+		"used recently is not touched": {
+			key: "1.secret",
+			apiKeyRepo: map[string]testdata.FuncCall{
+				"Lookup": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "1"},
+					Output: []interface{}{usedRecently, nil},
+				},
+			},
+			expectedPrincipal: domain.Principal{Subject: "apikey:1", Scopes: active.Scopes, TenantID: "acme"},
+		},
+		"with error touch is ignored": {
+			key: "1.secret",
+			apiKeyRepo: map[string]testdata.FuncCall{
+				"Lookup": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "1"},
+					Output: []interface{}{usedLongAgo, nil},
+				},
+				"Touch": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "1", mock.Anything},
+					Output: []interface{}{errors.New("unexpected error")},
+				},
+			},
+			expectedPrincipal: domain.Principal{Subject: "apikey:1", Scopes: active.Scopes, TenantID: "acme"},
+		},
+		"malformed key": {
+			key:         "secret",
+			apiKeyRepo:  map[string]testdata.FuncCall{},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"unknown key": {
+			key: "2.secret",
+			apiKeyRepo: map[string]testdata.FuncCall{
+				"Lookup": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "2"},
+					Output: []interface{}{domain.APIKey{}, domain.ErrNotFound},
+				},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"wrong secret": {
+			key: "1.wrong",
+			apiKeyRepo: map[string]testdata.FuncCall{
+				"Lookup": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "1"},
+					Output: []interface{}{active, nil},
+				},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"revoked key": {
+			key: "1.secret",
+			apiKeyRepo: map[string]testdata.FuncCall{
+				"Lookup": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "1"},
+					Output: []interface{}{revokedKey, nil},
+				},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockAPIKeyRepo := new(mocks.APIKeyRepository)
+			for name, fn := range tc.apiKeyRepo {
+				if fn.Called {
+					mockAPIKeyRepo.On(name, fn.Input...).Return(fn.Output...).Once()
+				}
+			}
+
+			p, err := service.New(mockAPIKeyRepo).Authenticate(context.Background(), tc.key)
+
+			mockAPIKeyRepo.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, errors.Cause(err))
+			require.Equal(t, tc.expectedPrincipal, p)
+		})
+	}
+}
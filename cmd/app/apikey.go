@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage API keys of machine clients",
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key, the key is only printed once",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		scopes, _ := cmd.Flags().GetStringSlice("scopes")
+		tenant, _ := cmd.Flags().GetString("tenant")
+
+		ctx := domain.NewContextWithTenant(context.Background(), tenant)
+		k := domain.APIKey{Name: name, Scopes: scopes}
+		key, err := apiKeysService.Create(ctx, &k)
+		if err != nil {
+			logger.L().Fatalf("can't create api key, err: %v", err)
+		}
+
+		fmt.Printf("id:  %s\nkey: %s\n", k.ID, key)
+	},
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		withRevoked, _ := cmd.Flags().GetBool("revoked")
+		tenant, _ := cmd.Flags().GetString("tenant")
+
+		ctx := domain.NewContextWithTenant(context.Background(), tenant)
+		keys, _, err := apiKeysService.Fetch(ctx, domain.APIKeyFilter{WithRevoked: withRevoked})
+		if err != nil {
+			logger.L().Fatalf("can't list api keys, err: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSCOPES\tLAST USED\tREVOKED")
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, strings.Join(k.Scopes, ","), formatTime(k.LastUsedTime), formatTime(k.RevokedTime))
+		}
+		w.Flush()
+	},
+}
+
+var apiKeyRotateCmd = &cobra.Command{
+	Use:   "rotate <id>",
+	Short: "Replace the secret of an API key, the old key stops working immediately",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tenant, _ := cmd.Flags().GetString("tenant")
+
+		ctx := domain.NewContextWithTenant(context.Background(), tenant)
+		_, key, err := apiKeysService.Rotate(ctx, args[0])
+		if err != nil {
+			logger.L().Fatalf("can't rotate api key %s, err: %v", args[0], err)
+		}
+
+		fmt.Printf("key: %s\n", key)
+	},
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tenant, _ := cmd.Flags().GetString("tenant")
+
+		ctx := domain.NewContextWithTenant(context.Background(), tenant)
+		if err := apiKeysService.Revoke(ctx, args[0]); err != nil {
+			logger.L().Fatalf("can't revoke api key %s, err: %v", args[0], err)
+		}
+	},
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func init() {
+	apiKeyCreateCmd.Flags().String("name", "", "name of the client owning the key")
+	apiKeyCreateCmd.Flags().StringSlice("scopes", nil, "comma separated scopes: "+strings.Join(domain.APIKeyScopes, ","))
+	apiKeyCreateCmd.MarkFlagRequired("name")
+	apiKeyCreateCmd.MarkFlagRequired("scopes")
+
+	apiKeyListCmd.Flags().Bool("revoked", false, "include revoked keys")
+
+	apiKeyCmd.PersistentFlags().String("tenant", domain.DefaultTenantID, "tenant the api keys belong to")
+
+	apiKeyCmd.AddCommand(apiKeyCreateCmd, apiKeyListCmd, apiKeyRotateCmd, apiKeyRevokeCmd)
+	rootCmd.AddCommand(apiKeyCmd)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+	// the config commands don't connect to the dependencies
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the effective configuration with the secrets redacted, the problems are reported after it",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := loadConfig(cmd)
+
+		if er := cfg.Print(os.Stdout); er != nil {
+			logger.L().Fatalf("can't print config, err: %v", er)
+		}
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPrintCmd)
+	rootCmd.AddCommand(configCmd)
+}
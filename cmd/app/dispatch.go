@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/outbox"
+	"github.com/milhamhidayat/golang-clean-code-v2/outbox/publisher"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/lifecycle"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/webhook/dispatcher"
+)
+
+var dispatchCmd = &cobra.Command{
+	Use:   "dispatch",
+	Short: "Publish the domain events of the outbox and send the webhook deliveries",
+	Run: func(cmd *cobra.Command, args []string) {
+		once, _ := cmd.Flags().GetBool("once")
+
+		pub, err := newPublisher()
+		if err != nil {
+			logger.L().Fatalf("can't create outbox publisher, err: %v", err)
+		}
+		outboxDispatcher := outbox.NewDispatcher(outboxRepository, pub, cfg.Outbox.BatchSize, cfg.Outbox.MaxAttempts)
+		sender := dispatcher.NewSender(webhookRepository, deliveryRepository, dispatcher.NewClient(cfg.Webhook.Timeout, cfg.Webhook.AllowPrivateNetworks),
+			dispatcher.SenderConfig{
+				BatchSize:   cfg.Webhook.BatchSize,
+				MaxAttempts: cfg.Webhook.MaxAttempts,
+				Backoff:     cfg.Webhook.Backoff,
+				MaxBackoff:  cfg.Webhook.MaxBackoff,
+			})
+
+		if once {
+			published, err := outboxDispatcher.Drain(context.Background())
+			logger.L().Infof("published %d outbox events", published)
+			if err != nil {
+				logger.L().Fatalf("%v", err)
+			}
+
+			attempts, err := sender.Send(context.Background())
+			logger.L().Infof("attempted %d webhook deliveries", attempts)
+			if err != nil {
+				logger.L().Fatalf("%v", err)
+			}
+			return
+		}
+
+		app.Append(lifecycle.Every("outbox dispatcher", cfg.Outbox.Interval, func(ctx context.Context) {
+			published, err := outboxDispatcher.Drain(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.L().Errorf("%v", err)
+			}
+			if published > 0 {
+				logger.L().Debugf("published %d outbox events", published)
+			}
+		}))
+
+		app.Append(lifecycle.Every("webhook sender", cfg.Webhook.Interval, func(ctx context.Context) {
+			attempts, err := sender.Send(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.L().Errorf("failed to send webhook deliveries: %v", err)
+			}
+			if attempts > 0 {
+				logger.L().Debugf("attempted %d webhook deliveries", attempts)
+			}
+		}))
+
+		logger.L().Infof("Dispatching outbox events every %s", cfg.Outbox.Interval)
+		if err := app.Run(context.Background()); err != nil {
+			logger.L().Fatalf("%v", err)
+		}
+	},
+}
+
+// newPublisher creates the publisher of outbox.publisher followed by the webhooks, the file is closed when the app stops
+func newPublisher() (pub domain.Publisher, err error) {
+	webhooks := dispatcher.NewPublisher(webhookRepository, deliveryRepository)
+
+	switch cfg.Outbox.Publisher {
+	case "none":
+		return webhooks, nil
+	case "file":
+		w, closeFile, err := publisher.NewFile(cfg.Outbox.File)
+		if err != nil {
+			return nil, err
+		}
+		app.Append(lifecycle.Hook{
+			Name: "outbox file",
+			OnStop: func(ctx context.Context) error {
+				return closeFile()
+			},
+		})
+		return publisher.Multi{w, webhooks}, nil
+	default:
+		return publisher.Multi{publisher.NewWriter(os.Stdout), webhooks}, nil
+	}
+}
+
+func init() {
+	dispatchCmd.Flags().Bool("once", false, "publish the pending events and exit")
+	rootCmd.AddCommand(dispatchCmd)
+}
@@ -1,48 +1,194 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/friendsofgo/errors"
 	"github.com/labstack/echo/v4"
-	"github.com/rs/zerolog/log"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 
+	apiKeyHandler "github.com/milhamhidayat/golang-clean-code-v2/apikey/delivery/http"
+	apiKeyService "github.com/milhamhidayat/golang-clean-code-v2/apikey/service"
 	departmentHandler "github.com/milhamhidayat/golang-clean-code-v2/department/delivery/http"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	employeeHandler "github.com/milhamhidayat/golang-clean-code-v2/employee/delivery/http"
+	eventHandler "github.com/milhamhidayat/golang-clean-code-v2/event/delivery/http"
+	importHandler "github.com/milhamhidayat/golang-clean-code-v2/imports/delivery/http"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/health"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/lifecycle"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/metrics"
 	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/tracing"
+	userHandler "github.com/milhamhidayat/golang-clean-code-v2/user/delivery/http"
+	webhookHandler "github.com/milhamhidayat/golang-clean-code-v2/webhook/delivery/http"
+	webhookService "github.com/milhamhidayat/golang-clean-code-v2/webhook/service"
 )
 
-const address = ":8500"
+const (
+	address      = ":8500"
+	debugAddress = ":6060"
+)
+
+// streamPaths are long lived and aren't bounded by the request timeout
+var streamPaths = map[string]bool{
+	"/events": true,
+}
+
+// publicPaths are served without authentication and rate limiting
+var publicPaths = map[string]bool{
+	"/ping":    true,
+	"/livez":   true,
+	"/readyz":  true,
+	"/healthz": true,
+}
 
 var serverCmd = &cobra.Command{
 	Use:   "http",
 	Short: "Start http server",
 	Run: func(cmd *cobra.Command, args []string) {
 		e := echo.New()
+		e.HideBanner = true
+		e.HTTPErrorHandler = middleware.ProblemHandler
+		e.Use(middleware.ClientIP(ipExtractor))
+		e.Use(middleware.RequestID())
+
+		httpMetrics, err := metrics.NewHTTP(metricsRegistry)
+		if err != nil {
+			logger.L().Fatalf("can't register http metrics, err: %v", err)
+		}
+		e.Use(httpMetrics.Middleware())
+		e.Use(tracing.Middleware("employee"))
 		e.Use(middleware.ErrorMiddleware())
+		e.Use(middleware.Timeout(middleware.TimeoutConfig{
+			Default: cfg.Timeout.Request(),
+			Routes:  routeTimeouts,
+			Skipper: func(c echo.Context) bool {
+				return streamPaths[c.Request().URL.Path]
+			},
+		}))
+		rateLimitStore := ratelimit.NewMemoryStore()
+		e.Use(middleware.AuthFailureLimit(middleware.AuthFailureLimitConfig{
+			Store: rateLimitStore,
+			Limit: authFailureLimit,
+			Skipper: func(c echo.Context) bool {
+				return publicPaths[c.Request().URL.Path]
+			},
+		}))
+		e.Use(middleware.Authentication(bearerVerifier, apiKeysService, func(c echo.Context) bool {
+			path := c.Request().URL.Path
+			return publicPaths[path] || (strings.HasPrefix(path, "/auth/") && path != "/auth/logout")
+		}))
+		e.Use(middleware.Tenant(middleware.TenantConfig{Default: cfg.Tenant.Default}))
+		e.Use(middleware.RateLimit(middleware.RateLimitConfig{
+			Store:   rateLimitStore,
+			Default: rateLimit,
+			Routes:  routeRateLimits,
+			Skipper: func(c echo.Context) bool {
+				return publicPaths[c.Request().URL.Path]
+			},
+		}))
+		e.Use(middleware.Idempotency(middleware.IdempotencyConfig{
+			Repository:     idempotencyRepository,
+			TTL:            cfg.Idempotency.TTL,
+			PendingTimeout: cfg.Idempotency.PendingTimeout,
+			MaxBodySize:    cfg.Idempotency.MaxBodySize(),
+			// the issued tokens mustn't be stored and replayed, e.g. after the refresh token is revoked
+			Skipper: func(c echo.Context) bool {
+				return strings.HasPrefix(c.Request().URL.Path, "/auth/")
+			},
+		}))
 
 		e.GET("ping", func(c echo.Context) error {
 			return c.JSON(http.StatusOK, "pong")
 		})
+		e.GET("livez", health.LiveHandler())
+		e.GET("readyz", healthChecker.Handler())
+		e.GET("healthz", healthChecker.Handler())
 
 		departmentHandler.AddDepartmentHandler(e, departmentService)
+		employeeHandler.AddEmployeeHandler(e, employeeService)
+		apiKeyHandler.AddAPIKeyHandler(e, apiKeyService.NewAuthorization(apiKeysService))
+		userHandler.AddUserHandler(e, userService)
+		webhookHandler.AddWebhookHandler(e, webhookService.NewAuthorization(webhooksService))
+		importHandler.AddImportHandler(e, importsService, cfg.Import.MaxSize())
+		if authService != nil {
+			userHandler.AddAuthHandler(e, authService)
+		}
+
+		// the streams are ended on shutdown, otherwise they would hold the server until the shutdown timeout
+		streamsDone := make(chan struct{})
+		e.Server.RegisterOnShutdown(func() { close(streamsDone) })
+		eventHandler.AddEventHandler(e, eventService, eventHandler.Config{
+			PollInterval: cfg.Events.PollInterval,
+			Heartbeat:    cfg.Events.Heartbeat,
+			Done:         streamsDone,
+		})
+
+		app.Append(lifecycle.Every("idempotency sweeper", cfg.Idempotency.SweepInterval, func(ctx context.Context) {
+			count, err := idempotencyRepository.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				logger.L().Errorf("failed to delete expired idempotency keys: %v", err)
+				return
+			}
+			logger.L().Debugf("deleted %d expired idempotency keys", count)
+		}))
 
-		errCh := make(chan error)
+		app.Append(lifecycle.Every("import worker", cfg.Import.Interval, func(ctx context.Context) {
+			for ctx.Err() == nil {
+				imp, err := importRunner.Process(ctx)
+				if errors.Is(err, domain.ErrNotFound) {
+					return
+				}
+				if err != nil {
+					logger.L().Errorf("failed to run an import: %v", err)
+					return
+				}
+				logger.L().Infof("import %s of %s %s: %d created, %d updated, %d errors",
+					imp.ID, imp.Kind, imp.Status, imp.Report.Created, imp.Report.Updated, len(imp.Report.Errors))
+			}
+		}))
 
-		go func(ch chan error) {
-			log.Info().Msgf("Starting HTTP server at: %s", address)
-			errCh <- e.Start(address)
-		}(errCh)
+		app.Append(serverHook("http server", e.Server, func() error {
+			logger.L().Infof("Starting HTTP server at: %s", address)
+			return e.Start(address)
+		}))
 
-		go func(ch chan error) {
-			errCh <- http.ListenAndServe(":6060", nil)
-		}(errCh)
+		// the metrics expose the routes and the traffic, so they are only served on the internal debug listener
+		debugMux := http.NewServeMux()
+		debugMux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+		debugMux.Handle("/", http.DefaultServeMux)
 
-		for {
-			log.Fatal().Err(<-errCh)
+		debugServer := &http.Server{Addr: debugAddress, Handler: debugMux}
+		app.Append(serverHook("debug server", debugServer, debugServer.ListenAndServe))
+
+		if err := app.Run(context.Background()); err != nil {
+			logger.L().Fatalf("%v", err)
 		}
 	},
 }
 
+// serverHook starts the server in a goroutine and drains its in-flight requests on stop
+func serverHook(name string, server *http.Server, start func() error) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				if err := start(); err != nil && err != http.ErrServerClosed {
+					app.Fail(errors.Wrapf(err, "%s failed", name))
+				}
+			}()
+			return nil
+		},
+		OnStop: server.Shutdown,
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(serverCmd)
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	impService "github.com/milhamhidayat/golang-clean-code-v2/imports/service"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <departments|employees> <file>",
+	Short: "Import departments or employees from a CSV, nothing is written when a row is not valid",
+	Long: "Import departments or employees from a CSV whose header names the columns.\n\n" +
+		"departments: " + strings.Join(impService.DepartmentColumns, ", ") + "\n" +
+		"employees:   " + strings.Join(impService.EmployeeColumns, ", ") + "\n\n" +
+		"A row with an id updates the existing department or employee, a department without id updates the department of the same name. " +
+		"An employee is assigned to its department by name or by department_id.",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		tenant, _ := cmd.Flags().GetString("tenant")
+
+		f, err := os.Open(args[1])
+		if err != nil {
+			logger.L().Fatalf("can't open %s, err: %v", args[1], err)
+		}
+		defer f.Close()
+
+		ctx := domain.NewContextWithTenant(context.Background(), tenant)
+		report, err := importRunner.Run(ctx, args[0], f, dryRun)
+		printReport(report, dryRun)
+		if err != nil {
+			logger.L().Fatalf("can't import %s, err: %v", args[0], err)
+		}
+
+		if len(report.Errors) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func printReport(report domain.ImportReport, dryRun bool) {
+	verb := "imported"
+	if dryRun {
+		verb = "would import"
+	}
+	fmt.Printf("%d rows, %s %d created, %d updated\n", report.Rows, verb, report.Created, report.Updated)
+
+	if len(report.Errors) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tFIELD\tERROR")
+	for _, e := range report.Errors {
+		field := e.Field
+		if field == "" {
+			field = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", e.Row, field, e.Message)
+	}
+	w.Flush()
+}
+
+func init() {
+	importCmd.Flags().Bool("dry-run", false, "validate the rows and report what would be written without writing")
+	importCmd.Flags().String("tenant", domain.DefaultTenantID, "tenant the rows are imported to")
+
+	rootCmd.AddCommand(importCmd)
+}
@@ -1,87 +1,458 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"net"
 	"os"
-	"strconv"
+	"strings"
 	"time"
 
-	log "github.com/sirupsen/logrus"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 
+	apiKeyRepo "github.com/milhamhidayat/golang-clean-code-v2/apikey/repository/mariadb"
+	apiKeyService "github.com/milhamhidayat/golang-clean-code-v2/apikey/service"
+	deptRepository "github.com/milhamhidayat/golang-clean-code-v2/department/repository"
 	deptRepo "github.com/milhamhidayat/golang-clean-code-v2/department/repository/mariadb"
 	deptService "github.com/milhamhidayat/golang-clean-code-v2/department/service"
 	"github.com/milhamhidayat/golang-clean-code-v2/domain"
-	"github.com/milhamhidayat/golang-clean-code-v2/pkg/env"
+	empRepository "github.com/milhamhidayat/golang-clean-code-v2/employee/repository"
+	empRepo "github.com/milhamhidayat/golang-clean-code-v2/employee/repository/mariadb"
+	empService "github.com/milhamhidayat/golang-clean-code-v2/employee/service"
+	evtService "github.com/milhamhidayat/golang-clean-code-v2/event/service"
+	idempotencyRepo "github.com/milhamhidayat/golang-clean-code-v2/idempotency/repository/mariadb"
+	importRepo "github.com/milhamhidayat/golang-clean-code-v2/imports/repository/mariadb"
+	impService "github.com/milhamhidayat/golang-clean-code-v2/imports/service"
+	outboxRepo "github.com/milhamhidayat/golang-clean-code-v2/outbox/repository/mariadb"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/config"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/deadline"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/health"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/lifecycle"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/metrics"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/oidc"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/tracing"
+	userRepo "github.com/milhamhidayat/golang-clean-code-v2/user/repository/mariadb"
+	usrService "github.com/milhamhidayat/golang-clean-code-v2/user/service"
+	webhookRepo "github.com/milhamhidayat/golang-clean-code-v2/webhook/repository/mariadb"
+	webhookService "github.com/milhamhidayat/golang-clean-code-v2/webhook/service"
 )
 
 var (
-	departmentRepository domain.DepartmentRepository
-	departmentService    domain.DepartmentService
+	cfg                   config.Config
+	departmentRepository  domain.DepartmentRepository
+	departmentService     domain.DepartmentService
+	employeeRepository    domain.EmployeeRepository
+	employeeService       domain.EmployeeService
+	apiKeyRepository      domain.APIKeyRepository
+	apiKeysService        domain.APIKeyService
+	userRepository        domain.UserRepository
+	idempotencyRepository domain.IdempotencyRepository
+	outboxRepository      outboxRepo.Repository
+	webhookRepository     domain.WebhookRepository
+	deliveryRepository    domain.WebhookDeliveryRepository
+	webhooksService       domain.WebhookService
+	eventService          domain.EventService
+	importRunner          impService.Service
+	importsService        domain.ImportService
+	userService           domain.UserService
+	authService           domain.AuthService
+	tokenVerifier         *jwtauth.Verifier
+	bearerVerifier        jwtauth.TokenVerifier
+	rateLimit             ratelimit.Limit
+	authFailureLimit      ratelimit.Limit
+	ipExtractor           middleware.IPExtractor
+	routeRateLimits       map[string]ratelimit.Limit
+	routeTimeouts         map[string]time.Duration
+	methodTimeouts        map[string]time.Duration
+	metricsRegistry       *prometheus.Registry
+	healthChecker         *health.Health
+	app                   *lifecycle.Lifecycle
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "employee",
 	Short: "Employee is an API for managing employees",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initApp(cmd)
+	},
 }
 
 func init() {
-	cobra.OnInitialize(initApp)
+	rootCmd.PersistentFlags().String("config", "", "YAML or TOML config file (env CONFIG_FILE)")
+	config.BindFlags(rootCmd.PersistentFlags())
 }
 
 // Execute the main function
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
-		os.Exit(1)
+	err := rootCmd.Execute()
+
+	// the commands which don't run the lifecycle still close the db and flush the pending spans
+	if app != nil {
+		if er := app.Stop(context.Background()); er != nil {
+			logger.L().Errorf("%v", er)
+		}
+	}
+
+	if err != nil {
+		logger.L().Fatalf("%v", err)
 	}
 }
 
-func initApp() {
+// loadConfig loads the config from the file of --config or CONFIG_FILE, the environment and the flags
+func loadConfig(cmd *cobra.Command) (err error) {
+	file, _ := cmd.Flags().GetString("config")
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+
+	cfg, err = config.Load(file, cmd.Flags())
+	return
+}
+
+func initApp(cmd *cobra.Command) {
+	/**
+	 * Config
+	 */
+	err := loadConfig(cmd)
+	if err != nil {
+		logger.L().Fatalf("%v", err)
+	}
+
+	/**
+	 * Logger
+	 */
+	err = logger.Configure(logger.Config{
+		Level:  cfg.Log.Level,
+		Format: cfg.Log.Format,
+	})
+	if err != nil {
+		logger.L().Fatalf("can't configure logger, err: %v", err)
+	}
+
+	/**
+	 * Timezone
+	 */
+	ntime.Location, err = time.LoadLocation(cfg.Server.Timezone)
+	if err != nil {
+		logger.L().Fatalf("can't load timezone, err: %v", err)
+	}
+
+	/**
+	 * Lifecycle
+	 */
+	app = lifecycle.New(cfg.Server.ShutdownTimeout)
+
+	/**
+	 * Tracing
+	 */
+	shutdownTracing, err := tracing.Init(tracing.Config{
+		ServiceName: "employee",
+		Exporter:    cfg.Tracing.Exporter,
+		File:        cfg.Tracing.File,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logger.L().Fatalf("can't init tracing, err: %v", err)
+	}
+	app.Append(lifecycle.Hook{Name: "tracing", OnStop: shutdownTracing})
+
 	/**
 	 * MYSQL Conf
 	 */
-	dsnMysql := env.Get("MYSQL_URI")
-	db, err := sql.Open("mysql", dsnMysql)
+	// the dsn isn't logged as it holds the password
+	db, err := sql.Open("mysql", cfg.MySQL.URI)
 	if err != nil {
-		log.Fatal("can't open mysql connection to: %s, got err: %v", dsnMysql, err)
+		logger.L().Fatalf("can't open mysql connection, got err: %v", err)
 	}
 
 	err = db.Ping()
 	if err != nil {
-		log.Fatal("can't connect to mysql db, err: %v", err)
+		logger.L().Fatalf("can't connect to mysql db, err: %v", err)
 	}
 
-	mysqlMaxIdleCon, err := strconv.Atoi(env.Get("MYSQL_MAX_IDLE_CONNECTION"))
+	db.SetMaxIdleConns(cfg.MySQL.MaxIdleConnection)
+	db.SetMaxOpenConns(cfg.MySQL.MaxOpenConnection)
+	db.SetConnMaxLifetime(cfg.MySQL.ConnectionLifetime())
+
+	// the db is closed after the components using it are stopped
+	app.Append(lifecycle.Hook{
+		Name: "mariadb",
+		OnStop: func(ctx context.Context) error {
+			return db.Close()
+		},
+	})
+
+	/**
+	 * Metrics
+	 */
+	metricsRegistry = metrics.NewRegistry()
+	metricsRegistry.MustRegister(metrics.NewDBStats(db, "employee"))
+
+	repositoryMetrics, err := metrics.NewRepository(metricsRegistry)
 	if err != nil {
-		log.Fatal("MYSQL_MAX_IDLE_CONNECTION is not well-set")
+		logger.L().Fatalf("can't register repository metrics, err: %v", err)
+	}
+
+	/**
+	 * Health
+	 */
+	healthChecker = health.New(cfg.Health.CheckTimeout)
+	healthChecker.Register("mariadb", health.DBChecker(db))
+
+	migrationsPath := cfg.Health.MigrationsPath
+
+	// the migrations check is skipped when the migrations are not shipped with the binary
+	if version, err := health.LatestMigrationVersion(migrationsPath); err == nil {
+		healthChecker.Register("migrations", health.MigrationChecker(db, version))
+	} else {
+		logger.L().Warnf("can't read migrations from %s, the migrations check is disabled, err: %v", migrationsPath, err)
 	}
-	db.SetMaxIdleConns(mysqlMaxIdleCon)
 
-	mysqlMaxOpenCon, err := strconv.Atoi(env.Get("MYSQL_MAX_OPEN_CONNECTION"))
+	/**
+	 * Context Timeout
+	 */
+	routeTimeouts, err = parseTimeouts(cfg.Timeout.Routes)
 	if err != nil {
-		log.Fatal("MYSQL_MAX_OPEN_CONNECTION is not well-set")
+		logger.L().Fatalf("can't parse timeout.routes, err: %v", err)
 	}
-	db.SetMaxOpenConns(mysqlMaxOpenCon)
 
-	mysqlMaxConnLifetime, err := strconv.Atoi(env.Get("MYSQL_CONNECTION_LIFETIME_M"))
+	methodTimeouts, err = parseTimeouts(cfg.Timeout.Methods)
 	if err != nil {
-		log.Fatal("MYSQL_CONNECTION_LIFETIME_M is not well-set")
+		logger.L().Fatalf("can't parse timeout.methods, err: %v", err)
 	}
-	db.SetConnMaxLifetime(time.Minute * time.Duration(mysqlMaxConnLifetime))
 
 	/**
-	 * Context Timeout
+	 * Authentication
 	 */
-	// t, err := strconv.ParseInt(env.Get("CONTEXT_TIMEOUT_MS"), 10, 16)
-	// if err != nil {
-	// 	log.Fatal("CONTEXT_TIMEOUT_MS is not well-set")
-	// }
-	// contextTimeout := time.Duration(t) * time.Millisecond
+	jwtConfig := jwtauth.Config{
+		HMACSecret:       cfg.JWT.HMACSecret,
+		RSAPublicKeyFile: cfg.JWT.RSAPublicKeyFile,
+		JWKSFile:         cfg.JWT.JWKSFile,
+		Issuer:           cfg.JWT.Issuer,
+		Audience:         cfg.JWT.Audience,
+	}
+	oidcIssuer := cfg.OIDC.Issuer
+
+	// jwt keys are optional when the tokens are issued by the oidc provider only
+	tokenVerifier, err = jwtauth.New(jwtConfig)
+	if err != nil && !(err == jwtauth.ErrNoKey && oidcIssuer != "") {
+		logger.L().Fatalf("can't create jwt verifier, err: %v", err)
+	}
 
 	/**
 	 * Department
 	 */
-	departmentRepository = deptRepo.New(db)
-	departmentService = deptService.New(departmentRepository)
+	departmentRepository = deptRepository.NewMetrics(deptRepo.New(db), repositoryMetrics)
+	departmentService = deptService.NewTracing(deptService.NewTimeout(
+		deptService.NewAuthorization(deptService.New(departmentRepository)), serviceTimeouts("department")))
+
+	/**
+	 * Employee
+	 */
+	employeeRepository = empRepository.NewMetrics(empRepo.New(db), repositoryMetrics)
+	employeeService = empService.NewTracing(empService.NewTimeout(
+		empService.NewAuthorization(empService.New(departmentRepository, employeeRepository, departmentService), employeeRepository), serviceTimeouts("employee")))
+
+	/**
+	 * API Key
+	 */
+	apiKeyRepository = apiKeyRepo.New(db)
+	apiKeysService = apiKeyService.New(apiKeyRepository)
+
+	/**
+	 * User
+	 */
+	userRepository = userRepo.New(db)
+	userService = usrService.NewAuthorization(usrService.New(userRepository, employeeRepository))
+
+	// tokens are only issued locally when they can be signed with the shared secret
+	if signer, err := jwtauth.NewSigner(jwtConfig); err == nil {
+		authService = usrService.NewAuth(userRepository, employeeRepository, userRepo.NewTokenRepository(db),
+			signer, tokenVerifier, usrService.LogNotifier{})
+	} else {
+		logger.L().Warnf("JWT_HMAC_SECRET is not set, login of local users is disabled")
+	}
+
+	/**
+	 * Idempotency
+	 */
+	idempotencyRepository = idempotencyRepo.New(db)
+
+	/**
+	 * Outbox
+	 */
+	outboxRepository = outboxRepo.New(db, cfg.Events.CommitLag)
+
+	/**
+	 * Event
+	 */
+	eventService = evtService.NewAuthorization(evtService.New(outboxRepository))
+
+	/**
+	 * Webhook
+	 */
+	webhookRepository = webhookRepo.New(db)
+	deliveryRepository = webhookRepo.NewDeliveryRepository(db)
+	webhooksService = webhookService.New(webhookRepository, deliveryRepository, cfg.Webhook.AllowPrivateNetworks)
+
+	/**
+	 * Import
+	 */
+	importRunner = impService.New(importRepo.New(db), departmentRepository, employeeRepository, cfg.Import.BatchSize, cfg.Import.RunTimeout)
+	importsService = impService.NewAuthorization(importRunner)
+
+	/**
+	 * Bearer Token
+	 */
+	// local users tokens are verified by the auth service so revoked tokens are rejected
+	var fallback jwtauth.TokenVerifier
+	switch {
+	case authService != nil:
+		fallback = authService
+	case tokenVerifier != nil:
+		fallback = tokenVerifier
+	}
+	router := jwtauth.NewRouter(fallback)
+
+	if oidcIssuer != "" {
+		oidcVerifier, err := oidc.New(oidc.Config{
+			Issuer:          oidcIssuer,
+			DiscoveryURL:    cfg.OIDC.DiscoveryURL,
+			JWKSURL:         cfg.OIDC.JWKSURL,
+			Audience:        cfg.OIDC.Audience,
+			RolesClaim:      cfg.OIDC.RolesClaim,
+			RoleMapping:     parseRoleMapping(cfg.OIDC.RoleMapping),
+			EmployeeIDClaim: cfg.OIDC.EmployeeIDClaim,
+			TenantIDClaim:   cfg.OIDC.TenantIDClaim,
+		})
+		if err != nil {
+			logger.L().Fatalf("can't create oidc verifier, err: %v", err)
+		}
+		router.Handle(oidcIssuer, oidcVerifier)
+	}
+	bearerVerifier = router
+
+	/**
+	 * Rate Limit
+	 */
+	rateLimit, err = ratelimit.ParseLimit(cfg.RateLimit.Default)
+	if err != nil {
+		logger.L().Fatalf("can't parse rate_limit.default, err: %v", err)
+	}
+
+	routeRateLimits, err = parseRouteRateLimits(cfg.RateLimit.Routes)
+	if err != nil {
+		logger.L().Fatalf("can't parse rate_limit.routes, err: %v", err)
+	}
+
+	authFailureLimit, err = ratelimit.ParseLimit(cfg.RateLimit.AuthFailures)
+	if err != nil {
+		logger.L().Fatalf("can't parse rate_limit.auth_failures, err: %v", err)
+	}
+
+	/**
+	 * Client IP
+	 */
+	// X-Forwarded-For is only trusted from the proxies, otherwise a client could pick its own rate limit bucket
+	trustedProxies, err := parseCIDRs(cfg.Server.TrustedProxies)
+	if err != nil {
+		logger.L().Fatalf("can't parse server.trusted_proxies, err: %v", err)
+	}
+
+	ipExtractor = middleware.ExtractIPDirect()
+	if len(trustedProxies) > 0 {
+		ipExtractor = middleware.ExtractIPFromXFFHeader(trustedProxies...)
+	}
+}
+
+// parseCIDRs parses comma separated CIDRs or IPs, e.g. 10.0.0.0/8,192.168.1.1
+func parseCIDRs(raw string) (nets []*net.IPNet, err error) {
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		if !strings.Contains(s, "/") {
+			if ip := net.ParseIP(s); ip != nil && ip.To4() != nil {
+				s += "/32"
+			} else {
+				s += "/128"
+			}
+		}
+
+		var n *net.IPNet
+		if _, n, err = net.ParseCIDR(s); err != nil {
+			return
+		}
+		nets = append(nets, n)
+	}
+
+	return
+}
+
+// parseRouteRateLimits parses comma separated <method> <path>=<limit> pairs, e.g. POST /departments=10/1m
+func parseRouteRateLimits(raw string) (limits map[string]ratelimit.Limit, err error) {
+	limits = map[string]ratelimit.Limit{}
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+
+		if limits[parts[0]], err = ratelimit.ParseLimit(parts[1]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// parseTimeouts parses comma separated <key>=<duration> pairs, e.g. GET /employees=5s or employee.Fetch=5s
+func parseTimeouts(raw string) (timeouts map[string]time.Duration, err error) {
+	timeouts = map[string]time.Duration{}
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+
+		if timeouts[parts[0]], err = time.ParseDuration(parts[1]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// serviceTimeouts returns the deadlines of the methods of the service, the configured ones are keyed by <service>.<method>
+func serviceTimeouts(service string) deadline.Timeouts {
+	timeouts := deadline.Timeouts{Default: cfg.Timeout.Service(), Methods: map[string]time.Duration{}}
+	for key, timeout := range methodTimeouts {
+		if strings.HasPrefix(key, service+".") {
+			timeouts.Methods[strings.TrimPrefix(key, service+".")] = timeout
+		}
+	}
+
+	return timeouts
+}
+
+// parseRoleMapping parses comma separated <claim value>:<role> pairs, e.g. hr:hr-admin,staff:viewer
+func parseRoleMapping(raw string) (mapping map[string]domain.Role) {
+	mapping = map[string]domain.Role{}
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		mapping[parts[0]] = domain.Role(parts[1])
+	}
+
+	return
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
+	usrService "github.com/milhamhidayat/golang-clean-code-v2/user/service"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage users of the local identity provider",
+}
+
+var userCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a user of an employee, it is used to create the first admin",
+	Run: func(cmd *cobra.Command, args []string) {
+		email, _ := cmd.Flags().GetString("email")
+		employeeID, _ := cmd.Flags().GetString("employee-id")
+		password, _ := cmd.Flags().GetString("password")
+		roles, _ := cmd.Flags().GetStringSlice("roles")
+		tenant, _ := cmd.Flags().GetString("tenant")
+
+		u := domain.User{
+			EmployeeID: employeeID,
+			Email:      email,
+			Password:   password,
+		}
+		for _, role := range roles {
+			u.Roles = append(u.Roles, domain.Role(role))
+		}
+
+		if err := validator.Validate(u); err != nil {
+			logger.L().Fatalf("%v", err)
+		}
+
+		// the command is run by an operator, so the user service is not wrapped by authorization
+		ctx := domain.NewContextWithTenant(context.Background(), tenant)
+		if err := usrService.New(userRepository, employeeRepository).Create(ctx, &u); err != nil {
+			logger.L().Fatalf("can't create user, err: %v", err)
+		}
+
+		fmt.Printf("id: %s\n", u.ID)
+	},
+}
+
+func init() {
+	userCreateCmd.Flags().String("email", "", "email used to login")
+	userCreateCmd.Flags().String("employee-id", "", "id of the employee owning the user")
+	userCreateCmd.Flags().String("password", "", "password of at least 8 characters")
+	userCreateCmd.Flags().StringSlice("roles", nil, "comma separated roles: viewer,hr-admin,department-head,admin")
+	userCreateCmd.Flags().String("tenant", domain.DefaultTenantID, "tenant the user and its employee belong to")
+	userCreateCmd.MarkFlagRequired("email")
+	userCreateCmd.MarkFlagRequired("employee-id")
+	userCreateCmd.MarkFlagRequired("password")
+	userCreateCmd.MarkFlagRequired("roles")
+
+	userCmd.AddCommand(userCreateCmd)
+	rootCmd.AddCommand(userCmd)
+}
@@ -11,7 +11,12 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	pkgCursor "github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/fieldset"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
 	"github.com/milhamhidayat/golang-clean-code-v2/pkg/md5"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/render"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/timerange"
 	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
 )
 
@@ -39,11 +44,11 @@ func (h departmentHandler) Insert(c echo.Context) error {
 
 	var department domain.Department
 	if err := c.Bind(&department); err != nil {
-		return c.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	if err := validator.Validate(department); err != nil {
-		return c.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	err := h.service.Create(ctx, &department)
@@ -58,12 +63,22 @@ func (h departmentHandler) Get(c echo.Context) error {
 	ctx := c.Request().Context()
 	departmentID := c.Param("id")
 
+	fields, err := fieldset.Parse(c.QueryParam("fields"), domain.Department{})
+	if err != nil {
+		return domain.ConstraintErrorf("fields query-param is not valid: %v", err)
+	}
+
 	department, err := h.service.Get(ctx, departmentID)
 	if err != nil {
 		return errors.Wrap(err, "failed get a department")
 	}
 
-	return c.JSON(http.StatusOK, department)
+	projected, err := fields.Apply(department)
+	if err != nil {
+		return errors.Wrap(err, "error project department fields")
+	}
+
+	return render.Render(c, http.StatusOK, "department", projected)
 }
 
 func (h departmentHandler) Fetch(c echo.Context) error {
@@ -71,6 +86,7 @@ func (h departmentHandler) Fetch(c echo.Context) error {
 
 	keyword := c.QueryParam("keyword")
 	cursor := c.QueryParam("cursor")
+	before := c.QueryParam("before")
 
 	ids := make([]string, 0)
 	paramIDs := c.QueryParam("ids")
@@ -87,7 +103,50 @@ func (h departmentHandler) Fetch(c echo.Context) error {
 		}
 	}
 
-	res, nextCursor, err := h.service.Fetch(ctx, domain.DepartmentFilter{IDs: ids, Keyword: keyword, Num: num, Cursor: cursor})
+	withTotal := false
+	if totalStr := c.QueryParam("total"); totalStr != "" {
+		var err error
+		if withTotal, err = strconv.ParseBool(totalStr); err != nil {
+			err = fmt.Errorf("total query-param is not valid. Got error when parsing value: %v", err)
+			return domain.ConstraintErrorf("%s", err)
+		}
+	}
+
+	createdTime, err := timerange.FromQuery(c, "createdFrom", "createdTo")
+	if err != nil {
+		return err
+	}
+
+	updatedTime, err := timerange.FromQuery(c, "updatedFrom", "updatedTo")
+	if err != nil {
+		return err
+	}
+
+	var expression filterql.Expr
+	if rawFilter := c.QueryParam("filter"); rawFilter != "" {
+		if expression, err = filterql.Parse(rawFilter, domain.DepartmentFilterFields); err != nil {
+			return domain.ConstraintErrorf("filter query-param is not valid: %v", err)
+		}
+	}
+
+	fields, err := fieldset.Parse(c.QueryParam("fields"), domain.Department{})
+	if err != nil {
+		return domain.ConstraintErrorf("fields query-param is not valid: %v", err)
+	}
+
+	filter := domain.DepartmentFilter{
+		IDs:         ids,
+		Keyword:     keyword,
+		Num:         num,
+		Cursor:      cursor,
+		Before:      before,
+		WithTotal:   withTotal,
+		CreatedTime: createdTime,
+		UpdatedTime: updatedTime,
+		Expression:  expression,
+	}
+
+	res, pagination, err := h.service.Fetch(ctx, filter)
 	if err != nil {
 		return errors.Wrap(err, "error fetch departments")
 	}
@@ -104,10 +163,23 @@ func (h departmentHandler) Fetch(c echo.Context) error {
 		}
 
 		c.Response().Header().Set("ETag", "W/"+eTag)
-		c.Response().Header().Set("X-Cursor", nextCursor)
+		c.Response().Header().Set("X-Cursor", pagination.NextCursor)
 	}
 
-	return c.JSON(http.StatusOK, res)
+	if link := pkgCursor.LinkHeader(*c.Request().URL, pagination.NextCursor, pagination.PrevCursor); link != "" {
+		c.Response().Header().Set("Link", link)
+	}
+
+	if withTotal {
+		c.Response().Header().Set("X-Total-Count", strconv.Itoa(pagination.Total))
+	}
+
+	projected, err := fields.Apply(res)
+	if err != nil {
+		return errors.Wrap(err, "error project departments fields")
+	}
+
+	return render.Render(c, http.StatusOK, "department", projected)
 }
 
 func (h departmentHandler) Update(c echo.Context) error {
@@ -116,11 +188,11 @@ func (h departmentHandler) Update(c echo.Context) error {
 
 	var department domain.Department
 	if err := c.Bind(&department); err != nil {
-		return c.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	if err := validator.Validate(department); err != nil {
-		return c.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	department.ID = departmentID
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -17,12 +18,14 @@ import (
 	handler "github.com/milhamhidayat/golang-clean-code-v2/department/delivery/http"
 	"github.com/milhamhidayat/golang-clean-code-v2/domain"
 	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
 	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
 	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
 )
 
 func TestInsert(t *testing.T) {
 	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
 
 	var mockDepartment domain.Department
 	testdata.UnmarshallGoldenToJSON(t, "department-0ujsswThIGTUYm2K8FjOOfXtY1K", &mockDepartment)
@@ -189,6 +192,8 @@ func TestFetch(t *testing.T) {
 		expectedStatusCode int
 		expectedCursor     string
 		expectedETag       string
+		expectedLink       string
+		expectedTotal      string
 	}{
 		"success with num": {
 			departmentService: testdata.FuncCall{
@@ -199,12 +204,33 @@ func TestFetch(t *testing.T) {
 					Num:     20,
 					Cursor:  "",
 				}},
-				Output: []interface{}{departments, "next-cursor", nil},
+				Output: []interface{}{departments, domain.Pagination{NextCursor: "next-cursor"}, nil},
 			},
 			target:             "/departments",
 			expectedStatusCode: http.StatusOK,
 			expectedCursor:     "next-cursor",
 			expectedETag:       "W/d60c95250ff1839e44dc74409f2b6c63",
+			expectedLink:       `</departments?cursor=next-cursor>; rel="next"`,
+		},
+		"success with before cursor and total": {
+			departmentService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.DepartmentFilter{
+					IDs:       []string{},
+					Keyword:   "",
+					Num:       2,
+					Cursor:    "",
+					Before:    "before-cursor",
+					WithTotal: true,
+				}},
+				Output: []interface{}{departments, domain.Pagination{NextCursor: "next-cursor", PrevCursor: "prev-cursor", Total: 10}, nil},
+			},
+			target:             "/departments?num=2&before=before-cursor&total=true",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/d60c95250ff1839e44dc74409f2b6c63",
+			expectedLink:       `</departments?cursor=next-cursor&num=2&total=true>; rel="next", </departments?before=prev-cursor&num=2&total=true>; rel="prev"`,
+			expectedTotal:      "10",
 		},
 		"success with keyword": {
 			departmentService: testdata.FuncCall{
@@ -215,12 +241,46 @@ func TestFetch(t *testing.T) {
 					Num:     20,
 					Cursor:  "",
 				}},
-				Output: []interface{}{engineerDepartments, "next-cursor", nil},
+				Output: []interface{}{engineerDepartments, domain.Pagination{NextCursor: "next-cursor"}, nil},
 			},
 			target:             "/departments?keyword=engineer",
 			expectedStatusCode: http.StatusOK,
 			expectedCursor:     "next-cursor",
 			expectedETag:       "W/cbd902cb9cd45600989fdca27dbdbbe0",
+			expectedLink:       `</departments?cursor=next-cursor&keyword=engineer>; rel="next"`,
+		},
+		"success with last page": {
+			departmentService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.DepartmentFilter{
+					IDs:     []string{},
+					Keyword: "",
+					Num:     20,
+					Cursor:  "last-cursor",
+				}},
+				Output: []interface{}{engineerDepartments, domain.Pagination{PrevCursor: "prev-cursor"}, nil},
+			},
+			target:             "/departments?cursor=last-cursor",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "",
+			expectedETag:       "W/cbd902cb9cd45600989fdca27dbdbbe0",
+			expectedLink:       `</departments?before=prev-cursor>; rel="prev"`,
+		},
+		"success with empty page": {
+			departmentService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.DepartmentFilter{
+					IDs:     []string{},
+					Keyword: "",
+					Num:     20,
+					Cursor:  "last-cursor",
+				}},
+				Output: []interface{}{[]domain.Department{}, domain.Pagination{}, nil},
+			},
+			target:             "/departments?cursor=last-cursor",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "",
+			expectedETag:       "",
 		},
 		"success with ids": {
 			departmentService: testdata.FuncCall{
@@ -231,7 +291,7 @@ func TestFetch(t *testing.T) {
 					Num:     20,
 					Cursor:  "",
 				}},
-				Output: []interface{}{engineerDepartments, "", nil},
+				Output: []interface{}{engineerDepartments, domain.Pagination{}, nil},
 			},
 			target:             "/departments?ids=0ujssxh0cECutqzMgbtXSGnjorm",
 			expectedStatusCode: http.StatusOK,
@@ -247,7 +307,7 @@ func TestFetch(t *testing.T) {
 					Num:     20,
 					Cursor:  "",
 				}},
-				Output: []interface{}{departments, "next-cursor", nil},
+				Output: []interface{}{departments, domain.Pagination{NextCursor: "next-cursor"}, nil},
 			},
 			target:             "/departments",
 			ifNoneMatch:        "W/d60c95250ff1839e44dc74409f2b6c63",
@@ -262,6 +322,77 @@ func TestFetch(t *testing.T) {
 			target:             "/departments?num=xxxx",
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		"success with created time range": {
+			departmentService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.DepartmentFilter{
+					IDs:     []string{},
+					Keyword: "",
+					Num:     20,
+					Cursor:  "",
+					CreatedTime: domain.TimeRange{
+						From: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+						To:   time.Date(2019, 10, 31, 23, 59, 59, 999999000, time.UTC),
+					},
+				}},
+				Output: []interface{}{engineerDepartments, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/departments?createdFrom=2019-10-01&createdTo=2019-10-31",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/cbd902cb9cd45600989fdca27dbdbbe0",
+			expectedLink:       `</departments?createdFrom=2019-10-01&createdTo=2019-10-31&cursor=next-cursor>; rel="next"`,
+		},
+		"success with filter expression": {
+			departmentService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.DepartmentFilter{
+					IDs:     []string{},
+					Keyword: "",
+					Num:     20,
+					Cursor:  "",
+					Expression: filterql.Comparison{
+						Field:    "name",
+						Operator: filterql.Eq,
+						Values:   []interface{}{"Engineer"},
+					},
+				}},
+				Output: []interface{}{engineerDepartments, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/departments?filter=" + url.QueryEscape(`name eq "Engineer"`),
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/cbd902cb9cd45600989fdca27dbdbbe0",
+			expectedLink:       `</departments?cursor=next-cursor&filter=name+eq+%22Engineer%22>; rel="next"`,
+		},
+		"with bad fields param": {
+			departmentService: testdata.FuncCall{
+				Called: false,
+			},
+			target:             "/departments?fields=id,password",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"with bad filter expression": {
+			departmentService: testdata.FuncCall{
+				Called: false,
+			},
+			target:             "/departments?filter=" + url.QueryEscape(`password eq "secret"`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"with bad created time param": {
+			departmentService: testdata.FuncCall{
+				Called: false,
+			},
+			target:             "/departments?createdFrom=xxxx",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"with bad total param": {
+			departmentService: testdata.FuncCall{
+				Called: false,
+			},
+			target:             "/departments?total=xxxx",
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		"with unexpected error": {
 			departmentService: testdata.FuncCall{
 				Called: true,
@@ -271,7 +402,7 @@ func TestFetch(t *testing.T) {
 					Num:     20,
 					Cursor:  "",
 				}},
-				Output: []interface{}{[]domain.Department{}, "", errors.New("unexpected error")},
+				Output: []interface{}{[]domain.Department{}, domain.Pagination{}, errors.New("unexpected error")},
 			},
 			target:             "/departments",
 			expectedStatusCode: http.StatusInternalServerError,
@@ -299,6 +430,8 @@ func TestFetch(t *testing.T) {
 
 			require.Equal(t, test.expectedCursor, rec.Header().Get("X-Cursor"))
 			require.Equal(t, test.expectedETag, rec.Header().Get("ETag"))
+			require.Equal(t, test.expectedLink, rec.Header().Get("Link"))
+			require.Equal(t, test.expectedTotal, rec.Header().Get("X-Total-Count"))
 			require.Equal(t, test.expectedStatusCode, rec.Code)
 		})
 	}
@@ -9,14 +9,23 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/segmentio/ksuid"
-	log "github.com/sirupsen/logrus"
 
 	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	outbox "github.com/milhamhidayat/golang-clean-code-v2/outbox/repository/mariadb"
 	"github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
 	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/tracing"
 )
 
-// Repository implement all department repository method from interface
+// queryRower queries a row, it is satisfied by *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Repository implement all department repository method from interface.
+// Every change is written with its domain event to the outbox in the same transaction
 type Repository struct {
 	DB *sql.DB
 }
@@ -30,6 +39,9 @@ func New(db *sql.DB) Repository {
 
 // Create is a repository to insert a department
 func (r Repository) Create(ctx context.Context, d *domain.Department) (err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
 	localTime, err := ntime.GetLocalTime()
 	if err != nil {
 		return
@@ -40,100 +52,127 @@ func (r Repository) Create(ctx context.Context, d *domain.Department) (err error
 		return
 	}
 
-	departmentID := ksuid.New().String()
-	if d.ID == "" {
-		d.ID = departmentID
+	err = r.insert(ctx, tx, d, localTime)
+	if err != nil {
+		r.rollback(ctx, tx)
+		return
 	}
 
-	d.CreatedTime = localTime
-	d.UpdatedTime = localTime
-
-	query, args, err := sq.Insert("departments").
-		Columns("id", "name", "description", "created_time", "updated_time").
-		Values(d.ID, d.Name, d.Description, d.CreatedTime, d.UpdatedTime).
-		ToSql()
+	err = tx.Commit()
 	if err != nil {
-		r.rollback(tx)
+		r.rollback(ctx, tx)
 		return
 	}
 
-	stmt, err := tx.PrepareContext(ctx, query)
+	dept, err := r.Get(ctx, d.ID)
 	if err != nil {
-		r.rollback(tx)
 		return
 	}
 
-	defer func() {
-		err := stmt.Close()
-		if err != nil {
-			log.Error(err)
-		}
-	}()
+	d = &dept
 
-	_, err = stmt.ExecContext(ctx, args...)
-	if err != nil {
-		r.rollback(tx)
-		return
+	return
+}
+
+// insert inserts a department and writes its created event within the transaction
+func (r Repository) insert(ctx context.Context, tx *sql.Tx, d *domain.Department, localTime time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.insert")
+	defer func() { tracing.End(span, err) }()
+
+	if d.ID == "" {
+		d.ID = ksuid.New().String()
 	}
 
-	err = tx.Commit()
+	d.CreatedTime = localTime
+	d.UpdatedTime = localTime
+
+	query, args, err := sq.Insert("departments").
+		Columns("id", "tenant_id", "name", "description", "created_time", "updated_time").
+		Values(d.ID, domain.TenantFromContext(ctx), d.Name, d.Description, d.CreatedTime, d.UpdatedTime).
+		ToSql()
 	if err != nil {
-		r.rollback(tx)
 		return
 	}
 
-	dept, err := r.Get(ctx, d.ID)
+	tracing.Statement(span, query)
+	_, err = tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return
 	}
 
-	d = &dept
-
-	return
+	return r.writeEvent(ctx, tx, domain.EventDepartmentCreated, d.ID, d)
 }
 
 // Fetch is a repository to fetch department based on parameter
-func (r Repository) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, nextCursor string, err error) {
+func (r Repository) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, pagination domain.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.Fetch")
+	defer func() { tracing.End(span, err) }()
+
 	qSelect := sq.Select("id", "name", "description", "created_time", "updated_time").
 		From("departments")
 
+	conditions := sq.And{sq.Eq{"tenant_id": domain.TenantFromContext(ctx)}}
+
 	if len(filter.IDs) != 0 {
-		qSelect = qSelect.Where(sq.Eq{"id": filter.IDs})
+		qSelect = qSelect.Where(conditions).Where(sq.Eq{"id": filter.IDs})
 		qField := strings.Repeat(",?", len(filter.IDs))
 		qOrderBy := fmt.Sprintf("ORDER BY FIELD(id%s)", qField)
-		qSelect = qSelect.Suffix(qOrderBy)
+		ids := make([]interface{}, 0, len(filter.IDs))
+		for _, id := range filter.IDs {
+			ids = append(ids, id)
+		}
+		qSelect = qSelect.Suffix(qOrderBy, ids...)
 	} else {
-		qSelect = qSelect.OrderBy(`id desc`)
-
 		if filter.Keyword != "" {
-			qSelect = qSelect.Where(`name LIKE ?`, fmt.Sprint("%", filter.Keyword, "%"))
+			conditions = append(conditions, sq.Expr(`name LIKE ?`, fmt.Sprint("%", filter.Keyword, "%")))
+		}
+
+		conditions = append(conditions, timeRangeConditions("created_time", filter.CreatedTime)...)
+		conditions = append(conditions, timeRangeConditions("updated_time", filter.UpdatedTime)...)
+
+		if filter.Expression != nil {
+			pred, er := filterql.ToSqlizer(filter.Expression, nil)
+			if er != nil {
+				err = er
+				return
+			}
+			conditions = append(conditions, pred)
 		}
 
-		if filter.Cursor != "" {
-			var id string
+		qSelect = qSelect.Where(conditions)
+
+		switch {
+		case filter.Before != "":
+			id, er := cursor.DecodeBase64(filter.Before)
+			if er != nil {
+				err = er
+				return
+			}
+			qSelect = qSelect.Where(sq.Gt{"id": id}).OrderBy(`id asc`)
+		case filter.Cursor != "":
 			id, er := cursor.DecodeBase64(filter.Cursor)
 			if er != nil {
 				err = er
 				return
 			}
-			qSelect = qSelect.Where(sq.Lt{"id": id})
+			qSelect = qSelect.Where(sq.Lt{"id": id}).OrderBy(`id desc`)
+		default:
+			qSelect = qSelect.OrderBy(`id desc`)
 		}
 
 		if filter.Num > 0 {
-			qSelect = qSelect.Limit(uint64(filter.Num))
+			// fetch one more item to know whether there is a page beyond this one
+			qSelect = qSelect.Limit(uint64(filter.Num) + 1)
 		}
 	}
 
 	query, args, err := qSelect.ToSql()
 
-	if len(filter.IDs) != 0 {
-		args = append(args, args...)
-	}
-
 	if err != nil {
 		return
 	}
 
+	tracing.Statement(span, query)
 	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return
@@ -142,7 +181,7 @@ func (r Repository) Fetch(ctx context.Context, filter domain.DepartmentFilter) (
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			log.Error(err)
+			logger.FromContext(ctx).Errorf("failed to close department rows: %v", err)
 		}
 	}()
 
@@ -170,25 +209,82 @@ func (r Repository) Fetch(ctx context.Context, filter domain.DepartmentFilter) (
 	}
 
 	err = rows.Err()
+	if err != nil {
+		return
+	}
 
 	if len(filter.IDs) != 0 {
+		if filter.WithTotal {
+			pagination.Total = len(departments)
+		}
 		return
 	}
 
-	nextCursor = filter.Cursor
+	hasMore := filter.Num > 0 && len(departments) > filter.Num
+	if hasMore {
+		departments = departments[:filter.Num]
+	}
+
+	// a forward page has a previous page when it was reached by a cursor,
+	// a backward page always has the page it was reached from as its next page
+	hasPrev, hasNext := filter.Cursor != "", hasMore
+	if filter.Before != "" {
+		hasPrev, hasNext = hasMore, true
+
+		for i, j := 0, len(departments)-1; i < j; i, j = i+1, j-1 {
+			departments[i], departments[j] = departments[j], departments[i]
+		}
+	}
+
 	if len(departments) >= 1 {
-		id := departments[len(departments)-1].ID
-		nextCursor = cursor.EncodeBase64(id)
+		if hasNext {
+			pagination.NextCursor = cursor.EncodeBase64(departments[len(departments)-1].ID)
+		}
+
+		if hasPrev {
+			pagination.PrevCursor = cursor.EncodeBase64(departments[0].ID)
+		}
+	}
+
+	if filter.WithTotal {
+		pagination.Total, err = r.count(ctx, conditions)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (r Repository) count(ctx context.Context, conditions sq.And) (total int, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.count")
+	defer func() { tracing.End(span, err) }()
+
+	qCount := sq.Select("COUNT(*)").From("departments").Where(conditions)
+
+	query, args, err := qCount.ToSql()
+	if err != nil {
+		return
 	}
 
+	tracing.Statement(span, query)
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(&total)
 	return
 }
 
 // Get is a repository to get a department based on parameter
 func (r Repository) Get(ctx context.Context, departmentID string) (department domain.Department, err error) {
+	return r.get(ctx, r.DB, departmentID)
+}
+
+// get gets a department with the db or a transaction
+func (r Repository) get(ctx context.Context, q queryRower, departmentID string) (department domain.Department, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.Get")
+	defer func() { tracing.End(span, err) }()
+
 	query, args, err := sq.Select("id", "name", "description", "created_time", "updated_time").
 		From("departments").
-		Where(sq.Eq{"id": departmentID}).
+		Where(sq.Eq{"id": departmentID, "tenant_id": domain.TenantFromContext(ctx)}).
 		ToSql()
 	if err != nil {
 		return
@@ -199,7 +295,8 @@ func (r Repository) Get(ctx context.Context, departmentID string) (department do
 	createdTime := time.Time{}
 	updatedTime := time.Time{}
 
-	row := r.DB.QueryRowContext(ctx, query, args...)
+	tracing.Statement(span, query)
+	row := q.QueryRowContext(ctx, query, args...)
 	err = row.Scan(
 		&department.ID,
 		&department.Name,
@@ -224,6 +321,9 @@ func (r Repository) Get(ctx context.Context, departmentID string) (department do
 
 // Update is a repository to update a department
 func (r Repository) Update(ctx context.Context, d domain.Department) (department domain.Department, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.Update")
+	defer func() { tracing.End(span, err) }()
+
 	localTime, err := ntime.GetLocalTime()
 	if err != nil {
 		return
@@ -232,63 +332,94 @@ func (r Repository) Update(ctx context.Context, d domain.Department) (department
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return
+	}
 
+	department, err = r.update(ctx, tx, d, localTime)
+	if err != nil {
+		r.rollback(ctx, tx)
+		return
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		r.rollback(ctx, tx)
+		return
+	}
+
+	return
+}
+
+// update updates a department and writes its updated event within the transaction,
+// ErrNotFound is returned when the department doesn't exist
+func (r Repository) update(ctx context.Context, tx *sql.Tx, d domain.Department, localTime time.Time) (department domain.Department, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.update")
+	defer func() { tracing.End(span, err) }()
+
 	query, args, err := sq.Update("departments").
 		SetMap(sq.Eq{
 			"name":         d.Name,
 			"description":  d.Description,
 			"updated_time": localTime,
 		}).
-		Where(sq.Eq{"id": d.ID}).
+		Where(sq.Eq{"id": d.ID, "tenant_id": domain.TenantFromContext(ctx)}).
 		ToSql()
 	if err != nil {
-		r.rollback(tx)
 		return
-
 	}
 
-	stmt, err := tx.PrepareContext(ctx, query)
+	tracing.Statement(span, query)
+	_, err = tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		r.rollback(tx)
 		return
-
 	}
 
-	defer func() {
-		err := stmt.Close()
-		if err != nil {
-			log.Error(err)
-		}
-	}()
-
-	res, err := stmt.ExecContext(ctx, args...)
+	// the updated row is read back within the transaction, it is missing when the department doesn't exist
+	department, err = r.get(ctx, tx, d.ID)
 	if err != nil {
-		r.rollback(tx)
-		return
-
+		return domain.Department{}, err
 	}
 
-	count, err := res.RowsAffected()
+	err = r.writeEvent(ctx, tx, domain.EventDepartmentUpdated, d.ID, department)
+	return
+}
+
+// Upsert is a repository to update the departments of an id and insert the others in a single transaction,
+// ErrNotFound is returned when an id doesn't exist in the tenant
+func (r Repository) Upsert(ctx context.Context, departments []domain.Department) (created, updated int, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.Upsert")
+	defer func() { tracing.End(span, err) }()
+
+	localTime, err := ntime.GetLocalTime()
 	if err != nil {
 		return
 	}
 
-	err = tx.Commit()
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		r.rollback(tx)
 		return
 	}
 
-	if count == 0 {
-		err = domain.ErrNotFound
-		return
+	for i := range departments {
+		d := &departments[i]
+
+		if d.ID == "" {
+			err = r.insert(ctx, tx, d, localTime)
+			created++
+		} else if _, err = r.get(ctx, tx, d.ID); err == nil {
+			*d, err = r.update(ctx, tx, *d, localTime)
+			updated++
+		}
+
+		if err != nil {
+			r.rollback(ctx, tx)
+			return 0, 0, err
+		}
 	}
 
-	department, err = r.Get(ctx, d.ID)
+	err = tx.Commit()
 	if err != nil {
-		return
+		r.rollback(ctx, tx)
+		return 0, 0, err
 	}
 
 	return
@@ -296,61 +427,103 @@ func (r Repository) Update(ctx context.Context, d domain.Department) (department
 
 // Delete is a repository to delete a department
 func (r Repository) Delete(ctx context.Context, departmentID string) (err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
 
+	// the deleted event carries the last state of the department
+	department, err := r.get(ctx, tx, departmentID)
+	if err != nil {
+		r.rollback(ctx, tx)
+		return
+	}
+
 	query, args, err := sq.Delete("departments").
-		Where(sq.Eq{"id": departmentID}).
+		Where(sq.Eq{"id": departmentID, "tenant_id": domain.TenantFromContext(ctx)}).
 		ToSql()
 	if err != nil {
-		r.rollback(tx)
+		r.rollback(ctx, tx)
 		return
 	}
 
+	tracing.Statement(span, query)
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
-		r.rollback(tx)
+		r.rollback(ctx, tx)
 		return
 	}
 
-	defer func() {
-		err := stmt.Close()
-		if err != nil {
-			log.Error(err)
-		}
-	}()
+	defer r.closeStatement(ctx, stmt)
 
 	res, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
-		r.rollback(tx)
+		r.rollback(ctx, tx)
 		return
 	}
 
 	count, err := res.RowsAffected()
 	if err != nil {
-		r.rollback(tx)
+		r.rollback(ctx, tx)
 		return
 	}
 
-	err = tx.Commit()
+	if count == 0 {
+		r.rollback(ctx, tx)
+		err = domain.ErrNotFound
+		return
+	}
+
+	err = r.writeEvent(ctx, tx, domain.EventDepartmentDeleted, departmentID, department)
 	if err != nil {
-		r.rollback(tx)
+		r.rollback(ctx, tx)
 		return
 	}
 
-	if count == 0 {
-		err = domain.ErrNotFound
+	err = tx.Commit()
+	if err != nil {
+		r.rollback(ctx, tx)
 		return
 	}
 
 	return
 }
 
-func (r Repository) rollback(tx *sql.Tx) {
+func timeRangeConditions(column string, timeRange domain.TimeRange) (conditions sq.And) {
+	if !timeRange.From.IsZero() {
+		conditions = append(conditions, sq.GtOrEq{column: timeRange.From})
+	}
+
+	if !timeRange.To.IsZero() {
+		conditions = append(conditions, sq.LtOrEq{column: timeRange.To})
+	}
+
+	return
+}
+
+// writeEvent writes the event of a department change to the outbox within the transaction of the change
+func (r Repository) writeEvent(ctx context.Context, tx *sql.Tx, eventType, departmentID string, data interface{}) (err error) {
+	event, err := domain.NewEvent(ctx, eventType, domain.AggregateDepartment, departmentID, data)
+	if err != nil {
+		return
+	}
+
+	return outbox.Insert(ctx, tx, event)
+}
+
+func (r Repository) rollback(ctx context.Context, tx *sql.Tx) {
 	err := tx.Rollback()
 	if err != nil && err != sql.ErrTxDone {
-		log.Error(err)
+		logger.FromContext(ctx).Errorf("failed to rollback department transaction: %v", err)
+	}
+}
+
+func (r Repository) closeStatement(ctx context.Context, stmt *sql.Stmt) {
+	err := stmt.Close()
+	if err != nil {
+		logger.FromContext(ctx).Errorf("failed to close department statement: %v", err)
 	}
 }
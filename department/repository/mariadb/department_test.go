@@ -3,6 +3,7 @@ package mariadb_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -172,14 +173,12 @@ func (d *departmentSuite) TestFetch() {
 			want[i].UpdatedTime = utcTime
 		}
 
-		expectedCursor := "MHVqc3N3VGhJR1RVWW0ySzhGak9PZlh0WTFL"
-
-		depts, cur, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+		depts, pagination, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
 			Keyword: "Marketing",
 		})
 
 		require.Equal(t, want, depts)
-		require.Equal(t, expectedCursor, cur)
+		require.Equal(t, domain.Pagination{}, pagination)
 		require.NoError(t, err)
 	})
 
@@ -198,28 +197,122 @@ func (d *departmentSuite) TestFetch() {
 			want[i].UpdatedTime = utcTime
 		}
 
-		expectedCursor := "MHVqc3N3VGhJR1RVWW0ySzhGak9PZlh0WTFL"
-
-		depts, cur, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+		depts, pagination, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
 			Num: 4,
 		})
 
 		require.Equal(t, want, depts)
-		require.Equal(t, expectedCursor, cur)
+		require.Equal(t, domain.Pagination{}, pagination)
+		require.NoError(t, err)
+	})
+
+	d.T().Run("success with first page using num", func(t *testing.T) {
+		want := make([]domain.Department, 2)
+		want[0] = departments[2]
+		want[1] = departments[3]
+
+		for i, v := range want {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
+			require.NoError(t, err)
+
+			want[i].CreatedTime = utcTime
+			want[i].UpdatedTime = utcTime
+		}
+
+		depts, pagination, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+			Num: 2,
+		})
+
+		require.Equal(t, want, depts)
+		require.Equal(t, domain.Pagination{NextCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0"}, pagination)
+		require.NoError(t, err)
+	})
+
+	d.T().Run("success with last page using num and cursor", func(t *testing.T) {
+		want := make([]domain.Department, 2)
+		want[0] = departments[0]
+		want[1] = departments[1]
+
+		for i, v := range want {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
+			require.NoError(t, err)
+
+			want[i].CreatedTime = utcTime
+			want[i].UpdatedTime = utcTime
+		}
+
+		depts, pagination, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+			Num:    2,
+			Cursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0",
+		})
+
+		require.Equal(t, want, depts)
+		require.Equal(t, domain.Pagination{PrevCursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt"}, pagination)
+		require.NoError(t, err)
+	})
+
+	d.T().Run("success with keyword and total", func(t *testing.T) {
+		depts, pagination, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+			Keyword:   "Marketing",
+			Num:       1,
+			WithTotal: true,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, depts, 1)
+		require.Equal(t, 2, pagination.Total)
+	})
+
+	d.T().Run("success with created time range", func(t *testing.T) {
+		depts, _, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+			Keyword: "Marketing",
+			CreatedTime: domain.TimeRange{
+				From: time.Now().Add(time.Hour),
+			},
+		})
+
+		require.NoError(t, err)
+		require.Empty(t, depts)
+	})
+
+	d.T().Run("success with num and before cursor", func(t *testing.T) {
+		want := make([]domain.Department, 2)
+		want[0] = departments[3]
+		want[1] = departments[0]
+
+		for i, v := range want {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
+			require.NoError(t, err)
+
+			want[i].CreatedTime = utcTime
+			want[i].UpdatedTime = utcTime
+		}
+
+		expectedPagination := domain.Pagination{
+			NextCursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt",
+			PrevCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0",
+		}
+
+		depts, pagination, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+			Num:    2,
+			Before: "MHVqc3N3VGhJR1RVWW0ySzhGak9PZlh0WTFL",
+		})
+
+		require.Equal(t, want, depts)
+		require.Equal(t, expectedPagination, pagination)
 		require.NoError(t, err)
 	})
 
-	d.T().Run("success with num and cursor", func(t *testing.T) {
+	d.T().Run("success with empty page using num and cursor", func(t *testing.T) {
 		var want []domain.Department
 
-		expectedCursor := "MHVqc3N3VGhJR1RVWW0ySzhGak9PZlh0WTFL"
-		depts, cur, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
+		depts, pagination, err := departmentRepo.Fetch(context.Background(), domain.DepartmentFilter{
 			Num:    4,
 			Cursor: "MHVqc3N3VGhJR1RVWW0ySzhGak9PZlh0WTFL",
 		})
 
 		require.Equal(t, want, depts)
-		require.Equal(t, expectedCursor, cur)
+		require.Equal(t, domain.Pagination{}, pagination)
 		require.NoError(t, err)
 	})
 }
@@ -275,3 +368,89 @@ func (d *departmentSuite) TestDelete() {
 		require.EqualError(t, err, domain.ErrNotFound.Error())
 	})
 }
+
+func (d *departmentSuite) TestTenantIsolation() {
+	departmentRepo := repo.New(d.DB)
+	acme := domain.NewContextWithTenant(context.Background(), "acme")
+	globex := domain.NewContextWithTenant(context.Background(), "globex")
+
+	var department domain.Department
+	testdata.UnmarshallGoldenToJSON(d.T(), "department-0ujssxh0cECutqzMgbtXSGnjorm", &department)
+
+	err := departmentRepo.Create(acme, &department)
+	require.NoError(d.T(), err)
+
+	d.T().Run("get of other tenant", func(t *testing.T) {
+		_, err := departmentRepo.Get(globex, department.ID)
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+
+		_, err = departmentRepo.Get(context.Background(), department.ID)
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	d.T().Run("fetch of other tenant", func(t *testing.T) {
+		res, pagination, err := departmentRepo.Fetch(globex, domain.DepartmentFilter{Num: 10, WithTotal: true})
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+		require.Equal(t, 0, pagination.Total)
+
+		res, _, err = departmentRepo.Fetch(globex, domain.DepartmentFilter{IDs: []string{department.ID}})
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+	})
+
+	d.T().Run("update of other tenant", func(t *testing.T) {
+		_, err := departmentRepo.Update(globex, domain.Department{ID: department.ID, Name: "Globex"})
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	d.T().Run("delete of other tenant", func(t *testing.T) {
+		err := departmentRepo.Delete(globex, department.ID)
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	d.T().Run("same tenant", func(t *testing.T) {
+		res, err := departmentRepo.Get(acme, department.ID)
+		require.NoError(t, err)
+		require.Equal(t, department.Name, res.Name)
+
+		departments, _, err := departmentRepo.Fetch(acme, domain.DepartmentFilter{IDs: []string{department.ID}})
+		require.NoError(t, err)
+		require.Len(t, departments, 1)
+	})
+}
+
+func (d *departmentSuite) TestUpsert() {
+	departmentRepo := repo.New(d.DB)
+
+	var department domain.Department
+	testdata.UnmarshallGoldenToJSON(d.T(), "department-0ujssxh0cECutqzMgbtXSGnjorm", &department)
+
+	err := d.SeedDepartment([]domain.Department{department})
+	require.NoError(d.T(), err)
+
+	departments := []domain.Department{
+		{ID: department.ID, Name: department.Name, Description: "imported"},
+		{Name: "Finance", Description: "imported"},
+	}
+	created, updated, err := departmentRepo.Upsert(context.Background(), departments)
+	require.NoError(d.T(), err)
+	require.Equal(d.T(), 1, created)
+	require.Equal(d.T(), 1, updated)
+
+	for _, v := range departments {
+		res, err := departmentRepo.Get(context.Background(), v.ID)
+		require.NoError(d.T(), err)
+		require.Equal(d.T(), v.Name, res.Name)
+		require.Equal(d.T(), "imported", res.Description)
+	}
+
+	d.T().Run("unknown id isn't inserted", func(t *testing.T) {
+		unknown := domain.Department{ID: "0ujzPyRiIAffKhBux4PvQdDqMHY", Name: "Ghost"}
+		_, _, err := departmentRepo.Upsert(context.Background(), []domain.Department{unknown})
+		require.Equal(t, domain.ErrNotFound, err)
+
+		_, err = departmentRepo.Get(context.Background(), unknown.ID)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/metrics"
+)
+
+// name labels the metrics of the repository
+const name = "department"
+
+// Metrics is a department repository measuring the calls of the next repository
+type Metrics struct {
+	next    domain.DepartmentRepository
+	metrics *metrics.Repository
+}
+
+// NewMetrics will return a department repository measuring the calls of the next repository
+func NewMetrics(next domain.DepartmentRepository, m *metrics.Repository) Metrics {
+	return Metrics{
+		next:    next,
+		metrics: m,
+	}
+}
+
+// Create will measure creating a department
+func (m Metrics) Create(ctx context.Context, d *domain.Department) (err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Create", start, err) }(time.Now())
+	return m.next.Create(ctx, d)
+}
+
+// Fetch will measure fetching departments
+func (m Metrics) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, pagination domain.Pagination, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Fetch", start, err) }(time.Now())
+	return m.next.Fetch(ctx, filter)
+}
+
+// Get will measure getting a department
+func (m Metrics) Get(ctx context.Context, departmentID string) (department domain.Department, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Get", start, err) }(time.Now())
+	return m.next.Get(ctx, departmentID)
+}
+
+// Update will measure updating a department
+func (m Metrics) Update(ctx context.Context, d domain.Department) (department domain.Department, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Update", start, err) }(time.Now())
+	return m.next.Update(ctx, d)
+}
+
+// Delete will measure deleting a department
+func (m Metrics) Delete(ctx context.Context, departmentID string) (err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Delete", start, err) }(time.Now())
+	return m.next.Delete(ctx, departmentID)
+}
+
+// Upsert will measure upserting departments
+func (m Metrics) Upsert(ctx context.Context, departments []domain.Department) (created, updated int, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Upsert", start, err) }(time.Now())
+	return m.next.Upsert(ctx, departments)
+}
@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/department/repository"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/metrics"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+func TestMetrics(t *testing.T) {
+	var department domain.Department
+	testdata.UnmarshallGoldenToJSON(t, "department-0ujssxh0cECutqzMgbtXSGnjorm", &department)
+
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewRepository(reg)
+	require.NoError(t, err)
+
+	next := new(mocks.DepartmentRepository)
+	next.On("Get", mock.Anything, department.ID).Return(department, nil).Once()
+	next.On("Delete", mock.Anything, department.ID).Return(domain.ErrNotFound).Once()
+	next.On("Fetch", mock.Anything, mock.Anything).Return(nil, domain.Pagination{}, context.DeadlineExceeded).Once()
+
+	repo := repository.NewMetrics(next, m)
+
+	res, err := repo.Get(context.Background(), department.ID)
+	require.NoError(t, err)
+	require.Equal(t, department, res)
+
+	err = repo.Delete(context.Background(), department.ID)
+	require.Equal(t, domain.ErrNotFound, err)
+
+	_, _, err = repo.Fetch(context.Background(), domain.DepartmentFilter{})
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	next.AssertExpectations(t)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	series := map[string]int{}
+	for _, f := range families {
+		series[f.GetName()] = len(f.GetMetric())
+	}
+	require.Equal(t, map[string]int{
+		"employee_repository_call_duration_seconds": 3,
+		"employee_repository_call_errors_total":     1,
+	}, series)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Authorization is a department service enforcing role based access on the next service.
+// Every role may read departments, only hr-admin may mutate them.
+// An API key is authorized by departments:read and departments:write scopes instead
+type Authorization struct {
+	next domain.DepartmentService
+}
+
+// NewAuthorization will return a department service enforcing role based access
+func NewAuthorization(next domain.DepartmentService) Authorization {
+	return Authorization{
+		next: next,
+	}
+}
+
+func authorize(ctx context.Context, scope string, roles ...domain.Role) (err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	if !p.HasScope(scope) && !p.HasRole(roles...) {
+		return domain.ErrForbidden
+	}
+
+	return
+}
+
+// Create will create a department when the caller is hr-admin
+func (a Authorization) Create(ctx context.Context, d *domain.Department) (err error) {
+	if err = authorize(ctx, domain.ScopeDepartmentsWrite, domain.RoleHRAdmin); err != nil {
+		return
+	}
+
+	return a.next.Create(ctx, d)
+}
+
+// Fetch will fetch departments when the caller has any role
+func (a Authorization) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, pagination domain.Pagination, err error) {
+	if err = authorize(ctx, domain.ScopeDepartmentsRead, domain.RoleViewer, domain.RoleHRAdmin, domain.RoleDepartmentHead); err != nil {
+		return
+	}
+
+	return a.next.Fetch(ctx, filter)
+}
+
+// Get will get a department when the caller has any role
+func (a Authorization) Get(ctx context.Context, departmentID string) (department domain.Department, err error) {
+	if err = authorize(ctx, domain.ScopeDepartmentsRead, domain.RoleViewer, domain.RoleHRAdmin, domain.RoleDepartmentHead); err != nil {
+		return
+	}
+
+	return a.next.Get(ctx, departmentID)
+}
+
+// Update will update a department when the caller is hr-admin
+func (a Authorization) Update(ctx context.Context, d domain.Department) (department domain.Department, err error) {
+	if err = authorize(ctx, domain.ScopeDepartmentsWrite, domain.RoleHRAdmin); err != nil {
+		return
+	}
+
+	return a.next.Update(ctx, d)
+}
+
+// Delete will delete a department when the caller is hr-admin
+func (a Authorization) Delete(ctx context.Context, departmentID string) (err error) {
+	if err = authorize(ctx, domain.ScopeDepartmentsWrite, domain.RoleHRAdmin); err != nil {
+		return
+	}
+
+	return a.next.Delete(ctx, departmentID)
+}
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/department/service"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+)
+
+func TestAuthorization(t *testing.T) {
+	department := domain.Department{ID: "1", Name: "Marketing"}
+
+	tests := map[string]struct {
+		principal   *domain.Principal
+		method      string
+		called      bool
+		expectedErr error
+	}{
+		"viewer get": {
+			principal: &domain.Principal{Subject: "viewer", Roles: []domain.Role{domain.RoleViewer}},
+			method:    "Get",
+			called:    true,
+		},
+		"viewer update": {
+			principal:   &domain.Principal{Subject: "viewer", Roles: []domain.Role{domain.RoleViewer}},
+			method:      "Update",
+			expectedErr: domain.ErrForbidden,
+		},
+		"department head update": {
+			principal:   &domain.Principal{Subject: "head", Roles: []domain.Role{domain.RoleDepartmentHead}, DepartmentID: "1"},
+			method:      "Update",
+			expectedErr: domain.ErrForbidden,
+		},
+		"hr admin update": {
+			principal: &domain.Principal{Subject: "hr", Roles: []domain.Role{domain.RoleHRAdmin}},
+			method:    "Update",
+			called:    true,
+		},
+		"api key with read scope get": {
+			principal: &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeDepartmentsRead}},
+			method:    "Get",
+			called:    true,
+		},
+		"api key with read scope update": {
+			principal:   &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeDepartmentsRead}},
+			method:      "Update",
+			expectedErr: domain.ErrForbidden,
+		},
+		"api key with write scope update": {
+			principal: &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeDepartmentsWrite}},
+			method:    "Update",
+			called:    true,
+		},
+		"not authenticated get": {
+			method:      "Get",
+			expectedErr: domain.ErrUnauthorized,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.principal != nil {
+				ctx = domain.NewContextWithPrincipal(ctx, *tc.principal)
+			}
+
+			mockDepartmentService := new(mocks.DepartmentService)
+			authz := service.NewAuthorization(mockDepartmentService)
+
+			var err error
+			switch tc.method {
+			case "Get":
+				if tc.called {
+					mockDepartmentService.On("Get", ctx, department.ID).Return(department, nil).Once()
+				}
+				_, err = authz.Get(ctx, department.ID)
+			case "Update":
+				if tc.called {
+					mockDepartmentService.On("Update", ctx, department).Return(department, nil).Once()
+				}
+				_, err = authz.Update(ctx, department)
+			}
+
+			mockDepartmentService.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
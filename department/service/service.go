@@ -16,7 +16,7 @@ type Service struct {
 // New will return a department service
 func New(
 	repo domain.DepartmentRepository,
-) domain.DepartmentRepository {
+) domain.DepartmentService {
 	return Service{
 		Repository: repo,
 	}
@@ -34,10 +34,10 @@ func (s Service) Create(ctx context.Context, d *domain.Department) (err error) {
 }
 
 // Fetch is a service to fetch department
-func (s Service) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, nextCursor string, err error) {
-	departments, nextCursor, err = s.Repository.Fetch(ctx, filter)
+func (s Service) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, pagination domain.Pagination, err error) {
+	departments, pagination, err = s.Repository.Fetch(ctx, filter)
 	if err != nil {
-		nextCursor = filter.Cursor
+		pagination = domain.Pagination{NextCursor: filter.Cursor}
 		err = errors.Wrap(err, "failed to fetch departments")
 		return
 	}
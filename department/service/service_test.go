@@ -78,11 +78,11 @@ func TestFetch(t *testing.T) {
 	mockDepartmentRepo := new(mocks.DepartmentRepository)
 
 	tests := map[string]struct {
-		filter         domain.DepartmentFilter
-		departmentRepo map[string]testdata.FuncCall
-		expectedRes    []domain.Department
-		expectedCursor string
-		expectedErr    error
+		filter             domain.DepartmentFilter
+		departmentRepo     map[string]testdata.FuncCall
+		expectedRes        []domain.Department
+		expectedPagination domain.Pagination
+		expectedErr        error
 	}{
 		"success with num first page": {
 			filter: domain.DepartmentFilter{Num: 2},
@@ -90,12 +90,12 @@ func TestFetch(t *testing.T) {
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.DepartmentFilter{Num: 2}},
-					Output: []interface{}{[]domain.Department{department1, department2}, "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt", nil},
+					Output: []interface{}{[]domain.Department{department1, department2}, domain.Pagination{NextCursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt"}, nil},
 				},
 			},
-			expectedRes:    []domain.Department{department1, department2},
-			expectedCursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt",
-			expectedErr:    nil,
+			expectedRes:        []domain.Department{department1, department2},
+			expectedPagination: domain.Pagination{NextCursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt"},
+			expectedErr:        nil,
 		},
 		"success with num and cursor second page": {
 			filter: domain.DepartmentFilter{Num: 2, Cursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt"},
@@ -103,12 +103,12 @@ func TestFetch(t *testing.T) {
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.DepartmentFilter{Num: 2, Cursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt"}},
-					Output: []interface{}{[]domain.Department{department3}, "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0", nil},
+					Output: []interface{}{[]domain.Department{department3}, domain.Pagination{NextCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0"}, nil},
 				},
 			},
-			expectedRes:    []domain.Department{department3},
-			expectedCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0",
-			expectedErr:    nil,
+			expectedRes:        []domain.Department{department3},
+			expectedPagination: domain.Pagination{NextCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0"},
+			expectedErr:        nil,
 		},
 		"success with num and cursor end of page": {
 			filter: domain.DepartmentFilter{Num: 2, Cursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0"},
@@ -116,12 +116,25 @@ func TestFetch(t *testing.T) {
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.DepartmentFilter{Num: 2, Cursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0"}},
-					Output: []interface{}{[]domain.Department{}, "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0", nil},
+					Output: []interface{}{[]domain.Department{}, domain.Pagination{NextCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0"}, nil},
+				},
+			},
+			expectedRes:        []domain.Department{},
+			expectedPagination: domain.Pagination{NextCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0"},
+			expectedErr:        nil,
+		},
+		"success with num and before cursor": {
+			filter: domain.DepartmentFilter{Num: 2, Before: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0", WithTotal: true},
+			departmentRepo: map[string]testdata.FuncCall{
+				"Fetch": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{context.Background(), domain.DepartmentFilter{Num: 2, Before: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0", WithTotal: true}},
+					Output: []interface{}{[]domain.Department{department1, department2}, domain.Pagination{NextCursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt", Total: 3}, nil},
 				},
 			},
-			expectedRes:    []domain.Department{},
-			expectedCursor: "MHVqc3N6Z0Z2YmlFcjdDRGdFM3o4TUFVUEZ0",
-			expectedErr:    nil,
+			expectedRes:        []domain.Department{department1, department2},
+			expectedPagination: domain.Pagination{NextCursor: "MHVqc3N4aDBjRUN1dHF6TWdidFhTR25qb3Jt", Total: 3},
+			expectedErr:        nil,
 		},
 		"succes with ids": {
 			filter: domain.DepartmentFilter{IDs: []string{department1.ID, department3.ID}},
@@ -129,12 +142,12 @@ func TestFetch(t *testing.T) {
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.DepartmentFilter{IDs: []string{department1.ID, department3.ID}}},
-					Output: []interface{}{[]domain.Department{department1, department3}, "", nil},
+					Output: []interface{}{[]domain.Department{department1, department3}, domain.Pagination{}, nil},
 				},
 			},
-			expectedRes:    []domain.Department{department1, department3},
-			expectedCursor: "",
-			expectedErr:    nil,
+			expectedRes:        []domain.Department{department1, department3},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        nil,
 		},
 		"success with keyword": {
 			filter: domain.DepartmentFilter{Keyword: "marketing"},
@@ -142,12 +155,12 @@ func TestFetch(t *testing.T) {
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.DepartmentFilter{Keyword: "marketing"}},
-					Output: []interface{}{[]domain.Department{department1}, "", nil},
+					Output: []interface{}{[]domain.Department{department1}, domain.Pagination{}, nil},
 				},
 			},
-			expectedRes:    []domain.Department{department1},
-			expectedCursor: "",
-			expectedErr:    nil,
+			expectedRes:        []domain.Department{department1},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        nil,
 		},
 		"error fetch department": {
 			filter: domain.DepartmentFilter{Num: 2},
@@ -155,12 +168,12 @@ func TestFetch(t *testing.T) {
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.DepartmentFilter{Num: 2}},
-					Output: []interface{}{[]domain.Department{}, "", errors.New("unknown error")},
+					Output: []interface{}{[]domain.Department{}, domain.Pagination{}, errors.New("unknown error")},
 				},
 			},
-			expectedRes:    []domain.Department{},
-			expectedCursor: "",
-			expectedErr:    errors.New("failed to fetch departments: unknown error"),
+			expectedRes:        []domain.Department{},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        errors.New("failed to fetch departments: unknown error"),
 		},
 	}
 
@@ -173,7 +186,7 @@ func TestFetch(t *testing.T) {
 			}
 
 			departmentService := service.New(mockDepartmentRepo)
-			res, cursor, err := departmentService.Fetch(context.Background(), tc.filter)
+			res, pagination, err := departmentService.Fetch(context.Background(), tc.filter)
 
 			mockDepartmentRepo.AssertExpectations(t)
 
@@ -184,7 +197,7 @@ func TestFetch(t *testing.T) {
 
 			require.NoError(t, err)
 			require.Equal(t, res, tc.expectedRes)
-			require.Equal(t, cursor, tc.expectedCursor)
+			require.Equal(t, pagination, tc.expectedPagination)
 		})
 	}
 }
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/deadline"
+)
+
+// Timeout is a department service bounding every call of the next service which isn't bounded by its caller,
+// e.g. a call of a command
+type Timeout struct {
+	next     domain.DepartmentService
+	timeouts deadline.Timeouts
+}
+
+// NewTimeout will return a department service calling the next service with the timeout of each method
+func NewTimeout(next domain.DepartmentService, timeouts deadline.Timeouts) Timeout {
+	return Timeout{
+		next:     next,
+		timeouts: timeouts,
+	}
+}
+
+// Create will create a department with the timeout
+func (t Timeout) Create(ctx context.Context, d *domain.Department) (err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Create")
+	defer cancel()
+
+	return t.next.Create(ctx, d)
+}
+
+// Fetch will fetch departments with the timeout
+func (t Timeout) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, pagination domain.Pagination, err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Fetch")
+	defer cancel()
+
+	return t.next.Fetch(ctx, filter)
+}
+
+// Get will get a department with the timeout
+func (t Timeout) Get(ctx context.Context, departmentID string) (department domain.Department, err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Get")
+	defer cancel()
+
+	return t.next.Get(ctx, departmentID)
+}
+
+// Update will update a department with the timeout
+func (t Timeout) Update(ctx context.Context, d domain.Department) (department domain.Department, err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Update")
+	defer cancel()
+
+	return t.next.Update(ctx, d)
+}
+
+// Delete will delete a department with the timeout
+func (t Timeout) Delete(ctx context.Context, departmentID string) (err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Delete")
+	defer cancel()
+
+	return t.next.Delete(ctx, departmentID)
+}
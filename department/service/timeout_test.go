@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/department/service"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/deadline"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+func TestTimeout(t *testing.T) {
+	var department domain.Department
+	testdata.UnmarshallGoldenToJSON(t, "department-0ujssxh0cECutqzMgbtXSGnjorm", &department)
+	timeouts := deadline.Timeouts{Default: time.Second, Methods: map[string]time.Duration{"Fetch": time.Minute}}
+
+	t.Run("unbounded context", func(t *testing.T) {
+		next := new(mocks.DepartmentService)
+		next.On("Get", mock.Anything, department.ID).
+			Run(func(args mock.Arguments) {
+				dl, ok := args.Get(0).(context.Context).Deadline()
+				require.True(t, ok)
+				require.WithinDuration(t, time.Now().Add(time.Second), dl, 100*time.Millisecond)
+			}).
+			Return(department, nil).Once()
+
+		res, err := service.NewTimeout(next, timeouts).Get(context.Background(), department.ID)
+		require.NoError(t, err)
+		require.Equal(t, department, res)
+		next.AssertExpectations(t)
+	})
+
+	t.Run("bounded context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		expected, _ := ctx.Deadline()
+
+		next := new(mocks.DepartmentService)
+		next.On("Delete", mock.Anything, department.ID).
+			Run(func(args mock.Arguments) {
+				// the deadline of the caller is kept
+				dl, ok := args.Get(0).(context.Context).Deadline()
+				require.True(t, ok)
+				require.Equal(t, expected, dl)
+			}).
+			Return(nil).Once()
+
+		require.NoError(t, service.NewTimeout(next, timeouts).Delete(ctx, department.ID))
+		next.AssertExpectations(t)
+	})
+
+	t.Run("timeout of the method", func(t *testing.T) {
+		filter := domain.DepartmentFilter{Num: 10}
+		next := new(mocks.DepartmentService)
+		next.On("Fetch", mock.Anything, filter).
+			Run(func(args mock.Arguments) {
+				dl, ok := args.Get(0).(context.Context).Deadline()
+				require.True(t, ok)
+				require.WithinDuration(t, time.Now().Add(time.Minute), dl, 100*time.Millisecond)
+			}).
+			Return([]domain.Department{department}, domain.Pagination{}, nil).Once()
+
+		res, _, err := service.NewTimeout(next, timeouts).Fetch(context.Background(), filter)
+		require.NoError(t, err)
+		require.Equal(t, []domain.Department{department}, res)
+		next.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/tracing"
+)
+
+// Tracing is a department service starting a span of every call of the next service
+type Tracing struct {
+	next domain.DepartmentService
+}
+
+// NewTracing will return a department service tracing the next service
+func NewTracing(next domain.DepartmentService) Tracing {
+	return Tracing{
+		next: next,
+	}
+}
+
+// Create will trace creating a department
+func (t Tracing) Create(ctx context.Context, d *domain.Department) (err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Create(ctx, d)
+}
+
+// Fetch will trace fetching departments
+func (t Tracing) Fetch(ctx context.Context, filter domain.DepartmentFilter) (departments []domain.Department, pagination domain.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentService.Fetch")
+	defer func() { tracing.End(span, err) }()
+
+	departments, pagination, err = t.next.Fetch(ctx, filter)
+	span.SetAttributes(attribute.Int("department.count", len(departments)))
+
+	return
+}
+
+// Get will trace getting a department
+func (t Tracing) Get(ctx context.Context, departmentID string) (department domain.Department, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentService.Get", attribute.String("department.id", departmentID))
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Get(ctx, departmentID)
+}
+
+// Update will trace updating a department
+func (t Tracing) Update(ctx context.Context, d domain.Department) (department domain.Department, err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentService.Update", attribute.String("department.id", d.ID))
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Update(ctx, d)
+}
+
+// Delete will trace deleting a department
+func (t Tracing) Delete(ctx context.Context, departmentID string) (err error) {
+	ctx, span := tracing.Start(ctx, "DepartmentService.Delete", attribute.String("department.id", departmentID))
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Delete(ctx, departmentID)
+}
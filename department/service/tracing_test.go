@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/department/service"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var department domain.Department
+	testdata.UnmarshallGoldenToJSON(t, "department-0ujssxh0cECutqzMgbtXSGnjorm", &department)
+
+	next := new(mocks.DepartmentService)
+	next.On("Get", mock.Anything, department.ID).
+		Run(func(args mock.Arguments) {
+			// the next service is called within the span
+			require.True(t, trace.SpanFromContext(args.Get(0).(context.Context)).SpanContext().IsValid())
+		}).
+		Return(department, nil).Once()
+	next.On("Delete", mock.Anything, department.ID).Return(errors.New("connection refused")).Once()
+
+	s := service.NewTracing(next)
+
+	res, err := s.Get(context.Background(), department.ID)
+	require.NoError(t, err)
+	require.Equal(t, department, res)
+
+	err = s.Delete(context.Background(), department.ID)
+	require.EqualError(t, err, "connection refused")
+
+	next.AssertExpectations(t)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "DepartmentService.Get", spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, "DepartmentService.Delete", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// List of scopes which can be granted to an API key
+const (
+	ScopeDepartmentsRead  = "departments:read"
+	ScopeDepartmentsWrite = "departments:write"
+	ScopeEmployeesRead    = "employees:read"
+	ScopeEmployeesWrite   = "employees:write"
+)
+
+// APIKeyScopes is the whitelist of scopes of an API key
+var APIKeyScopes = []string{
+	ScopeDepartmentsRead,
+	ScopeDepartmentsWrite,
+	ScopeEmployeesRead,
+	ScopeEmployeesWrite,
+}
+
+// APIKey represent an API key of a machine client, only the hash of the secret is stored
+type APIKey struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name" validate:"required"`
+	Scopes       []string   `json:"scopes" validate:"required,min=1,dive,oneof=departments:read departments:write employees:read employees:write"`
+	HashedSecret string     `json:"-"`
+	TenantID     string     `json:"-"`
+	LastUsedTime *time.Time `json:"last_used_time"`
+	RevokedTime  *time.Time `json:"revoked_time"`
+	CreatedTime  time.Time  `json:"created_time"`
+	UpdatedTime  time.Time  `json:"updated_time"`
+}
+
+// IsRevoked reports whether the API key is revoked
+func (k APIKey) IsRevoked() bool {
+	return k.RevokedTime != nil
+}
+
+// APIKeyFilter represent query filter of API keys
+type APIKeyFilter struct {
+	Num         int
+	Cursor      string
+	WithRevoked bool
+}
+
+// APIKeyService represent service contract for API key.
+// The plain key is only returned on create and rotate, it is formatted as <id>.<secret>
+type APIKeyService interface {
+	Create(ctx context.Context, k *APIKey) (key string, err error)
+	Fetch(ctx context.Context, filter APIKeyFilter) (keys []APIKey, pagination Pagination, err error)
+	Get(ctx context.Context, id string) (k APIKey, err error)
+	Rotate(ctx context.Context, id string) (k APIKey, key string, err error)
+	Revoke(ctx context.Context, id string) (err error)
+	Authenticate(ctx context.Context, key string) (p Principal, err error)
+}
+
+// APIKeyRepository represent repository contract for API key, the keys are scoped to the tenant of the context
+type APIKeyRepository interface {
+	Create(ctx context.Context, k *APIKey) (err error)
+	Fetch(ctx context.Context, filter APIKeyFilter) (keys []APIKey, pagination Pagination, err error)
+	Get(ctx context.Context, id string) (k APIKey, err error)
+	// Lookup returns the key of any tenant, it is only used to authenticate a key before its tenant is known
+	Lookup(ctx context.Context, id string) (k APIKey, err error)
+	UpdateSecret(ctx context.Context, id, hashedSecret string) (err error)
+	Revoke(ctx context.Context, id string, revokedTime time.Time) (err error)
+	Touch(ctx context.Context, id string, lastUsedTime time.Time) (err error)
+}
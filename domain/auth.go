@@ -0,0 +1,69 @@
+package domain
+
+import "context"
+
+type principalContextKey struct{}
+
+// Role represent a role granted to a caller
+type Role string
+
+// List of supported roles
+const (
+	// RoleViewer may only read departments and employees
+	RoleViewer Role = "viewer"
+	// RoleHRAdmin may read and mutate every department and employee
+	RoleHRAdmin Role = "hr-admin"
+	// RoleDepartmentHead may read everything and mutate employees of their own department
+	RoleDepartmentHead Role = "department-head"
+	// RoleAdmin may manage API keys
+	RoleAdmin Role = "admin"
+	// RoleSuperAdmin may select the tenant of each request, it is only granted by the tokens of an external identity provider
+	RoleSuperAdmin Role = "super-admin"
+)
+
+// Principal represent an authenticated caller, a user is granted roles and an API key is granted scopes.
+// TenantID binds the caller to a tenant, see middleware.Tenant for the callers which aren't bound
+type Principal struct {
+	Subject      string
+	Roles        []Role
+	Scopes       []string
+	DepartmentID string
+	EmployeeID   string
+	TenantID     string
+	Claims       map[string]interface{}
+}
+
+// HasRole reports whether the principal is granted any of the roles
+func (p Principal) HasRole(roles ...Role) bool {
+	for _, granted := range p.Roles {
+		for _, r := range roles {
+			if granted == r {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasScope reports whether the principal is granted the scope
+func (p Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewContextWithPrincipal returns a new context carrying the principal
+func NewContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext returns the principal of the context, ok is false when the caller is not authenticated
+func PrincipalFromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(principalContextKey{}).(Principal)
+	return
+}
@@ -3,14 +3,30 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
 )
 
 // DepartmentFilter represent query filter
 type DepartmentFilter struct {
-	IDs     []string
-	Keyword string
-	Num     int
-	Cursor  string
+	IDs         []string
+	Keyword     string
+	Num         int
+	Cursor      string
+	Before      string
+	WithTotal   bool
+	CreatedTime TimeRange
+	UpdatedTime TimeRange
+	Expression  filterql.Expr
+}
+
+// DepartmentFilterFields is the whitelist of fields allowed in department filter expression
+var DepartmentFilterFields = filterql.Fields{
+	"id":           filterql.String,
+	"name":         filterql.String,
+	"description":  filterql.String,
+	"created_time": filterql.Time,
+	"updated_time": filterql.Time,
 }
 
 // Department represent department data
@@ -25,7 +41,7 @@ type Department struct {
 // DepartmentService represent service contract for department
 type DepartmentService interface {
 	Create(ctx context.Context, d *Department) (err error)
-	Fetch(ctx context.Context, filter DepartmentFilter) (departments []Department, nextCursor string, err error)
+	Fetch(ctx context.Context, filter DepartmentFilter) (departments []Department, pagination Pagination, err error)
 	Get(ctx context.Context, departmentID string) (department Department, err error)
 	Update(ctx context.Context, d Department) (department Department, err error)
 	Delete(ctx context.Context, departmentID string) (err error)
@@ -34,8 +50,11 @@ type DepartmentService interface {
 // DepartmentRepository represent repository contract for department
 type DepartmentRepository interface {
 	Create(ctx context.Context, d *Department) (err error)
-	Fetch(ctx context.Context, filter DepartmentFilter) (departments []Department, nextCursor string, err error)
+	Fetch(ctx context.Context, filter DepartmentFilter) (departments []Department, pagination Pagination, err error)
 	Get(ctx context.Context, departmentID string) (department Department, err error)
 	Update(ctx context.Context, d Department) (department Department, err error)
 	Delete(ctx context.Context, departmentID string) (err error)
+	// Upsert updates the departments of an id and inserts the others in a single transaction,
+	// ErrNotFound is returned when an id doesn't exist
+	Upsert(ctx context.Context, departments []Department) (created, updated int, err error)
 }
@@ -3,37 +3,80 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
 )
 
-//EmployeeFilter reqpresent query filter
+// EmployeeFilter reqpresent query filter
 type EmployeeFilter struct {
-	IDs     []string
-	Keyword string
-	Num     int
-	Cursor  string
-	DeptIDs []string
+	IDs         []string
+	Keyword     string
+	Num         int
+	Cursor      string
+	Before      string
+	WithTotal   bool
+	DeptIDs     []string
+	Titles      []string
+	BirthPlaces []string
+	DateOfBirth TimeRange
+	CreatedTime TimeRange
+	UpdatedTime TimeRange
+	Expression  filterql.Expr
+	Expand      []string
+}
+
+// EmployeeExpandDepartment is the relation name to resolve department of an employee
+const EmployeeExpandDepartment = "department"
+
+// EmployeeExpandFields is the whitelist of relations allowed in employee expand,
+// an employee has no manager so there is no manager relation
+var EmployeeExpandFields = []string{EmployeeExpandDepartment}
+
+// EmployeeFilterFields is the whitelist of fields allowed in employee filter expression
+var EmployeeFilterFields = filterql.Fields{
+	"id":            filterql.String,
+	"first_name":    filterql.String,
+	"last_name":     filterql.String,
+	"birth_place":   filterql.String,
+	"date_of_birth": filterql.Time,
+	"title":         filterql.String,
+	"dept_id":       filterql.String,
+	"created_time":  filterql.Time,
+	"updated_time":  filterql.Time,
 }
 
 // Employee represent employee data
 type Employee struct {
 	ID          string     `json:"id"`
-	FirstName   string     `json:"first_name"`
+	FirstName   string     `json:"first_name" validate:"required"`
 	LastName    string     `json:"last_name"`
 	BirthPlace  string     `json:"birth_place"`
-	DateOfBirth string     `json:"date_of_birth"`
+	DateOfBirth string     `json:"date_of_birth" validate:"required"`
 	Title       string     `json:"title"`
-	Department  Department `json:"department"`
+	Department  Department `json:"department" validate:"-"`
 	CreatedTime time.Time  `json:"created_time"`
 	UpdatedTime time.Time  `json:"updated_time"`
 }
 
+// EmployeeService represent service contract for employee
+type EmployeeService interface {
+	Create(ctx context.Context, e *Employee) (err error)
+	Fetch(ctx context.Context, filter EmployeeFilter) (employees []Employee, pagination Pagination, err error)
+	Get(ctx context.Context, employeeID string, expand []string) (employee Employee, err error)
+	Update(ctx context.Context, e Employee) (employee Employee, err error)
+	Delete(ctx context.Context, employeeID string) (err error)
+}
+
 // EmployeeRepository represent repository contract for employee
 type EmployeeRepository interface {
 	Create(ctx context.Context, e *Employee) (err error)
-	Fetch(ctx context.Context, filter EmployeeFilter) (employees []Employee, nextCursor string, err error)
+	Fetch(ctx context.Context, filter EmployeeFilter) (employees []Employee, pagination Pagination, err error)
 	Get(ctx context.Context, employeeID string) (employee Employee, err error)
 	Update(ctx context.Context, e Employee) (employee Employee, err error)
 	Delete(ctx context.Context, employeeID string) (err error)
+	// Upsert updates the employees of an id and inserts the others in a single transaction,
+	// ErrNotFound is returned when an id doesn't exist
+	Upsert(ctx context.Context, employees []Employee) (created, updated int, err error)
 }
 
 // SetDateOfBirth will set date of birth
@@ -3,6 +3,7 @@ package domain
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/friendsofgo/errors"
 )
@@ -13,6 +14,18 @@ var (
 
 	// ErrNotModified is thrown to the client when the cached copy of a partifulcar file is up to date with the server
 	ErrNotModified = errors.New("")
+
+	// ErrUnauthorized is an error message when the caller is not authenticated
+	ErrUnauthorized = errors.New("authentication is required")
+
+	// ErrForbidden is an error message when the caller is not allowed to perform the action
+	ErrForbidden = errors.New("action is forbidden")
+
+	// ErrNotAcceptable is an error message when none of the accepted media types is supported
+	ErrNotAcceptable = errors.New("none of the accepted media types is supported")
+
+	// ErrTooManyRequests is an error message when the caller exceeded its rate limit
+	ErrTooManyRequests = errors.New("rate limit is exceeded")
 )
 
 // ConstraintError representes a custom error for a constraint things
@@ -27,6 +40,28 @@ func ConstraintErrorf(format string, a ...interface{}) ConstraintError {
 	return ConstraintError(fmt.Sprintf(format, a...))
 }
 
+// FieldViolation is a field of a request which is not valid
+type FieldViolation struct {
+	// Field is the JSON path of the field, e.g. roles[1]
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field violation of a request
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+
+	return strings.Join(messages, ", ")
+}
+
 // ErrorFromResponseStatusCode generates error based on the status code from *http.Response.
 // For example, it will generate ErrNotFound when given status code is 404
 func ErrorFromResponseStatusCode(code int, message string) (err error) {
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Types of the domain events
+const (
+	EventDepartmentCreated   = "department.created"
+	EventDepartmentUpdated   = "department.updated"
+	EventDepartmentDeleted   = "department.deleted"
+	EventEmployeeCreated     = "employee.created"
+	EventEmployeeUpdated     = "employee.updated"
+	EventEmployeeTransferred = "employee.transferred"
+	EventEmployeeDeleted     = "employee.deleted"
+)
+
+// Aggregates the domain events are emitted for
+const (
+	AggregateDepartment = "department"
+	AggregateEmployee   = "employee"
+)
+
+// Event represent a change of a department or an employee, it is written to the outbox
+// in the same transaction as the change and published afterwards in the order it was written
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	TenantID      string          `json:"tenant_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredTime  time.Time       `json:"occurred_time"`
+	Data          json.RawMessage `json:"data"`
+	Attempts      int             `json:"-"`
+}
+
+// EmployeeTransfer is the data of EventEmployeeTransferred
+type EmployeeTransfer struct {
+	EmployeeID       string `json:"employee_id"`
+	FromDepartmentID string `json:"from_department_id"`
+	ToDepartmentID   string `json:"to_department_id"`
+}
+
+// EventFilter represent query filter of the event log
+type EventFilter struct {
+	// AggregateTypes are the aggregates of the events, every aggregate when empty
+	AggregateTypes []string
+	// DepartmentID only keeps the events of the department and of its employees
+	DepartmentID string
+	// AfterID only keeps the events written after the event, every event when empty
+	AfterID string
+	Num     int
+}
+
+// DepartmentIDs returns the departments an event is about, the department of an employee event
+// and both departments of a transfer
+func (e Event) DepartmentIDs() (ids []string) {
+	switch {
+	case e.AggregateType == AggregateDepartment:
+		return []string{e.AggregateID}
+	case e.Type == EventEmployeeTransferred:
+		var t EmployeeTransfer
+		if err := json.Unmarshal(e.Data, &t); err == nil {
+			ids = []string{t.FromDepartmentID, t.ToDepartmentID}
+		}
+	case e.AggregateType == AggregateEmployee:
+		var emp Employee
+		if err := json.Unmarshal(e.Data, &emp); err == nil {
+			ids = []string{emp.Department.ID}
+		}
+	}
+
+	return
+}
+
+// NewEvent creates an event of the tenant of the context, data is marshalled as JSON
+func NewEvent(ctx context.Context, eventType, aggregateType, aggregateID string, data interface{}) (e Event, err error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	e = Event{
+		ID:            ksuid.New().String(),
+		Type:          eventType,
+		TenantID:      TenantFromContext(ctx),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		OccurredTime:  time.Now(),
+		Data:          raw,
+	}
+
+	return
+}
+
+// Publisher publishes the domain events to the other systems
+type Publisher interface {
+	Publish(ctx context.Context, e Event) (err error)
+}
+
+// EventService represent service contract for the event log of the tenant
+type EventService interface {
+	// Fetch fetches the events of the filter, the cursor is the id of the last event read,
+	// the next events are fetched after it even when it is filtered out
+	Fetch(ctx context.Context, filter EventFilter) (events []Event, cursor string, err error)
+	// Latest returns the id of the latest event, it is empty when there is no event
+	Latest(ctx context.Context) (id string, err error)
+}
+
+// EventRepository represent repository contract for the event log of the tenant, it is the outbox
+type EventRepository interface {
+	// Fetch fetches the events of the aggregate types in the order they were written,
+	// ErrNotFound is returned when the event of AfterID doesn't exist
+	Fetch(ctx context.Context, filter EventFilter) (events []Event, err error)
+	Latest(ctx context.Context) (id string, err error)
+}
+
+// OutboxRepository represent repository contract for the events of the outbox
+type OutboxRepository interface {
+	// FetchPending fetches the oldest unpublished events which were attempted less than maxAttempts times
+	FetchPending(ctx context.Context, num, maxAttempts int) (events []Event, err error)
+	MarkPublished(ctx context.Context, eventID string, publishedTime time.Time) (err error)
+	// MarkFailed counts a failed attempt of publishing the event
+	MarkFailed(ctx context.Context, eventID string, reason string) (err error)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// TimeRange represent an inclusive time range filter, zero value means unbounded
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// IsZero reports whether the time range has no bound
+func (r TimeRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/friendsofgo/errors"
+)
+
+var (
+	// ErrIdempotencyKeyInUse is an error message when a request with the same idempotency key is in progress
+	ErrIdempotencyKeyInUse = errors.New("a request with the idempotency key is in progress")
+
+	// ErrIdempotencyKeyReused is an error message when an idempotency key is reused with another request
+	ErrIdempotencyKeyReused = errors.New("the idempotency key is already used with another request")
+)
+
+// IdempotencyRecord represent a request made with an idempotency key and its response.
+// The record is pending, without response, until the request completes
+type IdempotencyRecord struct {
+	// Scope isolates the keys of the callers and the routes
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	CreatedTime time.Time
+	ExpiresTime time.Time
+}
+
+// Completed tells whether the response of the request is stored
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// IdempotencyRepository represent repository contract for idempotency records
+type IdempotencyRepository interface {
+	// Reserve inserts a pending record, ErrIdempotencyKeyInUse is returned when the key of the scope exists
+	Reserve(ctx context.Context, r *IdempotencyRecord) (err error)
+	Get(ctx context.Context, scope, key string) (r IdempotencyRecord, err error)
+	Complete(ctx context.Context, r IdempotencyRecord) (err error)
+	Delete(ctx context.Context, scope, key string) (err error)
+	// DeleteExpired deletes the records expired before the time
+	DeleteExpired(ctx context.Context, before time.Time) (count int64, err error)
+}
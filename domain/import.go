@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Kinds of the rows of an import
+const (
+	ImportDepartments = "departments"
+	ImportEmployees   = "employees"
+)
+
+// Statuses of an import
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportSucceeded = "succeeded"
+	ImportFailed    = "failed"
+)
+
+// Import represent a CSV import of departments or employees which is run in the background
+type Import struct {
+	ID           string       `json:"id"`
+	Kind         string       `json:"kind"`
+	Status       string       `json:"status"`
+	DryRun       bool         `json:"dry_run"`
+	Report       ImportReport `json:"report"`
+	Error        string       `json:"error,omitempty"`
+	TenantID     string       `json:"-"`
+	CreatedTime  time.Time    `json:"created_time"`
+	UpdatedTime  time.Time    `json:"updated_time"`
+	FinishedTime *time.Time   `json:"finished_time"`
+}
+
+// ImportReport is the outcome of an import, in a dry run Created and Updated are the rows which would be written
+type ImportReport struct {
+	Rows    int              `json:"rows"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// ImportRowError is a violation of a row, Row is numbered as in a spreadsheet where the header is row 1
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportService represent service contract for the imports
+type ImportService interface {
+	// Run imports the rows of the CSV, nothing is written in a dry run or when a row is not valid
+	Run(ctx context.Context, kind string, r io.Reader, dryRun bool) (report ImportReport, err error)
+	// Create schedules an import of the CSV
+	Create(ctx context.Context, imp *Import, data []byte) (err error)
+	Get(ctx context.Context, id string) (imp Import, err error)
+}
+
+// ImportRepository represent repository contract for the imports
+type ImportRepository interface {
+	Create(ctx context.Context, imp *Import, data []byte) (err error)
+	Get(ctx context.Context, id string) (imp Import, err error)
+	// Claim marks the oldest pending import of every tenant as running and returns it with its CSV,
+	// a running import claimed more than runTimeout ago, e.g. by a worker which stopped, is claimed again.
+	// ErrNotFound is returned when there is no import to claim
+	Claim(ctx context.Context, runTimeout time.Duration) (imp Import, data []byte, err error)
+	Update(ctx context.Context, imp Import) (err error)
+}
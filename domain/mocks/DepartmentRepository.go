@@ -43,7 +43,7 @@ func (_m *DepartmentRepository) Delete(ctx context.Context, departmentID string)
 }
 
 // Fetch provides a mock function with given fields: ctx, filter
-func (_m *DepartmentRepository) Fetch(ctx context.Context, filter domain.DepartmentFilter) ([]domain.Department, string, error) {
+func (_m *DepartmentRepository) Fetch(ctx context.Context, filter domain.DepartmentFilter) ([]domain.Department, domain.Pagination, error) {
 	ret := _m.Called(ctx, filter)
 
 	var r0 []domain.Department
@@ -55,11 +55,11 @@ func (_m *DepartmentRepository) Fetch(ctx context.Context, filter domain.Departm
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, domain.DepartmentFilter) string); ok {
+	var r1 domain.Pagination
+	if rf, ok := ret.Get(1).(func(context.Context, domain.DepartmentFilter) domain.Pagination); ok {
 		r1 = rf(ctx, filter)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.Pagination)
 	}
 
 	var r2 error
@@ -113,3 +113,31 @@ func (_m *DepartmentRepository) Update(ctx context.Context, d domain.Department)
 
 	return r0, r1
 }
+
+// Upsert provides a mock function with given fields: ctx, departments
+func (_m *DepartmentRepository) Upsert(ctx context.Context, departments []domain.Department) (int, int, error) {
+	ret := _m.Called(ctx, departments)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Department) int); ok {
+		r0 = rf(ctx, departments)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.Department) int); ok {
+		r1 = rf(ctx, departments)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, []domain.Department) error); ok {
+		r2 = rf(ctx, departments)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
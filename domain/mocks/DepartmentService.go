@@ -43,7 +43,7 @@ func (_m *DepartmentService) Delete(ctx context.Context, departmentID string) er
 }
 
 // Fetch provides a mock function with given fields: ctx, filter
-func (_m *DepartmentService) Fetch(ctx context.Context, filter domain.DepartmentFilter) ([]domain.Department, string, error) {
+func (_m *DepartmentService) Fetch(ctx context.Context, filter domain.DepartmentFilter) ([]domain.Department, domain.Pagination, error) {
 	ret := _m.Called(ctx, filter)
 
 	var r0 []domain.Department
@@ -55,11 +55,11 @@ func (_m *DepartmentService) Fetch(ctx context.Context, filter domain.Department
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, domain.DepartmentFilter) string); ok {
+	var r1 domain.Pagination
+	if rf, ok := ret.Get(1).(func(context.Context, domain.DepartmentFilter) domain.Pagination); ok {
 		r1 = rf(ctx, filter)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.Pagination)
 	}
 
 	var r2 error
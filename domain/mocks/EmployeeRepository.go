@@ -43,7 +43,7 @@ func (_m *EmployeeRepository) Delete(ctx context.Context, employeeID string) err
 }
 
 // Fetch provides a mock function with given fields: ctx, filter
-func (_m *EmployeeRepository) Fetch(ctx context.Context, filter domain.EmployeeFilter) ([]domain.Employee, string, error) {
+func (_m *EmployeeRepository) Fetch(ctx context.Context, filter domain.EmployeeFilter) ([]domain.Employee, domain.Pagination, error) {
 	ret := _m.Called(ctx, filter)
 
 	var r0 []domain.Employee
@@ -55,11 +55,11 @@ func (_m *EmployeeRepository) Fetch(ctx context.Context, filter domain.EmployeeF
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, domain.EmployeeFilter) string); ok {
+	var r1 domain.Pagination
+	if rf, ok := ret.Get(1).(func(context.Context, domain.EmployeeFilter) domain.Pagination); ok {
 		r1 = rf(ctx, filter)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.Pagination)
 	}
 
 	var r2 error
@@ -113,3 +113,31 @@ func (_m *EmployeeRepository) Update(ctx context.Context, e domain.Employee) (do
 
 	return r0, r1
 }
+
+// Upsert provides a mock function with given fields: ctx, employees
+func (_m *EmployeeRepository) Upsert(ctx context.Context, employees []domain.Employee) (int, int, error) {
+	ret := _m.Called(ctx, employees)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Employee) int); ok {
+		r0 = rf(ctx, employees)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.Employee) int); ok {
+		r1 = rf(ctx, employees)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, []domain.Employee) error); ok {
+		r2 = rf(ctx, employees)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Pagination represent cursors and total items of a fetched page
+type Pagination struct {
+	NextCursor string
+	PrevCursor string
+	Total      int
+}
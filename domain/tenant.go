@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+type tenantContextKey struct{}
+
+// DefaultTenantID is the tenant of a single tenant deployment and of the rows created before multi-tenancy
+const DefaultTenantID = "default"
+
+// NewContextWithTenant returns a new context carrying the tenant
+func NewContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant of the context, it is DefaultTenantID when the context carries no tenant
+func TenantFromContext(ctx context.Context) (tenantID string) {
+	tenantID, _ = ctx.Value(tenantContextKey{}).(string)
+	if tenantID == "" {
+		tenantID = DefaultTenantID
+	}
+
+	return
+}
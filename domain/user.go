@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// User represent an account of the local identity provider, every user is an employee of its tenant.
+// Password is only read on create, only the bcrypt hash of it is stored
+type User struct {
+	ID             string    `json:"id"`
+	EmployeeID     string    `json:"employee_id" validate:"required"`
+	Email          string    `json:"email" validate:"required,email"`
+	Password       string    `json:"password,omitempty" validate:"required,min=8"`
+	HashedPassword string    `json:"-"`
+	TenantID       string    `json:"-"`
+	Roles          []Role    `json:"roles" validate:"required,min=1,dive,oneof=viewer hr-admin department-head admin"`
+	CreatedTime    time.Time `json:"created_time"`
+	UpdatedTime    time.Time `json:"updated_time"`
+}
+
+// TokenPair represent the tokens issued to a user on login and refresh
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshToken represent a refresh token of a user, only the hash of the secret is stored.
+// A refresh token is revoked once it is exchanged, see AuthService.Refresh
+type RefreshToken struct {
+	ID          string
+	UserID      string
+	TenantID    string
+	HashedToken string
+	ExpiresTime time.Time
+	RevokedTime *time.Time
+	CreatedTime time.Time
+}
+
+// PasswordResetToken represent a single use token to reset the password of a user
+type PasswordResetToken struct {
+	ID          string
+	UserID      string
+	HashedToken string
+	ExpiresTime time.Time
+	UsedTime    *time.Time
+	CreatedTime time.Time
+}
+
+// UserService represent service contract for user
+type UserService interface {
+	Create(ctx context.Context, u *User) (err error)
+	Get(ctx context.Context, id string) (u User, err error)
+}
+
+// UserRepository represent repository contract for user, the users are created in and got from the tenant of the context.
+// The email is unique across the tenants, so a user is got by email before its tenant is known
+type UserRepository interface {
+	Create(ctx context.Context, u *User) (err error)
+	Get(ctx context.Context, id string) (u User, err error)
+	GetByEmail(ctx context.Context, email string) (u User, err error)
+	UpdatePassword(ctx context.Context, id, hashedPassword string) (err error)
+}
+
+// AuthService represent service contract for login and token issuance of the local identity provider.
+// Refresh and password reset tokens are formatted as <id>.<secret>
+type AuthService interface {
+	Login(ctx context.Context, email, password string) (tokens TokenPair, err error)
+	Refresh(ctx context.Context, refreshToken string) (tokens TokenPair, err error)
+	Logout(ctx context.Context, refreshToken string) (err error)
+	RequestPasswordReset(ctx context.Context, email string) (err error)
+	ResetPassword(ctx context.Context, token, password string) (err error)
+	Verify(token string) (p Principal, err error)
+}
+
+// AuthTokenRepository represent repository contract for refresh tokens, revoked access tokens and password reset tokens
+type AuthTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, t *RefreshToken) (err error)
+	GetRefreshToken(ctx context.Context, id string) (t RefreshToken, err error)
+	RevokeRefreshToken(ctx context.Context, id string, revokedTime time.Time) (err error)
+	RevokeUserRefreshTokens(ctx context.Context, userID string, revokedTime time.Time) (err error)
+	RevokeAccessToken(ctx context.Context, id string, expiresTime time.Time) (err error)
+	IsAccessTokenRevoked(ctx context.Context, id string) (revoked bool, err error)
+	CreatePasswordResetToken(ctx context.Context, t *PasswordResetToken) (err error)
+	GetPasswordResetToken(ctx context.Context, id string) (t PasswordResetToken, err error)
+	UsePasswordResetToken(ctx context.Context, id string, usedTime time.Time) (err error)
+}
+
+// PasswordResetNotifier delivers a password reset token to the user
+type PasswordResetNotifier interface {
+	NotifyPasswordReset(ctx context.Context, u User, token string) (err error)
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// EventTypes is the whitelist of event types a webhook can subscribe to,
+// a webhook can also subscribe to every event of an aggregate with e.g. employee.* or to every event with *
+var EventTypes = []string{
+	EventDepartmentCreated,
+	EventDepartmentUpdated,
+	EventDepartmentDeleted,
+	EventEmployeeCreated,
+	EventEmployeeUpdated,
+	EventEmployeeTransferred,
+	EventEmployeeDeleted,
+}
+
+// Statuses of a webhook delivery
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryDead is the status of a delivery given up after its last attempt, it can be redelivered
+	WebhookDeliveryDead = "dead"
+)
+
+// Webhook represent a subscription of a partner to the domain events of its tenant.
+// The secret signs the deliveries, it is only returned on create
+type Webhook struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url" validate:"required,url,max=2048"`
+	Events      []string  `json:"events" validate:"required,min=1"`
+	Secret      string    `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	CreatedTime time.Time `json:"created_time"`
+	UpdatedTime time.Time `json:"updated_time"`
+}
+
+// Subscribes reports whether the webhook subscribes to the event type
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, pattern := range w.Events {
+		if pattern == "*" || pattern == eventType ||
+			(strings.HasSuffix(pattern, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookDelivery represent the delivery of an event to a webhook and its attempts
+type WebhookDelivery struct {
+	ID              string          `json:"id"`
+	WebhookID       string          `json:"webhook_id"`
+	TenantID        string          `json:"-"`
+	EventID         string          `json:"event_id"`
+	EventType       string          `json:"event_type"`
+	Payload         json.RawMessage `json:"-"`
+	Status          string          `json:"status"`
+	Attempts        int             `json:"attempts"`
+	ResponseStatus  int             `json:"response_status,omitempty"`
+	LastError       string          `json:"last_error,omitempty"`
+	NextAttemptTime *time.Time      `json:"next_attempt_time,omitempty"`
+	CreatedTime     time.Time       `json:"created_time"`
+	UpdatedTime     time.Time       `json:"updated_time"`
+}
+
+// WebhookFilter represent query filter of webhooks
+type WebhookFilter struct {
+	Num    int
+	Cursor string
+}
+
+// WebhookDeliveryFilter represent query filter of the deliveries of a webhook
+type WebhookDeliveryFilter struct {
+	Num    int
+	Cursor string
+	Status string
+}
+
+// WebhookService represent service contract for webhook
+type WebhookService interface {
+	// Create creates a webhook, a secret is generated when none is given
+	Create(ctx context.Context, w *Webhook) (err error)
+	Fetch(ctx context.Context, filter WebhookFilter) (webhooks []Webhook, pagination Pagination, err error)
+	Get(ctx context.Context, id string) (w Webhook, err error)
+	Delete(ctx context.Context, id string) (err error)
+	FetchDeliveries(ctx context.Context, webhookID string, filter WebhookDeliveryFilter) (deliveries []WebhookDelivery, pagination Pagination, err error)
+	// Redeliver schedules a delivery again, e.g. a dead one
+	Redeliver(ctx context.Context, webhookID, deliveryID string) (d WebhookDelivery, err error)
+}
+
+// WebhookRepository represent repository contract for webhook
+type WebhookRepository interface {
+	Create(ctx context.Context, w *Webhook) (err error)
+	Fetch(ctx context.Context, filter WebhookFilter) (webhooks []Webhook, pagination Pagination, err error)
+	Get(ctx context.Context, id string) (w Webhook, err error)
+	Delete(ctx context.Context, id string) (err error)
+	// FetchSubscribers fetches the webhooks of the tenant subscribing to the event type
+	FetchSubscribers(ctx context.Context, eventType string) (webhooks []Webhook, err error)
+}
+
+// WebhookDeliveryRepository represent repository contract for webhook delivery
+type WebhookDeliveryRepository interface {
+	// Create inserts a delivery, the delivery of an event to a webhook is only inserted once
+	Create(ctx context.Context, d *WebhookDelivery) (err error)
+	Fetch(ctx context.Context, webhookID string, filter WebhookDeliveryFilter) (deliveries []WebhookDelivery, pagination Pagination, err error)
+	Get(ctx context.Context, webhookID, id string) (d WebhookDelivery, err error)
+	// FetchDue fetches the pending deliveries of every tenant which are due at the time
+	FetchDue(ctx context.Context, now time.Time, num int) (deliveries []WebhookDelivery, err error)
+	// Update stores the outcome of an attempt
+	Update(ctx context.Context, d WebhookDelivery) (err error)
+}
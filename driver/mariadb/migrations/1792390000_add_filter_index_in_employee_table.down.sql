@@ -0,0 +1,6 @@
+DROP INDEX `deptId_idx` ON `employees`;
+DROP INDEX `title_idx` ON `employees`;
+DROP INDEX `birthPlace_idx` ON `employees`;
+DROP INDEX `dateOfBirth_idx` ON `employees`;
+DROP INDEX `createdTime_idx` ON `employees`;
+DROP INDEX `updatedTime_idx` ON `employees`;
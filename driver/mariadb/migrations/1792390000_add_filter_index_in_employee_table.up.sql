@@ -0,0 +1,6 @@
+CREATE INDEX `deptId_idx` ON `employees` (`dept_id`);
+CREATE INDEX `title_idx` ON `employees` (`title`);
+CREATE INDEX `birthPlace_idx` ON `employees` (`birth_place`);
+CREATE INDEX `dateOfBirth_idx` ON `employees` (`date_of_birth`);
+CREATE INDEX `createdTime_idx` ON `employees` (`created_time`);
+CREATE INDEX `updatedTime_idx` ON `employees` (`updated_time`);
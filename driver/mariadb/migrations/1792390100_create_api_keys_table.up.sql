@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `api_keys` (
+    `id` varchar (50) NOT NULL,
+    `name` varchar(200) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `scopes` varchar(250) NOT NULL DEFAULT '',
+    `hashed_secret` char(64) NOT NULL,
+    `last_used_time` timestamp NULL,
+    `revoked_time` timestamp NULL,
+    `created_time` timestamp NULL,
+    `updated_time` timestamp NULL,
+    PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
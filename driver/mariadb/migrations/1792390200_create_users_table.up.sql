@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` varchar (50) NOT NULL,
+    `employee_id` varchar (50) NOT NULL,
+    `email` varchar(250) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `hashed_password` varchar(100) NOT NULL,
+    `roles` varchar(250) NOT NULL DEFAULT '',
+    `created_time` timestamp NULL,
+    `updated_time` timestamp NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `email_idx` (`email`),
+    UNIQUE KEY `employeeId_idx` (`employee_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `password_reset_tokens`;
+DROP TABLE IF EXISTS `revoked_tokens`;
+DROP TABLE IF EXISTS `refresh_tokens`;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS `refresh_tokens` (
+    `id` varchar (50) NOT NULL,
+    `user_id` varchar (50) NOT NULL,
+    `hashed_token` char(64) NOT NULL,
+    `expires_time` timestamp NULL,
+    `revoked_time` timestamp NULL,
+    `created_time` timestamp NULL,
+    PRIMARY KEY (`id`),
+    KEY `userId_idx` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+CREATE TABLE IF NOT EXISTS `revoked_tokens` (
+    `id` varchar (100) NOT NULL,
+    `expires_time` timestamp NULL,
+    PRIMARY KEY (`id`),
+    KEY `expiresTime_idx` (`expires_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+CREATE TABLE IF NOT EXISTS `password_reset_tokens` (
+    `id` varchar (50) NOT NULL,
+    `user_id` varchar (50) NOT NULL,
+    `hashed_token` char(64) NOT NULL,
+    `expires_time` timestamp NULL,
+    `used_time` timestamp NULL,
+    `created_time` timestamp NULL,
+    PRIMARY KEY (`id`),
+    KEY `userId_idx` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
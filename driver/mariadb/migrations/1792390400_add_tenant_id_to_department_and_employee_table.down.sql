@@ -0,0 +1,5 @@
+DROP INDEX `tenantIdDeptId_idx` ON `employees`;
+DROP INDEX `tenantId_idx` ON `employees`;
+DROP INDEX `tenantId_idx` ON `departments`;
+ALTER TABLE `employees` DROP COLUMN `tenant_id`;
+ALTER TABLE `departments` DROP COLUMN `tenant_id`;
@@ -0,0 +1,5 @@
+ALTER TABLE `departments` ADD COLUMN `tenant_id` varchar(50) NOT NULL DEFAULT 'default' AFTER `id`;
+ALTER TABLE `employees` ADD COLUMN `tenant_id` varchar(50) NOT NULL DEFAULT 'default' AFTER `id`;
+CREATE INDEX `tenantId_idx` ON `departments` (`tenant_id`, `id`);
+CREATE INDEX `tenantId_idx` ON `employees` (`tenant_id`, `id`);
+CREATE INDEX `tenantIdDeptId_idx` ON `employees` (`tenant_id`, `dept_id`);
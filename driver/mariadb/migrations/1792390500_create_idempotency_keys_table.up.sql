@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+    `scope` varchar (255) NOT NULL,
+    `idempotency_key` varchar (255) NOT NULL,
+    `request_hash` char(64) NOT NULL,
+    `status_code` int NULL,
+    `response_header` text NULL,
+    `response_body` mediumblob NULL,
+    `created_time` timestamp NULL,
+    `expires_time` timestamp NULL,
+    PRIMARY KEY (`scope`, `idempotency_key`),
+    KEY `expiresTime_idx` (`expires_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `outbox_events` (
+    `seq` bigint NOT NULL AUTO_INCREMENT,
+    `id` varchar (27) NOT NULL,
+    `tenant_id` varchar (50) NOT NULL,
+    `event_type` varchar (64) NOT NULL,
+    `aggregate_type` varchar (32) NOT NULL,
+    `aggregate_id` varchar (27) NOT NULL,
+    `data` mediumtext NOT NULL,
+    `occurred_time` timestamp NULL,
+    `published_time` timestamp NULL,
+    `attempts` int NOT NULL DEFAULT 0,
+    `last_error` text NULL,
+    PRIMARY KEY (`seq`),
+    UNIQUE KEY `id_uq` (`id`),
+    KEY `publishedTime_idx` (`published_time`, `seq`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS `webhooks` (
+    `id` varchar (27) NOT NULL,
+    `tenant_id` varchar (50) NOT NULL,
+    `url` varchar (2048) NOT NULL,
+    `events` text NOT NULL,
+    `secret` varchar (255) NOT NULL,
+    `created_time` timestamp NULL,
+    `updated_time` timestamp NULL,
+    PRIMARY KEY (`id`),
+    KEY `tenantId_idx` (`tenant_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+    `id` varchar (27) NOT NULL,
+    `webhook_id` varchar (27) NOT NULL,
+    `tenant_id` varchar (50) NOT NULL,
+    `event_id` varchar (27) NOT NULL,
+    `event_type` varchar (64) NOT NULL,
+    `payload` mediumtext NOT NULL,
+    `status` varchar (16) NOT NULL,
+    `attempts` int NOT NULL DEFAULT 0,
+    `response_status` int NULL,
+    `last_error` text NULL,
+    `next_attempt_time` timestamp NULL,
+    `created_time` timestamp NULL,
+    `updated_time` timestamp NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `webhookIdEventId_uq` (`webhook_id`, `event_id`),
+    KEY `webhookId_idx` (`webhook_id`, `id`),
+    KEY `statusNextAttemptTime_idx` (`status`, `next_attempt_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `imports` (
+    `id` varchar (27) NOT NULL,
+    `tenant_id` varchar (50) NOT NULL,
+    `kind` varchar (16) NOT NULL,
+    `status` varchar (16) NOT NULL,
+    `dry_run` tinyint (1) NOT NULL DEFAULT 0,
+    `data` mediumtext NOT NULL,
+    `report` mediumtext NULL,
+    `error` text NULL,
+    `created_time` timestamp NULL,
+    `updated_time` timestamp NULL,
+    `finished_time` timestamp NULL,
+    PRIMARY KEY (`id`),
+    KEY `tenantId_idx` (`tenant_id`, `id`),
+    KEY `status_idx` (`status`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
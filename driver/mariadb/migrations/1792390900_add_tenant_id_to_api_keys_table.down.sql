@@ -0,0 +1,2 @@
+DROP INDEX `tenantId_idx` ON `api_keys`;
+ALTER TABLE `api_keys` DROP COLUMN `tenant_id`;
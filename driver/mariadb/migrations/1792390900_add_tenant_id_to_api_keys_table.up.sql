@@ -0,0 +1,2 @@
+ALTER TABLE `api_keys` ADD COLUMN `tenant_id` varchar(50) NOT NULL DEFAULT 'default' AFTER `id`;
+CREATE INDEX `tenantId_idx` ON `api_keys` (`tenant_id`, `id`);
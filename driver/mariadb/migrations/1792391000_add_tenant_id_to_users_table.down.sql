@@ -0,0 +1,3 @@
+DROP INDEX `tenantId_idx` ON `users`;
+ALTER TABLE `refresh_tokens` DROP COLUMN `tenant_id`;
+ALTER TABLE `users` DROP COLUMN `tenant_id`;
@@ -0,0 +1,3 @@
+ALTER TABLE `users` ADD COLUMN `tenant_id` varchar(50) NOT NULL DEFAULT 'default' AFTER `id`;
+ALTER TABLE `refresh_tokens` ADD COLUMN `tenant_id` varchar(50) NOT NULL DEFAULT 'default' AFTER `id`;
+CREATE INDEX `tenantId_idx` ON `users` (`tenant_id`, `id`);
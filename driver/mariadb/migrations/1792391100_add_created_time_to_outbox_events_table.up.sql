@@ -0,0 +1 @@
+ALTER TABLE `outbox_events` ADD COLUMN `created_time` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) AFTER `occurred_time`;
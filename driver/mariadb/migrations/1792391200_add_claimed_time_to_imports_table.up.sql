@@ -0,0 +1 @@
+ALTER TABLE `imports` ADD COLUMN `claimed_time` timestamp NULL AFTER `updated_time`;
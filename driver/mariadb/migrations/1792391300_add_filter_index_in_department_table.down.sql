@@ -0,0 +1,2 @@
+DROP INDEX `createdTime_idx` ON `departments`;
+DROP INDEX `updatedTime_idx` ON `departments`;
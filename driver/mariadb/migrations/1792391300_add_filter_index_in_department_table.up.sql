@@ -0,0 +1,2 @@
+CREATE INDEX `createdTime_idx` ON `departments` (`created_time`);
+CREATE INDEX `updatedTime_idx` ON `departments` (`updated_time`);
@@ -0,0 +1,269 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	pkgCursor "github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/fieldset"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/md5"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/render"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/timerange"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
+)
+
+type employeeHandler struct {
+	service domain.EmployeeService
+}
+
+// AddEmployeeHandler adds the employee handler
+func AddEmployeeHandler(e *echo.Echo, service domain.EmployeeService) {
+	if service == nil {
+		panic("http: nil employee service")
+	}
+
+	handler := &employeeHandler{service}
+
+	e.POST("/employees", handler.Insert)
+	e.GET("/employees/:id", handler.Get)
+	e.GET("/employees", handler.Fetch)
+	e.PUT("/employees/:id", handler.Update)
+	e.DELETE("/employees/:id", handler.Delete)
+}
+
+func (h employeeHandler) Insert(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var employee domain.Employee
+	if err := c.Bind(&employee); err != nil {
+		return err
+	}
+
+	if err := validator.Validate(employee); err != nil {
+		return err
+	}
+
+	err := h.service.Create(ctx, &employee)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert an employee")
+	}
+
+	return c.JSON(http.StatusCreated, employee)
+}
+
+func (h employeeHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	employeeID := c.Param("id")
+
+	fields, expand, err := parseFieldsAndExpand(c)
+	if err != nil {
+		return err
+	}
+
+	employee, err := h.service.Get(ctx, employeeID, expand)
+	if err != nil {
+		return errors.Wrap(err, "failed get an employee")
+	}
+
+	projected, err := fields.Apply(employee)
+	if err != nil {
+		return errors.Wrap(err, "error project employee fields")
+	}
+
+	return render.Render(c, http.StatusOK, "employee", projected)
+}
+
+func (h employeeHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	keyword := c.QueryParam("keyword")
+	cursor := c.QueryParam("cursor")
+	before := c.QueryParam("before")
+
+	num := 20
+	if numStr := c.QueryParam("num"); numStr != "" {
+		var err error
+		if num, err = strconv.Atoi(numStr); err != nil {
+			err = fmt.Errorf("num query-param is not valid. Got error when parsing value: %v", err)
+			return domain.ConstraintErrorf("%s", err)
+		}
+	}
+
+	withTotal := false
+	if totalStr := c.QueryParam("total"); totalStr != "" {
+		var err error
+		if withTotal, err = strconv.ParseBool(totalStr); err != nil {
+			err = fmt.Errorf("total query-param is not valid. Got error when parsing value: %v", err)
+			return domain.ConstraintErrorf("%s", err)
+		}
+	}
+
+	dateOfBirth, err := timerange.FromQuery(c, "dateOfBirthFrom", "dateOfBirthTo")
+	if err != nil {
+		return err
+	}
+
+	createdTime, err := timerange.FromQuery(c, "createdFrom", "createdTo")
+	if err != nil {
+		return err
+	}
+
+	updatedTime, err := timerange.FromQuery(c, "updatedFrom", "updatedTo")
+	if err != nil {
+		return err
+	}
+
+	var expression filterql.Expr
+	if rawFilter := c.QueryParam("filter"); rawFilter != "" {
+		if expression, err = filterql.Parse(rawFilter, domain.EmployeeFilterFields); err != nil {
+			return domain.ConstraintErrorf("filter query-param is not valid: %v", err)
+		}
+	}
+
+	fields, expand, err := parseFieldsAndExpand(c)
+	if err != nil {
+		return err
+	}
+
+	filter := domain.EmployeeFilter{
+		IDs:         splitQueryParam(c, "ids"),
+		Keyword:     keyword,
+		Num:         num,
+		Cursor:      cursor,
+		Before:      before,
+		WithTotal:   withTotal,
+		DeptIDs:     splitQueryParam(c, "deptIds"),
+		Titles:      splitQueryParam(c, "titles"),
+		BirthPlaces: splitQueryParam(c, "birthPlaces"),
+		DateOfBirth: dateOfBirth,
+		CreatedTime: createdTime,
+		UpdatedTime: updatedTime,
+		Expression:  expression,
+		Expand:      expand,
+	}
+
+	res, pagination, err := h.service.Fetch(ctx, filter)
+	if err != nil {
+		return errors.Wrap(err, "error fetch employees")
+	}
+
+	if len(res) > 0 {
+		eTag := ""
+		if eTag, err = md5.Generate(res[0].ID); err != nil {
+			return errors.Wrap(err, "error generate employees eTag")
+		}
+
+		ifNoneMatch := c.Request().Header.Get("If-None-Match")
+		if eTag != "" && ifNoneMatch != "" && strings.Contains(ifNoneMatch, eTag) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		c.Response().Header().Set("ETag", "W/"+eTag)
+		c.Response().Header().Set("X-Cursor", pagination.NextCursor)
+	}
+
+	if link := pkgCursor.LinkHeader(*c.Request().URL, pagination.NextCursor, pagination.PrevCursor); link != "" {
+		c.Response().Header().Set("Link", link)
+	}
+
+	if withTotal {
+		c.Response().Header().Set("X-Total-Count", strconv.Itoa(pagination.Total))
+	}
+
+	projected, err := fields.Apply(res)
+	if err != nil {
+		return errors.Wrap(err, "error project employees fields")
+	}
+
+	return render.Render(c, http.StatusOK, "employee", projected)
+}
+
+func (h employeeHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	employeeID := c.Param("id")
+
+	var employee domain.Employee
+	if err := c.Bind(&employee); err != nil {
+		return err
+	}
+
+	if err := validator.Validate(employee); err != nil {
+		return err
+	}
+
+	employee.ID = employeeID
+
+	res, err := h.service.Update(ctx, employee)
+	if err != nil {
+		return errors.Wrap(err, "failed to update an employee")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h employeeHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	employeeID := c.Param("id")
+
+	err := h.service.Delete(ctx, employeeID)
+	if err != nil {
+		return errors.Wrap(err, "failed delete an employee")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func splitQueryParam(c echo.Context, name string) []string {
+	values := make([]string, 0)
+	if param := c.QueryParam(name); param != "" {
+		values = strings.Split(param, ",")
+	}
+
+	return values
+}
+
+// parseFieldsAndExpand parses fields and expand query-param.
+// When expand is not given, department is expanded unless the fields only select department.id
+func parseFieldsAndExpand(c echo.Context) (fields fieldset.Fieldset, expand []string, err error) {
+	if fields, err = fieldset.Parse(c.QueryParam("fields"), domain.Employee{}); err != nil {
+		err = domain.ConstraintErrorf("fields query-param is not valid: %v", err)
+		return
+	}
+
+	if _, ok := c.QueryParams()["expand"]; !ok {
+		expand = make([]string, 0)
+		department, _ := fields.Lookup("department")
+		if fields.Has("department") && !(len(department.Nested) == 1 && fields.Has("department.id")) {
+			expand = append(expand, domain.EmployeeExpandDepartment)
+		}
+		return
+	}
+
+	expand = splitQueryParam(c, "expand")
+	for _, relation := range expand {
+		if !isExpandable(relation) {
+			err = domain.ConstraintErrorf("expand query-param is not valid: unknown relation %s, the relations are %s",
+				relation, strings.Join(domain.EmployeeExpandFields, ", "))
+			return
+		}
+	}
+
+	return
+}
+
+func isExpandable(relation string) bool {
+	for _, v := range domain.EmployeeExpandFields {
+		if v == relation {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,626 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	handler "github.com/milhamhidayat/golang-clean-code-v2/employee/delivery/http"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+func TestInsert(t *testing.T) {
+	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
+
+	var mockEmployee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &mockEmployee)
+	rawMockEmployee := testdata.GetGolden(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW")
+
+	tests := map[string]struct {
+		reqBody         []byte
+		employeeService testdata.FuncCall
+		expectedStatus  int
+	}{
+		"success": {
+			reqBody: rawMockEmployee,
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, &mockEmployee},
+				Output: []interface{}{nil},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		"invalid request body": {
+			reqBody:         []byte(``),
+			employeeService: testdata.FuncCall{Called: false},
+			expectedStatus:  http.StatusBadRequest,
+		},
+		"missing employee first name attribute": {
+			reqBody: []byte(`
+				{
+					"last_name": "Easby",
+					"date_of_birth": "1995-02-13"
+				}
+			`),
+			employeeService: testdata.FuncCall{Called: false},
+			expectedStatus:  http.StatusBadRequest,
+		},
+		"error insert employee from employee service": {
+			reqBody: rawMockEmployee,
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, &mockEmployee},
+				Output: []interface{}{errors.New("unexpected error")},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockEmployeeService := new(mocks.EmployeeService)
+			if tc.employeeService.Called {
+				mockEmployeeService.On("Create", tc.employeeService.Input...).Return(tc.employeeService.Output...).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/employees", strings.NewReader(string(tc.reqBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			handler.AddEmployeeHandler(e, mockEmployeeService)
+
+			e.ServeHTTP(rec, req)
+
+			mockEmployeeService.AssertExpectations(t)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
+
+	var mockEmployee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &mockEmployee)
+
+	tests := map[string]struct {
+		employeeService testdata.FuncCall
+		query           string
+		expectedStatus  int
+	}{
+		"success": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mockEmployee.ID, []string{domain.EmployeeExpandDepartment}},
+				Output: []interface{}{mockEmployee, nil},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		"success without department": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mockEmployee.ID, []string{}},
+				Output: []interface{}{mockEmployee, nil},
+			},
+			query:          "?fields=id,first_name&expand=",
+			expectedStatus: http.StatusOK,
+		},
+		"with bad expand param": {
+			employeeService: testdata.FuncCall{Called: false},
+			query:           "?expand=manager",
+			expectedStatus:  http.StatusBadRequest,
+		},
+		"not found": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mockEmployee.ID, []string{domain.EmployeeExpandDepartment}},
+				Output: []interface{}{domain.Employee{}, domain.ErrNotFound},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		"error from employee service": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mockEmployee.ID, []string{domain.EmployeeExpandDepartment}},
+				Output: []interface{}{domain.Employee{}, errors.New("unexpected error")},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockEmployeeService := new(mocks.EmployeeService)
+			if tc.employeeService.Called {
+				mockEmployeeService.On("Get", tc.employeeService.Input...).Return(tc.employeeService.Output...).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/employees/"+mockEmployee.ID+tc.query, nil)
+			rec := httptest.NewRecorder()
+			handler.AddEmployeeHandler(e, mockEmployeeService)
+
+			e.ServeHTTP(rec, req)
+
+			mockEmployeeService.AssertExpectations(t)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	var employee1, employee2 domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee1)
+	testdata.UnmarshallGoldenToJSON(t, "employee-1SYxHnSCbFCxLr7zUxk5j8cB0Cr", &employee2)
+	employees := []domain.Employee{employee2, employee1}
+
+	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
+
+	tests := map[string]struct {
+		employeeService    testdata.FuncCall
+		target             string
+		ifNoneMatch        string
+		accept             string
+		expectedStatusCode int
+		expectedCursor     string
+		expectedETag       string
+		expectedLink       string
+		expectedNoLink     bool
+		expectedTotal      string
+		expectedBody       string
+		expectedCSV        string
+	}{
+		"success with num": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+				}},
+				Output: []interface{}{employees, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/employees",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/fbe5650ea6cc02663bb40a7da8817adc",
+			expectedLink:       `</employees?cursor=next-cursor>; rel="next"`,
+		},
+		"success with filters": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Keyword:     "emilia",
+					Num:         10,
+					Cursor:      "cursor",
+					WithTotal:   true,
+					DeptIDs:     []string{"0ujsswThIGTUYm2K8FjOOfXtY1K", "0ujsszwN8NRY24YaXiTIE2VWDTS"},
+					Titles:      []string{"Senior Developer"},
+					BirthPlaces: []string{"Jakarta"},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+					DateOfBirth: domain.TimeRange{
+						From: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+						To:   time.Date(1999, 12, 31, 23, 59, 59, 999999000, time.UTC),
+					},
+					CreatedTime: domain.TimeRange{
+						From: time.Date(2019, 10, 13, 8, 4, 5, 0, time.UTC),
+					},
+				}},
+				Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "next-cursor", PrevCursor: "prev-cursor", Total: 1}, nil},
+			},
+			target:             "/employees?keyword=emilia&num=10&cursor=cursor&total=true&deptIds=0ujsswThIGTUYm2K8FjOOfXtY1K,0ujsszwN8NRY24YaXiTIE2VWDTS&titles=Senior%20Developer&birthPlaces=Jakarta&dateOfBirthFrom=1990-01-01&dateOfBirthTo=1999-12-31&createdFrom=2019-10-13T08:04:05Z",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/7c0474a7046e32a618f2ee142c998c52",
+			expectedTotal:      "1",
+		},
+		"success with last page": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					Cursor:      "last-cursor",
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+				}},
+				Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{PrevCursor: "prev-cursor"}, nil},
+			},
+			target:             "/employees?cursor=last-cursor",
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       "W/7c0474a7046e32a618f2ee142c998c52",
+			expectedLink:       `</employees?before=prev-cursor>; rel="prev"`,
+		},
+		"success with empty page": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					Cursor:      "last-cursor",
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+				}},
+				Output: []interface{}{[]domain.Employee{}, domain.Pagination{}, nil},
+			},
+			target:             "/employees?cursor=last-cursor",
+			expectedStatusCode: http.StatusOK,
+			expectedNoLink:     true,
+		},
+		"success with etag": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+				}},
+				Output: []interface{}{employees, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/employees",
+			ifNoneMatch:        "W/fbe5650ea6cc02663bb40a7da8817adc",
+			expectedStatusCode: http.StatusNotModified,
+		},
+		"success with fields without department": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{},
+				}},
+				Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/employees?fields=id,first_name,department.id",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/7c0474a7046e32a618f2ee142c998c52",
+			expectedBody:       `[{"department":{"id":"` + employee1.Department.ID + `"},"first_name":"` + employee1.FirstName + `","id":"` + employee1.ID + `"}]`,
+		},
+		"success with fields and expand": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+				}},
+				Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/employees?fields=id&expand=department",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/7c0474a7046e32a618f2ee142c998c52",
+			expectedBody:       `[{"id":"` + employee1.ID + `"}]`,
+		},
+		"with bad fields param": {
+			employeeService:    testdata.FuncCall{Called: false},
+			target:             "/employees?fields=id,password",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"with bad expand param": {
+			employeeService:    testdata.FuncCall{Called: false},
+			target:             "/employees?expand=manager",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"success with csv": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{},
+				}},
+				Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/employees?fields=first_name,id",
+			accept:             "text/csv",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/7c0474a7046e32a618f2ee142c998c52",
+			expectedLink:       `</employees?cursor=next-cursor&fields=first_name%2Cid>; rel="next"`,
+			expectedCSV:        "first_name,id\nEmilia,1S9XpJCvJbt1plvU36tAcJWS2ZW\n",
+		},
+		"success with csv of empty list": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{},
+				}},
+				Output: []interface{}{[]domain.Employee{}, domain.Pagination{}, nil},
+			},
+			target:             "/employees?fields=first_name,id",
+			accept:             "text/csv",
+			expectedStatusCode: http.StatusOK,
+			expectedCSV:        "first_name,id\n",
+		},
+		"with not acceptable media type": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+				}},
+				Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/employees",
+			accept:             "text/html",
+			expectedStatusCode: http.StatusNotAcceptable,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/7c0474a7046e32a618f2ee142c998c52",
+		},
+		"with bad num param": {
+			employeeService:    testdata.FuncCall{Called: false},
+			target:             "/employees?num=xxxx",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"success with filter expression": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+					Expression: filterql.And{
+						Left: filterql.Comparison{
+							Field:    "title",
+							Operator: filterql.Eq,
+							Values:   []interface{}{"Senior Developer"},
+						},
+						Right: filterql.Comparison{
+							Field:    "dept_id",
+							Operator: filterql.In,
+							Values:   []interface{}{"0ujsswThIGTUYm2K8FjOOfXtY1K", "0ujsszwN8NRY24YaXiTIE2VWDTS"},
+						},
+					},
+				}},
+				Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "next-cursor"}, nil},
+			},
+			target:             "/employees?filter=" + url.QueryEscape(`title eq "Senior Developer" and dept_id in ("0ujsswThIGTUYm2K8FjOOfXtY1K","0ujsszwN8NRY24YaXiTIE2VWDTS")`),
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "next-cursor",
+			expectedETag:       "W/7c0474a7046e32a618f2ee142c998c52",
+		},
+		"with bad filter expression": {
+			employeeService:    testdata.FuncCall{Called: false},
+			target:             "/employees?filter=" + url.QueryEscape(`title eq`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"with bad date of birth param": {
+			employeeService:    testdata.FuncCall{Called: false},
+			target:             "/employees?dateOfBirthTo=xxxx",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"with unexpected error": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input: []interface{}{mock.Anything, domain.EmployeeFilter{
+					IDs:         []string{},
+					Num:         20,
+					DeptIDs:     []string{},
+					Titles:      []string{},
+					BirthPlaces: []string{},
+					Expand:      []string{domain.EmployeeExpandDepartment},
+				}},
+				Output: []interface{}{[]domain.Employee{}, domain.Pagination{}, errors.New("unexpected error")},
+			},
+			target:             "/employees",
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockEmployeeService := new(mocks.EmployeeService)
+			if tc.employeeService.Called {
+				mockEmployeeService.On("Fetch", tc.employeeService.Input...).Return(tc.employeeService.Output...).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.AddEmployeeHandler(e, mockEmployeeService)
+			e.ServeHTTP(rec, req)
+
+			mockEmployeeService.AssertExpectations(t)
+
+			require.Equal(t, tc.expectedStatusCode, rec.Code)
+			require.Equal(t, tc.expectedCursor, rec.Header().Get("X-Cursor"))
+			require.Equal(t, tc.expectedETag, rec.Header().Get("ETag"))
+			require.Equal(t, tc.expectedTotal, rec.Header().Get("X-Total-Count"))
+			if tc.expectedLink != "" || tc.expectedNoLink {
+				require.Equal(t, tc.expectedLink, rec.Header().Get("Link"))
+			}
+			if tc.expectedBody != "" {
+				require.JSONEq(t, tc.expectedBody, rec.Body.String())
+			}
+			if tc.expectedCSV != "" {
+				require.Equal(t, tc.expectedCSV, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
+
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee)
+
+	employeeReq := employee
+	employeeReq.CreatedTime = time.Time{}
+	employeeReq.UpdatedTime = time.Time{}
+	employeeReqJSON, err := json.Marshal(employeeReq)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		reqBody         []byte
+		employeeService testdata.FuncCall
+		expectedStatus  int
+	}{
+		"success": {
+			reqBody: employeeReqJSON,
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employeeReq},
+				Output: []interface{}{employee, nil},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		"missing employee first name attribute": {
+			reqBody:         []byte(`{"date_of_birth": "1995-02-13"}`),
+			employeeService: testdata.FuncCall{Called: false},
+			expectedStatus:  http.StatusBadRequest,
+		},
+		"not found": {
+			reqBody: employeeReqJSON,
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employeeReq},
+				Output: []interface{}{domain.Employee{}, domain.ErrNotFound},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		"unexpected error": {
+			reqBody: employeeReqJSON,
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employeeReq},
+				Output: []interface{}{domain.Employee{}, errors.New("unexpected error")},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockEmployeeService := new(mocks.EmployeeService)
+			if tc.employeeService.Called {
+				mockEmployeeService.On("Update", tc.employeeService.Input...).Return(tc.employeeService.Output...).Once()
+			}
+
+			handler.AddEmployeeHandler(e, mockEmployeeService)
+
+			req := httptest.NewRequest(http.MethodPut, "/employees/"+employee.ID, strings.NewReader(string(tc.reqBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			mockEmployeeService.AssertExpectations(t)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
+
+	employeeID := "1S9XpJCvJbt1plvU36tAcJWS2ZW"
+
+	tests := map[string]struct {
+		employeeService testdata.FuncCall
+		expectedStatus  int
+	}{
+		"success": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employeeID},
+				Output: []interface{}{nil},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		"not found": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employeeID},
+				Output: []interface{}{domain.ErrNotFound},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		"unexpected error": {
+			employeeService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employeeID},
+				Output: []interface{}{errors.New("unexpected error")},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockEmployeeService := new(mocks.EmployeeService)
+			if tc.employeeService.Called {
+				mockEmployeeService.On("Delete", tc.employeeService.Input...).Return(tc.employeeService.Output...).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/employees/"+employeeID, nil)
+			rec := httptest.NewRecorder()
+			handler.AddEmployeeHandler(e, mockEmployeeService)
+
+			e.ServeHTTP(rec, req)
+
+			mockEmployeeService.AssertExpectations(t)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
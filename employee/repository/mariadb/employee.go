@@ -5,19 +5,29 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/friendsofgo/errors"
 	"github.com/go-sql-driver/mysql"
 	"github.com/segmentio/ksuid"
-	log "github.com/sirupsen/logrus"
 
 	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	outbox "github.com/milhamhidayat/golang-clean-code-v2/outbox/repository/mariadb"
 	"github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
 	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/tracing"
 )
 
-// Repository implement all employee repository method from interface
+// queryRower queries a row, it is satisfied by *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Repository implement all employee repository method from interface.
+// Every change is written with its domain events to the outbox in the same transaction
 type Repository struct {
 	DB *sql.DB
 }
@@ -31,6 +41,9 @@ func New(db *sql.DB) Repository {
 
 // Create is a repository to insert an employee
 func (r Repository) Create(ctx context.Context, e *domain.Employee) (err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
 	localTime, err := ntime.GetLocalTime()
 	if err != nil {
 		return
@@ -41,9 +54,28 @@ func (r Repository) Create(ctx context.Context, e *domain.Employee) (err error)
 		return
 	}
 
-	employeeID := ksuid.New().String()
+	err = r.insert(ctx, tx, e, localTime)
+	if err != nil {
+		r.rollback(ctx, tx, "failed to insert employee")
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		r.rollback(ctx, tx, "failed to rollback after commit")
+		return
+	}
+
+	return
+}
+
+// insert inserts an employee and writes its created event within the transaction
+func (r Repository) insert(ctx context.Context, tx *sql.Tx, e *domain.Employee, localTime time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.insert")
+	defer func() { tracing.End(span, err) }()
+
 	if e.ID == "" {
-		e.ID = employeeID
+		e.ID = ksuid.New().String()
 	}
 
 	lastname := sql.NullString{}
@@ -58,37 +90,42 @@ func (r Repository) Create(ctx context.Context, e *domain.Employee) (err error)
 	e.UpdatedTime = localTime
 
 	query, args, err := sq.Insert("employees").
-		Columns("id", "first_name", "last_name", "birth_place", "date_of_birth", "title", "dept_id", "created_time", "updated_time").
-		Values(e.ID, e.FirstName, lastname, e.BirthPlace, e.DateOfBirth, e.Title, e.Department.ID, e.CreatedTime, e.UpdatedTime).
+		Columns("id", "tenant_id", "first_name", "last_name", "birth_place", "date_of_birth", "title", "dept_id", "created_time", "updated_time").
+		Values(e.ID, domain.TenantFromContext(ctx), e.FirstName, lastname, e.BirthPlace, e.DateOfBirth, e.Title, e.Department.ID, e.CreatedTime, e.UpdatedTime).
 		ToSql()
 	if err != nil {
-		r.rollback(tx, "failed to generate insert employee query")
+		err = errors.Wrap(err, "failed to generate insert employee query")
 		return
 	}
 
-	stmt, err := tx.PrepareContext(ctx, query)
+	tracing.Statement(span, query)
+	_, err = tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		r.rollback(tx, "failed to prepared insert employee statement")
+		err = errors.Wrap(err, "failed to execute insert employee statement")
 		return
 	}
 
-	defer r.closeStatement(stmt)
-
-	_, err = stmt.ExecContext(ctx, args...)
+	err = r.writeEvents(ctx, tx, e.ID, eventOf(domain.EventEmployeeCreated, e))
 	if err != nil {
-		r.rollback(tx, "failed to execute insert employee statement")
-		return
+		err = errors.Wrap(err, "failed to write employee created event")
 	}
 
-	err = tx.Commit()
-	return nil
+	return
 }
 
 // Get is a repository to get an employee
 func (r Repository) Get(ctx context.Context, employeeID string) (employee domain.Employee, err error) {
+	return r.get(ctx, r.DB, employeeID)
+}
+
+// get gets an employee with the db or a transaction
+func (r Repository) get(ctx context.Context, q queryRower, employeeID string) (employee domain.Employee, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.Get")
+	defer func() { tracing.End(span, err) }()
+
 	query, args, err := sq.Select("id", "first_name", "last_name", "birth_place", "date_of_birth", "title", "dept_id", "created_time", "updated_time").
 		From("employees").
-		Where(sq.Eq{"id": employeeID}).
+		Where(sq.Eq{"id": employeeID, "tenant_id": domain.TenantFromContext(ctx)}).
 		ToSql()
 	if err != nil {
 		return
@@ -99,7 +136,8 @@ func (r Repository) Get(ctx context.Context, employeeID string) (employee domain
 	createdTime := mysql.NullTime{}
 	updatedTime := mysql.NullTime{}
 
-	row := r.DB.QueryRowContext(ctx, query, args...)
+	tracing.Statement(span, query)
+	row := q.QueryRowContext(ctx, query, args...)
 	err = row.Scan(
 		&employee.ID,
 		&employee.FirstName,
@@ -128,36 +166,79 @@ func (r Repository) Get(ctx context.Context, employeeID string) (employee domain
 }
 
 // Fetch is a repository to fetch employees
-func (r Repository) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, nextCursor string, err error) {
+func (r Repository) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, pagination domain.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.Fetch")
+	defer func() { tracing.End(span, err) }()
+
 	employees = make([]domain.Employee, 0)
 	qSelect := sq.Select("id", "first_name", "last_name", "birth_place", "date_of_birth", "title", "dept_id", "created_time", "updated_time").
 		From("employees")
 
+	conditions := sq.And{sq.Eq{"tenant_id": domain.TenantFromContext(ctx)}}
+
 	if len(filter.IDs) != 0 {
-		qSelect = qSelect.Where(sq.Eq{"id": filter.IDs})
+		qSelect = qSelect.Where(conditions).Where(sq.Eq{"id": filter.IDs})
 		qField := strings.Repeat(",?", len(filter.IDs))
 		qOrderBy := fmt.Sprintf("ORDER BY FIELD(id%s)", qField)
-		qSelect = qSelect.Suffix(qOrderBy)
-	} else if len(filter.DeptIDs) != 0 {
-		qSelect = qSelect.Where(sq.Eq{"id": filter.IDs})
-		qField := strings.Repeat(",?", len(filter.IDs))
-		qOrderBy := fmt.Sprintf("ORDER BY FIELD(id%s)", qField)
-		qSelect = qSelect.OrderBy(qOrderBy)
+		ids := make([]interface{}, 0, len(filter.IDs))
+		for _, id := range filter.IDs {
+			ids = append(ids, id)
+		}
+		qSelect = qSelect.Suffix(qOrderBy, ids...)
 	} else {
-		qSelect = qSelect.OrderBy("id desc")
-
 		if filter.Keyword != "" {
-			qSelect = qSelect.Where(`first_name LIKE ?`, fmt.Sprint("%", filter.Keyword, "%"))
+			conditions = append(conditions, sq.Expr(`first_name LIKE ?`, fmt.Sprint("%", filter.Keyword, "%")))
+		}
+
+		if len(filter.DeptIDs) != 0 {
+			conditions = append(conditions, sq.Eq{"dept_id": filter.DeptIDs})
+		}
+
+		if len(filter.Titles) != 0 {
+			conditions = append(conditions, sq.Eq{"title": filter.Titles})
 		}
 
-		if filter.Cursor != "" {
-			var id string
+		if len(filter.BirthPlaces) != 0 {
+			conditions = append(conditions, sq.Eq{"birth_place": filter.BirthPlaces})
+		}
+
+		conditions = append(conditions, timeRangeConditions("date_of_birth", filter.DateOfBirth)...)
+		conditions = append(conditions, timeRangeConditions("created_time", filter.CreatedTime)...)
+		conditions = append(conditions, timeRangeConditions("updated_time", filter.UpdatedTime)...)
+
+		if filter.Expression != nil {
+			pred, er := filterql.ToSqlizer(filter.Expression, nil)
+			if er != nil {
+				err = er
+				return
+			}
+			conditions = append(conditions, pred)
+		}
+
+		qSelect = qSelect.Where(conditions)
+
+		switch {
+		case filter.Before != "":
+			id, er := cursor.DecodeBase64(filter.Before)
+			if er != nil {
+				err = er
+				return
+			}
+			qSelect = qSelect.Where(sq.Gt{"id": id}).OrderBy("id asc")
+		case filter.Cursor != "":
 			id, er := cursor.DecodeBase64(filter.Cursor)
 			if er != nil {
 				err = er
 				return
 			}
-			qSelect = qSelect.Where(sq.Lt{"id": id})
+			qSelect = qSelect.Where(sq.Lt{"id": id}).OrderBy("id desc")
+		default:
+			qSelect = qSelect.OrderBy("id desc")
+		}
+
+		if filter.Num > 0 {
+			// fetch one more item to know whether there is a page beyond this one
+			qSelect = qSelect.Limit(uint64(filter.Num) + 1)
 		}
 	}
 
@@ -166,10 +247,7 @@ func (r Repository) Fetch(ctx context.Context, filter domain.EmployeeFilter) (em
 		return
 	}
 
-	if len(filter.IDs) != 0 || len(filter.DeptIDs) != 0 {
-		args = append(args, args...)
-	}
-
+	tracing.Statement(span, query)
 	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return
@@ -178,7 +256,7 @@ func (r Repository) Fetch(ctx context.Context, filter domain.EmployeeFilter) (em
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			log.Error(err)
+			logger.FromContext(ctx).Errorf("failed to close employee rows: %v", err)
 		}
 	}()
 
@@ -217,13 +295,43 @@ func (r Repository) Fetch(ctx context.Context, filter domain.EmployeeFilter) (em
 	}
 
 	if len(filter.IDs) != 0 {
+		if filter.WithTotal {
+			pagination.Total = len(employees)
+		}
 		return
 	}
 
-	nextCursor = filter.Cursor
+	hasMore := filter.Num > 0 && len(employees) > filter.Num
+	if hasMore {
+		employees = employees[:filter.Num]
+	}
+
+	// a forward page has a previous page when it was reached by a cursor,
+	// a backward page always has the page it was reached from as its next page
+	hasPrev, hasNext := filter.Cursor != "", hasMore
+	if filter.Before != "" {
+		hasPrev, hasNext = hasMore, true
+
+		for i, j := 0, len(employees)-1; i < j; i, j = i+1, j-1 {
+			employees[i], employees[j] = employees[j], employees[i]
+		}
+	}
+
 	if len(employees) >= 1 {
-		id := employees[len(employees)-1].ID
-		nextCursor = cursor.EncodeBase64(id)
+		if hasNext {
+			pagination.NextCursor = cursor.EncodeBase64(employees[len(employees)-1].ID)
+		}
+
+		if hasPrev {
+			pagination.PrevCursor = cursor.EncodeBase64(employees[0].ID)
+		}
+	}
+
+	if filter.WithTotal {
+		pagination.Total, err = r.count(ctx, conditions)
+		if err != nil {
+			return
+		}
 	}
 
 	return
@@ -231,13 +339,47 @@ func (r Repository) Fetch(ctx context.Context, filter domain.EmployeeFilter) (em
 
 // Update is a repository to update an employee
 func (r Repository) Update(ctx context.Context, e domain.Employee) (employee domain.Employee, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.Update")
+	defer func() { tracing.End(span, err) }()
+
 	localTime, err := ntime.GetLocalTime()
 	if err != nil {
 		return
 	}
 
-	lastname := sql.NullString{}
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	// the department before the update tells whether the employee is transferred
+	fromDepartmentID, err := r.lockDepartmentOf(ctx, tx, e.ID)
+	if err != nil {
+		r.rollback(ctx, tx, "failed to lock employee")
+		return
+	}
+
+	employee, err = r.update(ctx, tx, e, fromDepartmentID, localTime)
+	if err != nil {
+		r.rollback(ctx, tx, "failed to update employee")
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		r.rollback(ctx, tx, "failed to rollback after commit")
+		return
+	}
+
+	return
+}
+
+// update updates an employee locked by lockDepartmentOf and writes its updated events within the transaction
+func (r Repository) update(ctx context.Context, tx *sql.Tx, e domain.Employee, fromDepartmentID string, localTime time.Time) (employee domain.Employee, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.update")
+	defer func() { tracing.End(span, err) }()
 
+	lastname := sql.NullString{}
 	if e.LastName != "" {
 		lastname = sql.NullString{
 			Valid:  true,
@@ -245,11 +387,6 @@ func (r Repository) Update(ctx context.Context, e domain.Employee) (employee dom
 		}
 	}
 
-	tx, err := r.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return
-	}
-
 	query, args, err := sq.Update("employees").
 		SetMap(sq.Eq{
 			"first_name":    e.FirstName,
@@ -260,46 +397,82 @@ func (r Repository) Update(ctx context.Context, e domain.Employee) (employee dom
 			"dept_id":       e.Department.ID,
 			"updated_time":  localTime,
 		}).
-		Where(sq.Eq{"id": e.ID}).
+		Where(sq.Eq{"id": e.ID, "tenant_id": domain.TenantFromContext(ctx)}).
 		ToSql()
 	if err != nil {
-		r.rollback(tx, "failed to prepare update employee query")
+		err = errors.Wrap(err, "failed to prepare update employee query")
 		return
 	}
 
-	stmt, err := tx.PrepareContext(ctx, query)
+	tracing.Statement(span, query)
+	_, err = tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		r.rollback(tx, "failed to prepared update employee statement")
+		err = errors.Wrap(err, "failed to execute update employee statement")
 		return
 	}
 
-	defer r.closeStatement(stmt)
-
-	res, err := stmt.ExecContext(ctx, args...)
+	employee, err = r.get(ctx, tx, e.ID)
 	if err != nil {
-		r.rollback(tx, "failed to update employee")
 		return
 	}
 
-	err = tx.Commit()
+	events := []event{eventOf(domain.EventEmployeeUpdated, employee)}
+	if employee.Department.ID != fromDepartmentID {
+		events = append(events, eventOf(domain.EventEmployeeTransferred, domain.EmployeeTransfer{
+			EmployeeID:       employee.ID,
+			FromDepartmentID: fromDepartmentID,
+			ToDepartmentID:   employee.Department.ID,
+		}))
+	}
+
+	err = r.writeEvents(ctx, tx, e.ID, events...)
 	if err != nil {
-		r.rollback(tx, "failed to rollback after commit")
-		return
+		err = errors.Wrap(err, "failed to write employee updated events")
 	}
 
-	count, err := res.RowsAffected()
+	return
+}
+
+// Upsert is a repository to update the employees of an id and insert the others in a single transaction,
+// ErrNotFound is returned when an id doesn't exist in the tenant
+func (r Repository) Upsert(ctx context.Context, employees []domain.Employee) (created, updated int, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.Upsert")
+	defer func() { tracing.End(span, err) }()
+
+	localTime, err := ntime.GetLocalTime()
 	if err != nil {
 		return
 	}
 
-	if count == 0 {
-		err = domain.ErrNotFound
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
 		return
 	}
 
-	employee, err = r.Get(ctx, e.ID)
+	for i := range employees {
+		e := &employees[i]
+
+		if e.ID == "" {
+			err = r.insert(ctx, tx, e, localTime)
+			created++
+		} else {
+			var fromDepartmentID string
+			if fromDepartmentID, err = r.lockDepartmentOf(ctx, tx, e.ID); err == nil {
+				*e, err = r.update(ctx, tx, *e, fromDepartmentID, localTime)
+				updated++
+			}
+		}
+
+		if err != nil {
+			r.rollback(ctx, tx, "failed to upsert employees")
+			return 0, 0, err
+		}
+	}
+
+	err = tx.Commit()
 	if err != nil {
-		return
+		r.rollback(ctx, tx, "failed to commit upserted employees")
+		return 0, 0, err
 	}
 
 	return
@@ -307,60 +480,156 @@ func (r Repository) Update(ctx context.Context, e domain.Employee) (employee dom
 
 // Delete is a repository to delete an employee
 func (r Repository) Delete(ctx context.Context, employeeID string) (err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
 
+	// the deleted event carries the last state of the employee
+	employee, err := r.get(ctx, tx, employeeID)
+	if err != nil {
+		r.rollback(ctx, tx, "failed to get employee to delete")
+		return
+	}
+
 	query, args, err := sq.Delete("employees").
-		Where(sq.Eq{"id": employeeID}).
+		Where(sq.Eq{"id": employeeID, "tenant_id": domain.TenantFromContext(ctx)}).
 		ToSql()
 	if err != nil {
-		r.rollback(tx, "failed to prepare delete employee query")
+		r.rollback(ctx, tx, "failed to prepare delete employee query")
 		return
 	}
 
+	tracing.Statement(span, query)
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
-		r.rollback(tx, "failed to prepare delete employee statement")
+		r.rollback(ctx, tx, "failed to prepare delete employee statement")
+		return
 	}
 
-	defer r.closeStatement(stmt)
+	defer r.closeStatement(ctx, stmt)
 
 	res, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
-		r.rollback(tx, "failed to execute delete employee")
+		r.rollback(ctx, tx, "failed to execute delete employee")
+		return
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		r.rollback(ctx, tx, "failed to count deleted employee")
+		return
+	}
+
+	if count == 0 {
+		r.rollback(ctx, tx, "employee to delete is not found")
+		err = domain.ErrNotFound
+		return
+	}
+
+	err = r.writeEvents(ctx, tx, employeeID, eventOf(domain.EventEmployeeDeleted, employee))
+	if err != nil {
+		r.rollback(ctx, tx, "failed to write employee deleted event")
 		return
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		r.rollback(tx, "failed to commit")
+		r.rollback(ctx, tx, "failed to commit")
 	}
 
-	count, err := res.RowsAffected()
+	return
+}
+
+func timeRangeConditions(column string, timeRange domain.TimeRange) (conditions sq.And) {
+	if !timeRange.From.IsZero() {
+		conditions = append(conditions, sq.GtOrEq{column: timeRange.From})
+	}
+
+	if !timeRange.To.IsZero() {
+		conditions = append(conditions, sq.LtOrEq{column: timeRange.To})
+	}
+
+	return
+}
+
+// lockDepartmentOf locks the employee row within the transaction and returns its department
+func (r Repository) lockDepartmentOf(ctx context.Context, tx *sql.Tx, employeeID string) (departmentID string, err error) {
+	query, args, err := sq.Select("dept_id").
+		From("employees").
+		Where(sq.Eq{"id": employeeID, "tenant_id": domain.TenantFromContext(ctx)}).
+		Suffix("FOR UPDATE").
+		ToSql()
 	if err != nil {
 		return
 	}
 
-	if count == 0 {
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&departmentID)
+	if errors.Is(err, sql.ErrNoRows) {
 		err = domain.ErrNotFound
-		return
 	}
 
 	return
 }
 
-func (r Repository) rollback(tx *sql.Tx, msg string) {
+// event is a domain event of an employee and its data
+type event struct {
+	eventType string
+	data      interface{}
+}
+
+func eventOf(eventType string, data interface{}) event {
+	return event{eventType: eventType, data: data}
+}
+
+// writeEvents writes the events of an employee change to the outbox within the transaction of the change
+func (r Repository) writeEvents(ctx context.Context, tx *sql.Tx, employeeID string, events ...event) (err error) {
+	outboxEvents := make([]domain.Event, 0, len(events))
+	for _, ev := range events {
+		e, er := domain.NewEvent(ctx, ev.eventType, domain.AggregateEmployee, employeeID, ev.data)
+		if er != nil {
+			return er
+		}
+		outboxEvents = append(outboxEvents, e)
+	}
+
+	return outbox.Insert(ctx, tx, outboxEvents...)
+}
+
+func (r Repository) rollback(ctx context.Context, tx *sql.Tx, msg string) {
 	err := tx.Rollback()
 	if err != nil && err != sql.ErrTxDone {
-		log.Error(errors.Wrap(err, msg))
+		logger.FromContext(ctx).Errorf("%v", errors.Wrap(err, msg))
+	}
+}
+
+func (r Repository) count(ctx context.Context, conditions sq.And) (total int, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeRepository.count")
+	defer func() { tracing.End(span, err) }()
+
+	qCount := sq.Select("COUNT(*)").From("employees").Where(conditions)
+
+	query, args, err := qCount.ToSql()
+	if err != nil {
+		return
 	}
+
+	tracing.Statement(span, query)
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		err = errors.Wrap(err, "failed to count employees")
+		return
+	}
+
+	return
 }
 
-func (r Repository) closeStatement(stmt *sql.Stmt) {
+func (r Repository) closeStatement(ctx context.Context, stmt *sql.Stmt) {
 	err := stmt.Close()
 	if err != nil {
-		log.Error(err)
+		logger.FromContext(ctx).Errorf("failed to close employee statement: %v", err)
 	}
 }
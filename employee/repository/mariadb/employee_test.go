@@ -3,6 +3,7 @@ package mariadb_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -11,6 +12,7 @@ import (
 	"github.com/milhamhidayat/golang-clean-code-v2/domain"
 	mariadb "github.com/milhamhidayat/golang-clean-code-v2/driver/mariadb"
 	repo "github.com/milhamhidayat/golang-clean-code-v2/employee/repository/mariadb"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
 	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
 	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
 )
@@ -179,13 +181,13 @@ func (e *employeeSuite) TestFetch() {
 			expectedEmployees[i].UpdatedTime = utcTime
 		}
 
-		emps, cursor, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
 			IDs: []string{expectedEmployees[0].ID, expectedEmployees[1].ID},
 		})
 
 		require.NoError(t, err)
 		require.Equal(t, expectedEmployees, emps)
-		require.Equal(t, "", cursor)
+		require.Equal(t, "", pagination.NextCursor)
 	})
 
 	e.T().Run("success with dept ids", func(t *testing.T) {
@@ -198,15 +200,74 @@ func (e *employeeSuite) TestFetch() {
 
 			expectedEmployees[i].CreatedTime = utcTime
 			expectedEmployees[i].UpdatedTime = utcTime
+		}
 
-			emps, cursor, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
-				IDs: []string{expectedEmployees[0].ID},
-			})
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+			DeptIDs: []string{expectedEmployees[0].Department.ID},
+		})
 
+		require.NoError(t, err)
+		require.Equal(t, expectedEmployees, emps)
+		require.Equal(t, "", pagination.NextCursor)
+	})
+
+	e.T().Run("success with title, birth place and date of birth range", func(t *testing.T) {
+		expectedEmployees := make([]domain.Employee, 1)
+		expectedEmployees[0] = employees[0]
+
+		for i, v := range expectedEmployees {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
 			require.NoError(t, err)
-			require.Equal(t, expectedEmployees, emps)
-			require.Equal(t, "", cursor)
+
+			expectedEmployees[i].CreatedTime = utcTime
+			expectedEmployees[i].UpdatedTime = utcTime
 		}
+
+		emps, _, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+			Titles:      []string{"Senior Developer", "Manager"},
+			BirthPlaces: []string{"Jakarta"},
+			DateOfBirth: domain.TimeRange{
+				From: time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(1995, 12, 31, 0, 0, 0, 0, time.UTC),
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, expectedEmployees, emps)
+	})
+
+	e.T().Run("success with filter expression", func(t *testing.T) {
+		expectedEmployees := make([]domain.Employee, 1)
+		expectedEmployees[0] = employees[1]
+
+		for i, v := range expectedEmployees {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
+			require.NoError(t, err)
+
+			expectedEmployees[i].CreatedTime = utcTime
+			expectedEmployees[i].UpdatedTime = utcTime
+		}
+
+		expression, err := filterql.Parse(`title eq "Manager" and (birth_place in ("Sydney","Jakarta") or date_of_birth lt 1991-01-01)`, domain.EmployeeFilterFields)
+		require.NoError(t, err)
+
+		emps, _, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+			Expression: expression,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, expectedEmployees, emps)
+	})
+
+	e.T().Run("success with created time range", func(t *testing.T) {
+		emps, _, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+			CreatedTime: domain.TimeRange{
+				From: time.Now().Add(time.Hour),
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []domain.Employee{}, emps)
 	})
 
 	e.T().Run("success with num", func(t *testing.T) {
@@ -222,24 +283,67 @@ func (e *employeeSuite) TestFetch() {
 			expectedEmployees[i].UpdatedTime = utcTime
 		}
 
-		emps, cursor, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
 			Num: 2,
 		})
 
 		require.NoError(t, err)
 		require.Equal(t, expectedEmployees, emps)
-		require.Equal(t, "MVM5WHBKQ3ZKYnQxcGx2VTM2dEFjSldTMlpX", cursor)
+		require.Equal(t, domain.Pagination{}, pagination)
+	})
+
+	e.T().Run("success with first page using num", func(t *testing.T) {
+		expectedEmployees := make([]domain.Employee, 1)
+		expectedEmployees[0] = employees[1]
+
+		for i, v := range expectedEmployees {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
+			require.NoError(t, err)
+
+			expectedEmployees[i].CreatedTime = utcTime
+			expectedEmployees[i].UpdatedTime = utcTime
+		}
+
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+			Num: 1,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, expectedEmployees, emps)
+		require.Equal(t, domain.Pagination{NextCursor: "MVNZeEhuU0NiRkN4THI3elV4azVqOGNCMENy"}, pagination)
+	})
+
+	e.T().Run("success with last page using num and cursor", func(t *testing.T) {
+		expectedEmployees := make([]domain.Employee, 1)
+		expectedEmployees[0] = employees[0]
+
+		for i, v := range expectedEmployees {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
+			require.NoError(t, err)
+
+			expectedEmployees[i].CreatedTime = utcTime
+			expectedEmployees[i].UpdatedTime = utcTime
+		}
+
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+			Num:    1,
+			Cursor: "MVNZeEhuU0NiRkN4THI3elV4azVqOGNCMENy",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, expectedEmployees, emps)
+		require.Equal(t, domain.Pagination{PrevCursor: "MVM5WHBKQ3ZKYnQxcGx2VTM2dEFjSldTMlpX"}, pagination)
 	})
 
-	e.T().Run("success with second page using num and cursor", func(t *testing.T) {
-		emps, cursor, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+	e.T().Run("success with empty page using num and cursor", func(t *testing.T) {
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
 			Num:    2,
 			Cursor: "MVM5WHBKQ3ZKYnQxcGx2VTM2dEFjSldTMlpX",
 		})
 
 		require.NoError(t, err)
 		require.Equal(t, []domain.Employee{}, emps)
-		require.Equal(t, "MVM5WHBKQ3ZKYnQxcGx2VTM2dEFjSldTMlpX", cursor)
+		require.Equal(t, domain.Pagination{}, pagination)
 	})
 
 	e.T().Run("success with keyword", func(t *testing.T) {
@@ -254,13 +358,36 @@ func (e *employeeSuite) TestFetch() {
 			expectedEmployees[i].UpdatedTime = utcTime
 		}
 
-		emps, cursor, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
 			Keyword: "casey",
 		})
 
 		require.NoError(t, err)
 		require.Equal(t, expectedEmployees, emps)
-		require.Equal(t, "MVNZeEhuU0NiRkN4THI3elV4azVqOGNCMENy", cursor)
+		require.Equal(t, "", pagination.NextCursor)
+	})
+
+	e.T().Run("success with num, before cursor and total", func(t *testing.T) {
+		expectedEmployees := make([]domain.Employee, 1)
+		expectedEmployees[0] = employees[1]
+
+		for i, v := range expectedEmployees {
+			utcTime, err := ntime.ConvertToUTCTime(v.CreatedTime)
+			require.NoError(t, err)
+
+			expectedEmployees[i].CreatedTime = utcTime
+			expectedEmployees[i].UpdatedTime = utcTime
+		}
+
+		emps, pagination, err := employeeRepo.Fetch(context.Background(), domain.EmployeeFilter{
+			Num:       1,
+			Before:    "MVM5WHBKQ3ZKYnQxcGx2VTM2dEFjSldTMlpX",
+			WithTotal: true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, expectedEmployees, emps)
+		require.Equal(t, domain.Pagination{NextCursor: "MVNZeEhuU0NiRkN4THI3elV4azVqOGNCMENy", Total: 2}, pagination)
 	})
 }
 
@@ -311,3 +438,95 @@ func (e *employeeSuite) TestDelete() {
 		require.EqualError(t, err, domain.ErrNotFound.Error())
 	})
 }
+
+func (e *employeeSuite) TestTenantIsolation() {
+	employeeRepo := repo.New(e.DB)
+	acme := domain.NewContextWithTenant(context.Background(), "acme")
+	globex := domain.NewContextWithTenant(context.Background(), "globex")
+
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(e.T(), "employee-1SYxHnSCbFCxLr7zUxk5j8cB0Cr", &employee)
+
+	err := employeeRepo.Create(acme, &employee)
+	require.NoError(e.T(), err)
+
+	e.T().Run("get of other tenant", func(t *testing.T) {
+		_, err := employeeRepo.Get(globex, employee.ID)
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+
+		_, err = employeeRepo.Get(context.Background(), employee.ID)
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	e.T().Run("fetch of other tenant", func(t *testing.T) {
+		res, pagination, err := employeeRepo.Fetch(globex, domain.EmployeeFilter{Num: 10, WithTotal: true})
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+		require.Equal(t, 0, pagination.Total)
+
+		res, _, err = employeeRepo.Fetch(globex, domain.EmployeeFilter{IDs: []string{employee.ID}})
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+	})
+
+	e.T().Run("update of other tenant", func(t *testing.T) {
+		updated := employee
+		updated.Title = "Director"
+
+		_, err := employeeRepo.Update(globex, updated)
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	e.T().Run("delete of other tenant", func(t *testing.T) {
+		err := employeeRepo.Delete(globex, employee.ID)
+		require.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	e.T().Run("same tenant", func(t *testing.T) {
+		res, err := employeeRepo.Get(acme, employee.ID)
+		require.NoError(t, err)
+		require.Equal(t, employee.Title, res.Title)
+
+		employees, _, err := employeeRepo.Fetch(acme, domain.EmployeeFilter{IDs: []string{employee.ID}})
+		require.NoError(t, err)
+		require.Len(t, employees, 1)
+	})
+}
+
+func (e *employeeSuite) TestUpsert() {
+	employeeRepo := repo.New(e.DB)
+
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(e.T(), "employee-1SYxHnSCbFCxLr7zUxk5j8cB0Cr", &employee)
+
+	err := e.SeedEmployee([]domain.Employee{employee})
+	require.NoError(e.T(), err)
+
+	transferred := employee
+	transferred.Department = domain.Department{ID: "0ujsswThIGTUYm2K8FjOOfXtY1K"}
+	employees := []domain.Employee{
+		transferred,
+		{FirstName: "Maria", DateOfBirth: "1990-01-02", Department: employee.Department},
+	}
+	created, updated, err := employeeRepo.Upsert(context.Background(), employees)
+	require.NoError(e.T(), err)
+	require.Equal(e.T(), 1, created)
+	require.Equal(e.T(), 1, updated)
+
+	res, err := employeeRepo.Get(context.Background(), employee.ID)
+	require.NoError(e.T(), err)
+	require.Equal(e.T(), transferred.Department.ID, res.Department.ID)
+
+	res, err = employeeRepo.Get(context.Background(), employees[1].ID)
+	require.NoError(e.T(), err)
+	require.Equal(e.T(), "Maria", res.FirstName)
+
+	e.T().Run("unknown id isn't inserted", func(t *testing.T) {
+		unknown := domain.Employee{ID: "0ujzPyRiIAffKhBux4PvQdDqMHY", FirstName: "Ghost", DateOfBirth: "1990-01-02", Department: employee.Department}
+		_, _, err := employeeRepo.Upsert(context.Background(), []domain.Employee{unknown})
+		require.Equal(t, domain.ErrNotFound, err)
+
+		_, err = employeeRepo.Get(context.Background(), unknown.ID)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+}
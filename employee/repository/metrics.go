@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/metrics"
+)
+
+// name labels the metrics of the repository
+const name = "employee"
+
+// Metrics is a employee repository measuring the calls of the next repository
+type Metrics struct {
+	next    domain.EmployeeRepository
+	metrics *metrics.Repository
+}
+
+// NewMetrics will return a employee repository measuring the calls of the next repository
+func NewMetrics(next domain.EmployeeRepository, m *metrics.Repository) Metrics {
+	return Metrics{
+		next:    next,
+		metrics: m,
+	}
+}
+
+// Create will measure creating a employee
+func (m Metrics) Create(ctx context.Context, e *domain.Employee) (err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Create", start, err) }(time.Now())
+	return m.next.Create(ctx, e)
+}
+
+// Fetch will measure fetching employees
+func (m Metrics) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, pagination domain.Pagination, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Fetch", start, err) }(time.Now())
+	return m.next.Fetch(ctx, filter)
+}
+
+// Get will measure getting a employee
+func (m Metrics) Get(ctx context.Context, employeeID string) (employee domain.Employee, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Get", start, err) }(time.Now())
+	return m.next.Get(ctx, employeeID)
+}
+
+// Update will measure updating a employee
+func (m Metrics) Update(ctx context.Context, e domain.Employee) (employee domain.Employee, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Update", start, err) }(time.Now())
+	return m.next.Update(ctx, e)
+}
+
+// Delete will measure deleting a employee
+func (m Metrics) Delete(ctx context.Context, employeeID string) (err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Delete", start, err) }(time.Now())
+	return m.next.Delete(ctx, employeeID)
+}
+
+// Upsert will measure upserting employees
+func (m Metrics) Upsert(ctx context.Context, employees []domain.Employee) (created, updated int, err error) {
+	defer func(start time.Time) { m.metrics.Observe(name, "Upsert", start, err) }(time.Now())
+	return m.next.Upsert(ctx, employees)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Authorization is an employee service enforcing role based access on the next service.
+// Every role may read employees, hr-admin may mutate every employee
+// and department-head may only mutate employees of their own department.
+// An API key is authorized by employees:read and employees:write scopes instead
+type Authorization struct {
+	next         domain.EmployeeService
+	employeeRepo domain.EmployeeRepository
+}
+
+// NewAuthorization will create an employee service enforcing role based access.
+// The repository is used to look up the current department of an employee before it is mutated
+func NewAuthorization(next domain.EmployeeService, employeeRepo domain.EmployeeRepository) Authorization {
+	return Authorization{
+		next:         next,
+		employeeRepo: employeeRepo,
+	}
+}
+
+func principal(ctx context.Context) (p domain.Principal, err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		err = domain.ErrUnauthorized
+	}
+
+	return
+}
+
+// writer returns the principal of a mutation, the department of a department-head
+// is looked up from their employee when the token only carries the employee id
+func (a Authorization) writer(ctx context.Context) (p domain.Principal, err error) {
+	if p, err = principal(ctx); err != nil {
+		return
+	}
+
+	if p.DepartmentID != "" || p.EmployeeID == "" || !p.HasRole(domain.RoleDepartmentHead) || canWriteAll(p) {
+		return
+	}
+
+	employee, err := a.employeeRepo.Get(ctx, p.EmployeeID)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = domain.ErrForbidden
+		}
+		return
+	}
+
+	p.DepartmentID = employee.Department.ID
+	return
+}
+
+func authorizeRead(ctx context.Context) (err error) {
+	p, err := principal(ctx)
+	if err != nil {
+		return
+	}
+
+	if !p.HasScope(domain.ScopeEmployeesRead) && !p.HasRole(domain.RoleViewer, domain.RoleHRAdmin, domain.RoleDepartmentHead) {
+		err = domain.ErrForbidden
+	}
+
+	return
+}
+
+// canWriteAll reports whether the principal may mutate employees of every department
+func canWriteAll(p domain.Principal) bool {
+	return p.HasScope(domain.ScopeEmployeesWrite) || p.HasRole(domain.RoleHRAdmin)
+}
+
+// authorizeWrite allows hr-admin, and department-head when the department is their own
+func authorizeWrite(p domain.Principal, deptID string) (err error) {
+	if canWriteAll(p) {
+		return
+	}
+
+	if !p.HasRole(domain.RoleDepartmentHead) || p.DepartmentID == "" || p.DepartmentID != deptID {
+		err = domain.ErrForbidden
+	}
+
+	return
+}
+
+// Create will create an employee when the caller is allowed to mutate the employee department
+func (a Authorization) Create(ctx context.Context, e *domain.Employee) (err error) {
+	p, err := a.writer(ctx)
+	if err != nil {
+		return
+	}
+
+	if err = authorizeWrite(p, e.Department.ID); err != nil {
+		return
+	}
+
+	return a.next.Create(ctx, e)
+}
+
+// Fetch will fetch employees when the caller has any role
+func (a Authorization) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, pagination domain.Pagination, err error) {
+	if err = authorizeRead(ctx); err != nil {
+		return
+	}
+
+	return a.next.Fetch(ctx, filter)
+}
+
+// Get will get an employee when the caller has any role
+func (a Authorization) Get(ctx context.Context, employeeID string, expand []string) (employee domain.Employee, err error) {
+	if err = authorizeRead(ctx); err != nil {
+		return
+	}
+
+	return a.next.Get(ctx, employeeID, expand)
+}
+
+// Update will update an employee when the caller is allowed to mutate both its current and new department
+func (a Authorization) Update(ctx context.Context, e domain.Employee) (employee domain.Employee, err error) {
+	p, err := a.writer(ctx)
+	if err != nil {
+		return
+	}
+
+	if err = authorizeWrite(p, e.Department.ID); err != nil {
+		return
+	}
+
+	if !canWriteAll(p) {
+		var current domain.Employee
+		if current, err = a.employeeRepo.Get(ctx, e.ID); err != nil {
+			return
+		}
+
+		if err = authorizeWrite(p, current.Department.ID); err != nil {
+			return
+		}
+	}
+
+	return a.next.Update(ctx, e)
+}
+
+// Delete will delete an employee when the caller is allowed to mutate its department
+func (a Authorization) Delete(ctx context.Context, employeeID string) (err error) {
+	p, err := a.writer(ctx)
+	if err != nil {
+		return
+	}
+
+	if !canWriteAll(p) && !p.HasRole(domain.RoleDepartmentHead) {
+		return domain.ErrForbidden
+	}
+
+	if !canWriteAll(p) {
+		var current domain.Employee
+		if current, err = a.employeeRepo.Get(ctx, employeeID); err != nil {
+			return
+		}
+
+		if err = authorizeWrite(p, current.Department.ID); err != nil {
+			return
+		}
+	}
+
+	return a.next.Delete(ctx, employeeID)
+}
@@ -0,0 +1,265 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/employee/service"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+var (
+	hrAdmin = domain.Principal{Subject: "hr", Roles: []domain.Role{domain.RoleHRAdmin}}
+	viewer  = domain.Principal{Subject: "viewer", Roles: []domain.Role{domain.RoleViewer}}
+)
+
+func departmentHead(deptID string) domain.Principal {
+	return domain.Principal{Subject: "head", Roles: []domain.Role{domain.RoleDepartmentHead}, DepartmentID: deptID}
+}
+
+func TestAuthorizationFetch(t *testing.T) {
+	tests := map[string]struct {
+		ctx         context.Context
+		called      bool
+		expectedErr error
+	}{
+		"viewer":              {ctx: domain.NewContextWithPrincipal(context.Background(), viewer), called: true},
+		"department head":     {ctx: domain.NewContextWithPrincipal(context.Background(), departmentHead("1")), called: true},
+		"without role":        {ctx: domain.NewContextWithPrincipal(context.Background(), domain.Principal{Subject: "x"}), expectedErr: domain.ErrForbidden},
+		"api key read scope":  {ctx: domain.NewContextWithPrincipal(context.Background(), domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeEmployeesRead}}), called: true},
+		"api key write scope": {ctx: domain.NewContextWithPrincipal(context.Background(), domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeEmployeesWrite}}), expectedErr: domain.ErrForbidden},
+		"not authenticated":   {ctx: context.Background(), expectedErr: domain.ErrUnauthorized},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockEmployeeService := new(mocks.EmployeeService)
+			if tc.called {
+				mockEmployeeService.On("Fetch", tc.ctx, domain.EmployeeFilter{}).Return([]domain.Employee{}, domain.Pagination{}, nil).Once()
+			}
+
+			authz := service.NewAuthorization(mockEmployeeService, new(mocks.EmployeeRepository))
+			_, _, err := authz.Fetch(tc.ctx, domain.EmployeeFilter{})
+
+			mockEmployeeService.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestAuthorizationUpdate(t *testing.T) {
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee)
+
+	moved := employee
+	moved.Department.ID = "2"
+
+	tests := map[string]struct {
+		principal    domain.Principal
+		input        domain.Employee
+		employeeRepo testdata.FuncCall
+		called       bool
+		expectedErr  error
+	}{
+		"hr admin": {
+			principal:    hrAdmin,
+			input:        moved,
+			employeeRepo: testdata.FuncCall{Called: false},
+			called:       true,
+		},
+		"api key with write scope": {
+			principal:    domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeEmployeesWrite}},
+			input:        moved,
+			employeeRepo: testdata.FuncCall{Called: false},
+			called:       true,
+		},
+		"department head of the employee": {
+			principal: departmentHead(employee.Department.ID),
+			input:     employee,
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{employee, nil},
+			},
+			called: true,
+		},
+		"department head moving employee to other department": {
+			principal:    departmentHead(employee.Department.ID),
+			input:        moved,
+			employeeRepo: testdata.FuncCall{Called: false},
+			expectedErr:  domain.ErrForbidden,
+		},
+		"department head of other department": {
+			principal: departmentHead("2"),
+			input:     moved,
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{employee, nil},
+			},
+			expectedErr: domain.ErrForbidden,
+		},
+		"viewer": {
+			principal:    viewer,
+			input:        employee,
+			employeeRepo: testdata.FuncCall{Called: false},
+			expectedErr:  domain.ErrForbidden,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			ctx := domain.NewContextWithPrincipal(context.Background(), tc.principal)
+
+			mockEmployeeService := new(mocks.EmployeeService)
+			mockEmployeeRepo := new(mocks.EmployeeRepository)
+			if tc.employeeRepo.Called {
+				mockEmployeeRepo.On("Get", tc.employeeRepo.Input...).Return(tc.employeeRepo.Output...).Once()
+			}
+			if tc.called {
+				mockEmployeeService.On("Update", ctx, tc.input).Return(tc.input, nil).Once()
+			}
+
+			authz := service.NewAuthorization(mockEmployeeService, mockEmployeeRepo)
+			res, err := authz.Update(ctx, tc.input)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockEmployeeService.AssertExpectations(t)
+
+			if tc.expectedErr != nil {
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.input, res)
+		})
+	}
+}
+
+func TestAuthorizationDelete(t *testing.T) {
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee)
+
+	tests := map[string]struct {
+		principal    domain.Principal
+		employeeRepo testdata.FuncCall
+		called       bool
+		expectedErr  error
+	}{
+		"hr admin": {
+			principal:    hrAdmin,
+			employeeRepo: testdata.FuncCall{Called: false},
+			called:       true,
+		},
+		"department head of the employee": {
+			principal: departmentHead(employee.Department.ID),
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{employee, nil},
+			},
+			called: true,
+		},
+		"department head of other department": {
+			principal: departmentHead("2"),
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{employee, nil},
+			},
+			expectedErr: domain.ErrForbidden,
+		},
+		"department head with not found employee": {
+			principal: departmentHead(employee.Department.ID),
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{domain.Employee{}, domain.ErrNotFound},
+			},
+			expectedErr: domain.ErrNotFound,
+		},
+		"viewer": {
+			principal:    viewer,
+			employeeRepo: testdata.FuncCall{Called: false},
+			expectedErr:  domain.ErrForbidden,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			ctx := domain.NewContextWithPrincipal(context.Background(), tc.principal)
+
+			mockEmployeeService := new(mocks.EmployeeService)
+			mockEmployeeRepo := new(mocks.EmployeeRepository)
+			if tc.employeeRepo.Called {
+				mockEmployeeRepo.On("Get", tc.employeeRepo.Input...).Return(tc.employeeRepo.Output...).Once()
+			}
+			if tc.called {
+				mockEmployeeService.On("Delete", ctx, employee.ID).Return(nil).Once()
+			}
+
+			authz := service.NewAuthorization(mockEmployeeService, mockEmployeeRepo)
+			err := authz.Delete(ctx, employee.ID)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockEmployeeService.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestAuthorizationDepartmentHeadByEmployee(t *testing.T) {
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee)
+
+	head := domain.Employee{ID: "head-1", Department: domain.Department{ID: employee.Department.ID}}
+
+	tests := map[string]struct {
+		employeeRepo testdata.FuncCall
+		called       bool
+		expectedErr  error
+	}{
+		"department of the employee": {
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "head-1"},
+				Output: []interface{}{head, nil},
+			},
+			called: true,
+		},
+		"unknown employee": {
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "head-1"},
+				Output: []interface{}{domain.Employee{}, domain.ErrNotFound},
+			},
+			expectedErr: domain.ErrForbidden,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			p := domain.Principal{Subject: "sso|1", Roles: []domain.Role{domain.RoleDepartmentHead}, EmployeeID: "head-1"}
+			ctx := domain.NewContextWithPrincipal(context.Background(), p)
+
+			mockEmployeeService := new(mocks.EmployeeService)
+			mockEmployeeRepo := new(mocks.EmployeeRepository)
+			mockEmployeeRepo.On("Get", tc.employeeRepo.Input...).Return(tc.employeeRepo.Output...).Once()
+			if tc.called {
+				mockEmployeeService.On("Create", ctx, &employee).Return(nil).Once()
+			}
+
+			err := service.NewAuthorization(mockEmployeeService, mockEmployeeRepo).Create(ctx, &employee)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockEmployeeService.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
@@ -10,15 +10,18 @@ import (
 
 // Service is an employee service
 type Service struct {
-	departmentRepo domain.DepartmentRepository
-	employeeRepo   domain.EmployeeRepository
+	departmentRepo    domain.DepartmentRepository
+	employeeRepo      domain.EmployeeRepository
+	departmentService domain.DepartmentService
 }
 
-// New will crate a new employee service
-func New(departmentRepo domain.DepartmentRepository, employeeRepo domain.EmployeeRepository) Service {
+// New will crate a new employee service.
+// An expanded department is read through the department service, so the caller must be allowed to read it
+func New(departmentRepo domain.DepartmentRepository, employeeRepo domain.EmployeeRepository, departmentService domain.DepartmentService) Service {
 	return Service{
-		departmentRepo: departmentRepo,
-		employeeRepo:   employeeRepo,
+		departmentRepo:    departmentRepo,
+		employeeRepo:      employeeRepo,
+		departmentService: departmentService,
 	}
 }
 
@@ -33,13 +36,13 @@ func (s Service) Create(ctx context.Context, e *domain.Employee) (err error) {
 }
 
 // Fetch will return employess based on filter
-func (s Service) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, nextCursor string, err error) {
-	employees, nextCursor, err = s.employeeRepo.Fetch(ctx, filter)
+func (s Service) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, pagination domain.Pagination, err error) {
+	employees, pagination, err = s.employeeRepo.Fetch(ctx, filter)
 	if err != nil {
 		return
 	}
 
-	if len(employees) == 0 {
+	if len(employees) == 0 || !isExpanded(filter.Expand, domain.EmployeeExpandDepartment) {
 		return
 	}
 
@@ -52,37 +55,36 @@ func (s Service) Fetch(ctx context.Context, filter domain.EmployeeFilter) (emplo
 }
 
 func (s Service) fetchDepartment(ctx context.Context, e []domain.Employee) (err error) {
+	deptIDs := make([]string, 0)
 	empDept := map[string]domain.Department{}
 	for _, v := range e {
-		empDept[v.ID] = domain.Department{}
+		if _, ok := empDept[v.Department.ID]; ok {
+			continue
+		}
+		empDept[v.Department.ID] = domain.Department{}
+		deptIDs = append(deptIDs, v.Department.ID)
 	}
 
-	g, ctx := errgroup.WithContext(context.Background())
-	c := make(chan domain.Department)
-	for k := range empDept {
-		k := k
+	depts := make([]domain.Department, len(deptIDs))
+	g, ctx := errgroup.WithContext(ctx)
+	for i, id := range deptIDs {
+		i, id := i, id
 		g.Go(func() error {
-			dept, err := s.departmentRepo.Get(ctx, k)
+			dept, err := s.departmentService.Get(ctx, id)
 			if err != nil {
 				return err
 			}
-			c <- dept
+			depts[i] = dept
 			return nil
 		})
 	}
 
-	go func() {
-		g.Wait()
-		close(c)
-	}()
-
-	if err := g.Wait(); err != nil {
-		return err
+	if err = g.Wait(); err != nil {
+		return
 	}
-	for v := range c {
-		if v == (domain.Department{}) {
-			empDept[v.ID] = v
-		}
+
+	for i, id := range deptIDs {
+		empDept[id] = depts[i]
 	}
 
 	for i, v := range e {
@@ -94,14 +96,28 @@ func (s Service) fetchDepartment(ctx context.Context, e []domain.Employee) (err
 	return
 }
 
-// Get will return an employee
-func (s Service) Get(ctx context.Context, employeeID string) (employee domain.Employee, err error) {
+func isExpanded(expand []string, relation string) bool {
+	for _, v := range expand {
+		if v == relation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get will return an employee, department is only resolved when it is expanded
+func (s Service) Get(ctx context.Context, employeeID string, expand []string) (employee domain.Employee, err error) {
 	employee, err = s.employeeRepo.Get(ctx, employeeID)
 	if err != nil {
 		return
 	}
 
-	department, err := s.departmentRepo.Get(ctx, employee.Department.ID)
+	if !isExpanded(expand, domain.EmployeeExpandDepartment) {
+		return
+	}
+
+	department, err := s.departmentService.Get(ctx, employee.Department.ID)
 	if err != nil {
 		return
 	}
@@ -111,7 +127,8 @@ func (s Service) Get(ctx context.Context, employeeID string) (employee domain.Em
 	return
 }
 
-// Update will update an employee
+// Update will update an employee, the department of the response is read from the repository
+// as the update is already done when it is read
 func (s Service) Update(ctx context.Context, e domain.Employee) (employee domain.Employee, err error) {
 	ch1 := make(chan func() (domain.Employee, error))
 	ch2 := make(chan func() (domain.Department, error))
@@ -56,7 +56,7 @@ func TestCreate(t *testing.T) {
 				}
 			}
 
-			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo)
+			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo, new(mocks.DepartmentService))
 			err := employeeService.Create(context.Background(), &employee)
 
 			mockEmployeeRepo.AssertExpectations(t)
@@ -81,56 +81,62 @@ func TestFetch(t *testing.T) {
 		Name: "Marketing",
 	}
 
-	mockDepartmentRepo := new(mocks.DepartmentRepository)
+	wantEmployee1, wantEmployee2 := employee1, employee2
+	wantEmployee1.Department = mockDepartment
+	wantEmployee2.Department = mockDepartment
+
+	expand := []string{domain.EmployeeExpandDepartment}
+
+	mockDepartmentService := new(mocks.DepartmentService)
 	mockEmployeeRepo := new(mocks.EmployeeRepository)
 
 	tests := map[string]struct {
-		filter         domain.EmployeeFilter
-		employeeRepo   map[string]testdata.FuncCall
-		departmentRepo map[string]testdata.FuncCall
-		expectedRes    []domain.Employee
-		expectedCursor string
-		expectedErr    error
+		filter             domain.EmployeeFilter
+		employeeRepo       map[string]testdata.FuncCall
+		departmentService  map[string]testdata.FuncCall
+		expectedRes        []domain.Employee
+		expectedPagination domain.Pagination
+		expectedErr        error
 	}{
 		"success with num": {
-			filter: domain.EmployeeFilter{Num: 1},
+			filter: domain.EmployeeFilter{Num: 1, Expand: expand},
 			employeeRepo: map[string]testdata.FuncCall{
 				"Fetch": testdata.FuncCall{
 					Called: true,
-					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1}},
-					Output: []interface{}{[]domain.Employee{employee1}, "cursor-1", nil},
+					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1, Expand: expand}},
+					Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "cursor-1"}, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{mock.Anything, mock.AnythingOfType("string")},
 					Output: []interface{}{mockDepartment, nil},
 				},
 			},
-			expectedRes:    []domain.Employee{employee1},
-			expectedCursor: "cursor-1",
-			expectedErr:    nil,
+			expectedRes:        []domain.Employee{wantEmployee1},
+			expectedPagination: domain.Pagination{NextCursor: "cursor-1"},
+			expectedErr:        nil,
 		},
 		"success with num and cursor": {
-			filter: domain.EmployeeFilter{Num: 1, Cursor: "cursor-1"},
+			filter: domain.EmployeeFilter{Num: 1, Cursor: "cursor-1", Expand: expand},
 			employeeRepo: map[string]testdata.FuncCall{
 				"Fetch": testdata.FuncCall{
 					Called: true,
-					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1, Cursor: "cursor-1"}},
-					Output: []interface{}{[]domain.Employee{employee2}, "cursor-2", nil},
+					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1, Cursor: "cursor-1", Expand: expand}},
+					Output: []interface{}{[]domain.Employee{employee2}, domain.Pagination{NextCursor: "cursor-2"}, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{mock.Anything, mock.AnythingOfType("string")},
 					Output: []interface{}{mockDepartment, nil},
 				},
 			},
-			expectedRes:    []domain.Employee{employee2},
-			expectedCursor: "cursor-2",
-			expectedErr:    nil,
+			expectedRes:        []domain.Employee{wantEmployee2},
+			expectedPagination: domain.Pagination{NextCursor: "cursor-2"},
+			expectedErr:        nil,
 		},
 		"success with num and cursor end of page": {
 			filter: domain.EmployeeFilter{Num: 1, Cursor: "cursor-2"},
@@ -138,111 +144,127 @@ func TestFetch(t *testing.T) {
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1, Cursor: "cursor-2"}},
-					Output: []interface{}{[]domain.Employee{}, "cursor-2", nil},
+					Output: []interface{}{[]domain.Employee{}, domain.Pagination{NextCursor: "cursor-2"}, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{Called: false},
 			},
-			expectedRes:    []domain.Employee{},
-			expectedCursor: "cursor-2",
-			expectedErr:    nil,
+			expectedRes:        []domain.Employee{},
+			expectedPagination: domain.Pagination{NextCursor: "cursor-2"},
+			expectedErr:        nil,
 		},
 		"success with ids": {
-			filter: domain.EmployeeFilter{IDs: []string{employee2.ID}},
+			filter: domain.EmployeeFilter{IDs: []string{employee2.ID}, Expand: expand},
 			employeeRepo: map[string]testdata.FuncCall{
 				"Fetch": testdata.FuncCall{
 					Called: true,
-					Input:  []interface{}{context.Background(), domain.EmployeeFilter{IDs: []string{employee2.ID}}},
-					Output: []interface{}{[]domain.Employee{employee2}, "", nil},
+					Input:  []interface{}{context.Background(), domain.EmployeeFilter{IDs: []string{employee2.ID}, Expand: expand}},
+					Output: []interface{}{[]domain.Employee{employee2}, domain.Pagination{}, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{mock.Anything, mock.AnythingOfType("string")},
 					Output: []interface{}{mockDepartment, nil},
 				},
 			},
-			expectedRes:    []domain.Employee{employee2},
-			expectedCursor: "",
-			expectedErr:    nil,
+			expectedRes:        []domain.Employee{wantEmployee2},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        nil,
 		},
 		"success with keyword": {
-			filter: domain.EmployeeFilter{Keyword: "emilia"},
+			filter: domain.EmployeeFilter{Keyword: "emilia", Expand: expand},
 			employeeRepo: map[string]testdata.FuncCall{
 				"Fetch": testdata.FuncCall{
 					Called: true,
-					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Keyword: "emilia"}},
-					Output: []interface{}{[]domain.Employee{employee1}, "", nil},
+					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Keyword: "emilia", Expand: expand}},
+					Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{}, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{mock.Anything, mock.AnythingOfType("string")},
 					Output: []interface{}{mockDepartment, nil},
 				},
 			},
-			expectedRes:    []domain.Employee{employee1},
-			expectedCursor: "",
-			expectedErr:    nil,
+			expectedRes:        []domain.Employee{wantEmployee1},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        nil,
 		},
 		"success with dept ids": {
-			filter: domain.EmployeeFilter{DeptIDs: []string{"1"}},
+			filter: domain.EmployeeFilter{DeptIDs: []string{"1"}, Expand: expand},
 			employeeRepo: map[string]testdata.FuncCall{
 				"Fetch": testdata.FuncCall{
 					Called: true,
-					Input:  []interface{}{context.Background(), domain.EmployeeFilter{DeptIDs: []string{"1"}}},
-					Output: []interface{}{[]domain.Employee{employee1}, "", nil},
+					Input:  []interface{}{context.Background(), domain.EmployeeFilter{DeptIDs: []string{"1"}, Expand: expand}},
+					Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{}, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{mock.Anything, mock.AnythingOfType("string")},
 					Output: []interface{}{mockDepartment, nil},
 				},
 			},
-			expectedRes:    []domain.Employee{employee1},
-			expectedCursor: "",
-			expectedErr:    nil,
+			expectedRes:        []domain.Employee{wantEmployee1},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        nil,
 		},
-		"error fetch employee repo": {
+		"success without expand department": {
 			filter: domain.EmployeeFilter{Num: 1},
 			employeeRepo: map[string]testdata.FuncCall{
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1}},
-					Output: []interface{}{[]domain.Employee{}, "", errors.New("unknown error")},
+					Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "cursor-1"}, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{Called: false},
 			},
-			expectedRes:    []domain.Employee{},
-			expectedCursor: "",
-			expectedErr:    errors.New("unknown error"),
+			expectedRes:        []domain.Employee{employee1},
+			expectedPagination: domain.Pagination{NextCursor: "cursor-1"},
+			expectedErr:        nil,
 		},
-		"error get department": {
+		"error fetch employee repo": {
 			filter: domain.EmployeeFilter{Num: 1},
 			employeeRepo: map[string]testdata.FuncCall{
 				"Fetch": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1}},
-					Output: []interface{}{[]domain.Employee{employee1}, "cursor-1", nil},
+					Output: []interface{}{[]domain.Employee{}, domain.Pagination{}, errors.New("unknown error")},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
+				"Get": testdata.FuncCall{Called: false},
+			},
+			expectedRes:        []domain.Employee{},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        errors.New("unknown error"),
+		},
+		"error get department": {
+			filter: domain.EmployeeFilter{Num: 1, Expand: expand},
+			employeeRepo: map[string]testdata.FuncCall{
+				"Fetch": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{context.Background(), domain.EmployeeFilter{Num: 1, Expand: expand}},
+					Output: []interface{}{[]domain.Employee{employee1}, domain.Pagination{NextCursor: "cursor-1"}, nil},
+				},
+			},
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{mock.Anything, mock.AnythingOfType("string")},
 					Output: []interface{}{domain.Department{}, errors.New("unknown error")},
 				},
 			},
-			expectedRes:    []domain.Employee{},
-			expectedCursor: "",
-			expectedErr:    errors.New("unknown error"),
+			expectedRes:        []domain.Employee{},
+			expectedPagination: domain.Pagination{},
+			expectedErr:        errors.New("unknown error"),
 		},
 	}
 
@@ -254,17 +276,17 @@ func TestFetch(t *testing.T) {
 				}
 			}
 
-			for name, fn := range tc.departmentRepo {
+			for name, fn := range tc.departmentService {
 				if fn.Called {
-					mockDepartmentRepo.On(name, fn.Input...).Return(fn.Output...).Once()
+					mockDepartmentService.On(name, fn.Input...).Return(fn.Output...).Once()
 				}
 			}
 
-			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo)
-			res, nextCursor, err := employeeService.Fetch(context.Background(), tc.filter)
+			employeeService := service.New(new(mocks.DepartmentRepository), mockEmployeeRepo, mockDepartmentService)
+			res, pagination, err := employeeService.Fetch(context.Background(), tc.filter)
 
 			mockEmployeeRepo.AssertExpectations(t)
-			mockDepartmentRepo.AssertExpectations(t)
+			mockDepartmentService.AssertExpectations(t)
 
 			if tc.expectedErr != nil {
 				require.EqualError(t, err, tc.expectedErr.Error())
@@ -272,7 +294,7 @@ func TestFetch(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			require.Equal(t, tc.expectedCursor, nextCursor)
+			require.Equal(t, tc.expectedPagination, pagination)
 			require.Equal(t, tc.expectedRes, res)
 		})
 	}
@@ -286,16 +308,20 @@ func TestGet(t *testing.T) {
 	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee)
 	testdata.UnmarshallGoldenToJSON(t, "department-0ujsswThIGTUYm2K8FjOOfXtY1K", &department)
 
-	mockDepartmentRepo := new(mocks.DepartmentRepository)
+	mockDepartmentService := new(mocks.DepartmentService)
 	mockEmployeeRepo := new(mocks.EmployeeRepository)
 
+	expand := []string{domain.EmployeeExpandDepartment}
+
 	tests := map[string]struct {
-		employeeRepo   map[string]testdata.FuncCall
-		departmentRepo map[string]testdata.FuncCall
-		expectedRes    domain.Employee
-		expectedErr    error
+		expand            []string
+		employeeRepo      map[string]testdata.FuncCall
+		departmentService map[string]testdata.FuncCall
+		expectedRes       domain.Employee
+		expectedErr       error
 	}{
 		"success": {
+			expand: expand,
 			employeeRepo: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
@@ -303,7 +329,7 @@ func TestGet(t *testing.T) {
 					Output: []interface{}{employee, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), employee.Department.ID},
@@ -313,7 +339,23 @@ func TestGet(t *testing.T) {
 			expectedRes: employee,
 			expectedErr: nil,
 		},
+		"success without expand department": {
+			expand: nil,
+			employeeRepo: map[string]testdata.FuncCall{
+				"Get": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{context.Background(), employee.ID},
+					Output: []interface{}{employee, nil},
+				},
+			},
+			departmentService: map[string]testdata.FuncCall{
+				"Get": testdata.FuncCall{Called: false},
+			},
+			expectedRes: employee,
+			expectedErr: nil,
+		},
 		"with error get employee": {
+			expand: expand,
 			employeeRepo: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
@@ -321,13 +363,14 @@ func TestGet(t *testing.T) {
 					Output: []interface{}{domain.Employee{}, errors.New("unknown error")},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{Called: false},
 			},
 			expectedRes: domain.Employee{},
 			expectedErr: errors.New("unknown error"),
 		},
 		"with error get department": {
+			expand: expand,
 			employeeRepo: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
@@ -335,7 +378,7 @@ func TestGet(t *testing.T) {
 					Output: []interface{}{employee, nil},
 				},
 			},
-			departmentRepo: map[string]testdata.FuncCall{
+			departmentService: map[string]testdata.FuncCall{
 				"Get": testdata.FuncCall{
 					Called: true,
 					Input:  []interface{}{context.Background(), employee.Department.ID},
@@ -345,6 +388,25 @@ func TestGet(t *testing.T) {
 			expectedRes: domain.Employee{},
 			expectedErr: errors.New("unexpected error"),
 		},
+		"with forbidden department": {
+			expand: expand,
+			employeeRepo: map[string]testdata.FuncCall{
+				"Get": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{context.Background(), employee.ID},
+					Output: []interface{}{employee, nil},
+				},
+			},
+			departmentService: map[string]testdata.FuncCall{
+				"Get": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{context.Background(), employee.Department.ID},
+					Output: []interface{}{domain.Department{}, domain.ErrForbidden},
+				},
+			},
+			expectedRes: domain.Employee{},
+			expectedErr: domain.ErrForbidden,
+		},
 	}
 
 	for tn, tc := range tests {
@@ -355,16 +417,16 @@ func TestGet(t *testing.T) {
 				}
 			}
 
-			for name, fn := range tc.departmentRepo {
+			for name, fn := range tc.departmentService {
 				if fn.Called {
-					mockDepartmentRepo.On(name, fn.Input...).Return(fn.Output...).Once()
+					mockDepartmentService.On(name, fn.Input...).Return(fn.Output...).Once()
 				}
 			}
 
-			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo)
-			res, err := employeeService.Get(context.Background(), employee.ID)
+			employeeService := service.New(new(mocks.DepartmentRepository), mockEmployeeRepo, mockDepartmentService)
+			res, err := employeeService.Get(context.Background(), employee.ID, tc.expand)
 
-			mockDepartmentRepo.AssertExpectations(t)
+			mockDepartmentService.AssertExpectations(t)
 			mockEmployeeRepo.AssertExpectations(t)
 
 			if tc.expectedErr != nil {
@@ -469,7 +531,7 @@ func TestUpdate(t *testing.T) {
 				}
 			}
 
-			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo)
+			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo, new(mocks.DepartmentService))
 			res, err := employeeService.Update(context.Background(), newEmployee)
 
 			mockEmployeeRepo.AssertExpectations(t)
@@ -537,7 +599,7 @@ func TestDelete(t *testing.T) {
 				}
 			}
 
-			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo)
+			employeeService := service.New(mockDepartmentRepo, mockEmployeeRepo, new(mocks.DepartmentService))
 			err := employeeService.Delete(context.Background(), employee.ID)
 
 			mockEmployeeRepo.AssertExpectations(t)
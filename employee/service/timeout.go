@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/deadline"
+)
+
+// Timeout is an employee service bounding every call of the next service which isn't bounded by its caller,
+// e.g. a call of a command
+type Timeout struct {
+	next     domain.EmployeeService
+	timeouts deadline.Timeouts
+}
+
+// NewTimeout will return an employee service calling the next service with the timeout of each method
+func NewTimeout(next domain.EmployeeService, timeouts deadline.Timeouts) Timeout {
+	return Timeout{
+		next:     next,
+		timeouts: timeouts,
+	}
+}
+
+// Create will create an employee with the timeout
+func (t Timeout) Create(ctx context.Context, e *domain.Employee) (err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Create")
+	defer cancel()
+
+	return t.next.Create(ctx, e)
+}
+
+// Fetch will fetch employees with the timeout
+func (t Timeout) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, pagination domain.Pagination, err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Fetch")
+	defer cancel()
+
+	return t.next.Fetch(ctx, filter)
+}
+
+// Get will get an employee with the timeout
+func (t Timeout) Get(ctx context.Context, employeeID string, expand []string) (employee domain.Employee, err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Get")
+	defer cancel()
+
+	return t.next.Get(ctx, employeeID, expand)
+}
+
+// Update will update an employee with the timeout
+func (t Timeout) Update(ctx context.Context, e domain.Employee) (employee domain.Employee, err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Update")
+	defer cancel()
+
+	return t.next.Update(ctx, e)
+}
+
+// Delete will delete an employee with the timeout
+func (t Timeout) Delete(ctx context.Context, employeeID string) (err error) {
+	ctx, cancel := t.timeouts.Bound(ctx, "Delete")
+	defer cancel()
+
+	return t.next.Delete(ctx, employeeID)
+}
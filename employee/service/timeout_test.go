@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/employee/service"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/deadline"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+func TestTimeout(t *testing.T) {
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1SYxHnSCbFCxLr7zUxk5j8cB0Cr", &employee)
+	timeouts := deadline.Timeouts{Default: time.Second, Methods: map[string]time.Duration{"Fetch": time.Minute}}
+
+	t.Run("unbounded context", func(t *testing.T) {
+		next := new(mocks.EmployeeService)
+		next.On("Get", mock.Anything, employee.ID, []string{domain.EmployeeExpandDepartment}).
+			Run(func(args mock.Arguments) {
+				dl, ok := args.Get(0).(context.Context).Deadline()
+				require.True(t, ok)
+				require.WithinDuration(t, time.Now().Add(time.Second), dl, 100*time.Millisecond)
+			}).
+			Return(employee, nil).Once()
+
+		res, err := service.NewTimeout(next, timeouts).Get(context.Background(), employee.ID, []string{domain.EmployeeExpandDepartment})
+		require.NoError(t, err)
+		require.Equal(t, employee, res)
+		next.AssertExpectations(t)
+	})
+
+	t.Run("bounded context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		expected, _ := ctx.Deadline()
+
+		next := new(mocks.EmployeeService)
+		next.On("Delete", mock.Anything, employee.ID).
+			Run(func(args mock.Arguments) {
+				// the deadline of the caller is kept
+				dl, ok := args.Get(0).(context.Context).Deadline()
+				require.True(t, ok)
+				require.Equal(t, expected, dl)
+			}).
+			Return(nil).Once()
+
+		require.NoError(t, service.NewTimeout(next, timeouts).Delete(ctx, employee.ID))
+		next.AssertExpectations(t)
+	})
+
+	t.Run("timeout of the method", func(t *testing.T) {
+		filter := domain.EmployeeFilter{Num: 10}
+		next := new(mocks.EmployeeService)
+		next.On("Fetch", mock.Anything, filter).
+			Run(func(args mock.Arguments) {
+				dl, ok := args.Get(0).(context.Context).Deadline()
+				require.True(t, ok)
+				require.WithinDuration(t, time.Now().Add(time.Minute), dl, 100*time.Millisecond)
+			}).
+			Return([]domain.Employee{employee}, domain.Pagination{}, nil).Once()
+
+		res, _, err := service.NewTimeout(next, timeouts).Fetch(context.Background(), filter)
+		require.NoError(t, err)
+		require.Equal(t, []domain.Employee{employee}, res)
+		next.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/tracing"
+)
+
+// Tracing is a employee service starting a span of every call of the next service
+type Tracing struct {
+	next domain.EmployeeService
+}
+
+// NewTracing will return a employee service tracing the next service
+func NewTracing(next domain.EmployeeService) Tracing {
+	return Tracing{
+		next: next,
+	}
+}
+
+// Create will trace creating a employee
+func (t Tracing) Create(ctx context.Context, e *domain.Employee) (err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Create(ctx, e)
+}
+
+// Fetch will trace fetching employees
+func (t Tracing) Fetch(ctx context.Context, filter domain.EmployeeFilter) (employees []domain.Employee, pagination domain.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.Fetch")
+	defer func() { tracing.End(span, err) }()
+
+	employees, pagination, err = t.next.Fetch(ctx, filter)
+	span.SetAttributes(attribute.Int("employee.count", len(employees)))
+
+	return
+}
+
+// Get will trace getting a employee
+func (t Tracing) Get(ctx context.Context, employeeID string, expand []string) (employee domain.Employee, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.Get", attribute.String("employee.id", employeeID))
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Get(ctx, employeeID, expand)
+}
+
+// Update will trace updating a employee
+func (t Tracing) Update(ctx context.Context, e domain.Employee) (employee domain.Employee, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.Update", attribute.String("employee.id", e.ID))
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Update(ctx, e)
+}
+
+// Delete will trace deleting a employee
+func (t Tracing) Delete(ctx context.Context, employeeID string) (err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.Delete", attribute.String("employee.id", employeeID))
+	defer func() { tracing.End(span, err) }()
+
+	return t.next.Delete(ctx, employeeID)
+}
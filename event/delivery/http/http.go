@@ -0,0 +1,168 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+const (
+	// batchSize is the number of events read from the log at a time
+	batchSize = 100
+	// retryMS is the reconnection delay advised to the clients
+	retryMS = 3000
+)
+
+var errStreamClosed = errors.New("event stream closed")
+
+// Config is the configuration of the event stream
+type Config struct {
+	// PollInterval is the interval of reading the new events
+	PollInterval time.Duration
+	// Heartbeat is the interval of the comments keeping an idle stream open
+	Heartbeat time.Duration
+	// Done ends every open stream when it's closed, e.g. on shutdown
+	Done <-chan struct{}
+}
+
+type eventHandler struct {
+	service domain.EventService
+	cfg     Config
+}
+
+// AddEventHandler adds the server-sent events stream of the department and employee changes
+func AddEventHandler(e *echo.Echo, service domain.EventService, cfg Config) {
+	if service == nil {
+		panic("http: nil event service")
+	}
+
+	handler := &eventHandler{service, cfg}
+
+	e.GET("/events", handler.Stream)
+}
+
+// Stream streams the events written after Last-Event-ID, or the events written from now on without it.
+// The errors are responded before the stream starts, afterwards the stream is ended and the client
+// resumes from the last event it received
+func (h eventHandler) Stream(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filter := domain.EventFilter{
+		DepartmentID: c.QueryParam("department_id"),
+		AfterID:      c.Request().Header.Get("Last-Event-ID"),
+		Num:          batchSize,
+	}
+	if filter.AfterID == "" {
+		filter.AfterID = c.QueryParam("last_event_id")
+	}
+	if types := c.QueryParam("types"); types != "" {
+		filter.AggregateTypes = strings.Split(types, ",")
+	}
+
+	if filter.AfterID == "" {
+		latest, err := h.service.Latest(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to get the latest event")
+		}
+		filter.AfterID = latest
+	}
+
+	events, cursor, err := h.service.Fetch(ctx, filter)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch events")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if _, err = fmt.Fprintf(res, "retry: %d\n\n", retryMS); err != nil {
+		return nil
+	}
+
+	poll := time.NewTicker(h.cfg.PollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(h.cfg.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		for _, e := range events {
+			if err = writeEvent(res, e); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+
+		// a batch which moved the cursor is followed at once, the log may hold more events
+		if cursor == filter.AfterID {
+			err = h.wait(c, poll, heartbeat)
+		} else {
+			err = h.closed(c)
+		}
+		if err != nil {
+			return nil
+		}
+
+		filter.AfterID = cursor
+		events, cursor, err = h.service.Fetch(ctx, filter)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("failed to fetch events of a stream: %v", err)
+			return nil
+		}
+	}
+}
+
+// wait blocks until the next poll and writes the heartbeats meanwhile,
+// an error is returned when the stream is over
+func (h eventHandler) wait(c echo.Context, poll, heartbeat *time.Ticker) (err error) {
+	ctx := c.Request().Context()
+	res := c.Response()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-h.cfg.Done:
+			return errStreamClosed
+		case <-poll.C:
+			return
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return
+			}
+			res.Flush()
+		}
+	}
+}
+
+// closed returns an error when the stream is over
+func (h eventHandler) closed(c echo.Context) (err error) {
+	select {
+	case <-c.Request().Context().Done():
+		return c.Request().Context().Err()
+	case <-h.cfg.Done:
+		return errStreamClosed
+	default:
+		return
+	}
+}
+
+func writeEvent(res *echo.Response, e domain.Event) (err error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return
+}
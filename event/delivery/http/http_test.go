@@ -0,0 +1,165 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	handler "github.com/milhamhidayat/golang-clean-code-v2/event/delivery/http"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+func TestStream(t *testing.T) {
+	event := domain.Event{ID: "2", Type: domain.EventEmployeeUpdated, AggregateType: domain.AggregateEmployee, AggregateID: "e1", Data: []byte(`{"id":"e1"}`)}
+
+	tests := map[string]struct {
+		url            string
+		lastEventID    string
+		latest         *testdata.FuncCall
+		filter         domain.EventFilter
+		firstFetch     []interface{}
+		expectedStatus int
+		expectedBody   []string
+	}{
+		"resume after the last event": {
+			url:         "/events?types=department,employee&department_id=d1",
+			lastEventID: "1",
+			filter: domain.EventFilter{
+				AggregateTypes: []string{domain.AggregateDepartment, domain.AggregateEmployee},
+				DepartmentID:   "d1",
+				AfterID:        "1",
+				Num:            100,
+			},
+			firstFetch:     []interface{}{[]domain.Event{event}, "2", nil},
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				"retry: 3000\n\n",
+				"id: 2\nevent: employee.updated\ndata: {\"id\":\"2\",\"type\":\"employee.updated\",",
+				"\"data\":{\"id\":\"e1\"}}\n\n",
+			},
+		},
+		"resume after the last event of the query": {
+			url:            "/events?last_event_id=2",
+			filter:         domain.EventFilter{AfterID: "2", Num: 100},
+			firstFetch:     []interface{}{[]domain.Event{}, "2", nil},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"retry: 3000\n\n"},
+		},
+		"from now on": {
+			url: "/events",
+			latest: &testdata.FuncCall{
+				Called: true,
+				Output: []interface{}{"2", nil},
+			},
+			filter:         domain.EventFilter{AfterID: "2", Num: 100},
+			firstFetch:     []interface{}{[]domain.Event{}, "2", nil},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"retry: 3000\n\n"},
+		},
+		"unknown last event": {
+			url:            "/events",
+			lastEventID:    "9",
+			filter:         domain.EventFilter{AfterID: "9", Num: 100},
+			firstFetch:     []interface{}{nil, "", domain.ConstraintErrorf("unknown last event id 9")},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"forbidden": {
+			url: "/events",
+			latest: &testdata.FuncCall{
+				Called: true,
+				Output: []interface{}{"", domain.ErrForbidden},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := testdata.GetEchoServer()
+			e.Use(middleware.ErrorMiddleware())
+
+			done := make(chan struct{})
+			mockEventService := new(mocks.EventService)
+			if tc.latest != nil {
+				mockEventService.On("Latest", mock.Anything).Return(tc.latest.Output...).Once()
+			}
+			if tc.firstFetch != nil {
+				mockEventService.On("Fetch", mock.Anything, tc.filter).Return(tc.firstFetch...).Once()
+			}
+			if tc.expectedStatus == http.StatusOK {
+				next := tc.filter
+				next.AfterID = tc.firstFetch[1].(string)
+				if next.AfterID == tc.filter.AfterID {
+					close(done)
+				} else {
+					mockEventService.On("Fetch", mock.Anything, next).Run(func(mock.Arguments) {
+						close(done)
+					}).Return([]domain.Event{}, next.AfterID, nil).Once()
+				}
+			}
+
+			handler.AddEventHandler(e, mockEventService, handler.Config{
+				PollInterval: time.Millisecond,
+				Heartbeat:    time.Hour,
+				Done:         done,
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			mockEventService.AssertExpectations(t)
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+			require.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+			for _, s := range tc.expectedBody {
+				require.Contains(t, rec.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestStreamHeartbeat(t *testing.T) {
+	e := testdata.GetEchoServer()
+
+	done := make(chan struct{})
+	mockEventService := new(mocks.EventService)
+	mockEventService.On("Fetch", mock.Anything, domain.EventFilter{AfterID: "1", Num: 100}).
+		Return([]domain.Event{}, "1", nil).Once()
+
+	handler.AddEventHandler(e, mockEventService, handler.Config{
+		PollInterval: time.Hour,
+		Heartbeat:    time.Millisecond,
+		Done:         done,
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(done)
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	mockEventService.AssertExpectations(t)
+	require.True(t, strings.Contains(rec.Body.String(), ": ping\n\n"))
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Authorization is an event service only streaming the aggregates the caller may read.
+// Every role may read departments and employees, an API key needs the read scope of the aggregate
+type Authorization struct {
+	next domain.EventService
+}
+
+// NewAuthorization will create an event service only streaming the aggregates the caller may read
+func NewAuthorization(next domain.EventService) Authorization {
+	return Authorization{
+		next: next,
+	}
+}
+
+var readScopes = map[string]string{
+	domain.AggregateDepartment: domain.ScopeDepartmentsRead,
+	domain.AggregateEmployee:   domain.ScopeEmployeesRead,
+}
+
+// readable returns the aggregate types of the filter the caller may read, every readable one when none is asked
+func readable(ctx context.Context, aggregateTypes []string) (allowed []string, err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	canRead := func(aggregateType string) bool {
+		return p.HasScope(readScopes[aggregateType]) ||
+			p.HasRole(domain.RoleViewer, domain.RoleHRAdmin, domain.RoleDepartmentHead)
+	}
+
+	if len(aggregateTypes) == 0 {
+		for _, aggregateType := range []string{domain.AggregateDepartment, domain.AggregateEmployee} {
+			if canRead(aggregateType) {
+				allowed = append(allowed, aggregateType)
+			}
+		}
+		if len(allowed) == 0 {
+			err = domain.ErrForbidden
+		}
+		return
+	}
+
+	for _, aggregateType := range aggregateTypes {
+		if _, known := readScopes[aggregateType]; known && !canRead(aggregateType) {
+			return nil, domain.ErrForbidden
+		}
+	}
+
+	return aggregateTypes, nil
+}
+
+// Fetch will fetch the events of the aggregates the caller may read
+func (a Authorization) Fetch(ctx context.Context, filter domain.EventFilter) (events []domain.Event, cursor string, err error) {
+	if filter.AggregateTypes, err = readable(ctx, filter.AggregateTypes); err != nil {
+		return
+	}
+
+	return a.next.Fetch(ctx, filter)
+}
+
+// Latest will get the id of the latest event when the caller may read any aggregate
+func (a Authorization) Latest(ctx context.Context) (id string, err error) {
+	if _, err = readable(ctx, nil); err != nil {
+		return
+	}
+
+	return a.next.Latest(ctx)
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/event/service"
+)
+
+func TestAuthorization(t *testing.T) {
+	every := []string{domain.AggregateDepartment, domain.AggregateEmployee}
+
+	tests := map[string]struct {
+		principal     *domain.Principal
+		types         []string
+		expectedTypes []string
+		expectedErr   error
+	}{
+		"viewer every aggregate": {
+			principal:     &domain.Principal{Subject: "viewer", Roles: []domain.Role{domain.RoleViewer}},
+			expectedTypes: every,
+		},
+		"api key with departments scope every aggregate": {
+			principal:     &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeDepartmentsRead}},
+			expectedTypes: []string{domain.AggregateDepartment},
+		},
+		"api key with employees scope employee events": {
+			principal:     &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeEmployeesRead}},
+			types:         []string{domain.AggregateEmployee},
+			expectedTypes: []string{domain.AggregateEmployee},
+		},
+		"api key with employees scope department events": {
+			principal:   &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeEmployeesRead}},
+			types:       []string{domain.AggregateDepartment},
+			expectedErr: domain.ErrForbidden,
+		},
+		"api key with write scope only": {
+			principal:   &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeEmployeesWrite}},
+			expectedErr: domain.ErrForbidden,
+		},
+		"admin only": {
+			principal:   &domain.Principal{Subject: "admin", Roles: []domain.Role{domain.RoleAdmin}},
+			expectedErr: domain.ErrForbidden,
+		},
+		"not authenticated": {
+			expectedErr: domain.ErrUnauthorized,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.principal != nil {
+				ctx = domain.NewContextWithPrincipal(ctx, *tc.principal)
+			}
+
+			mockEventService := new(mocks.EventService)
+			if tc.expectedErr == nil {
+				mockEventService.On("Fetch", ctx, domain.EventFilter{AggregateTypes: tc.expectedTypes, Num: 10}).
+					Return([]domain.Event{}, "", nil).Once()
+			}
+
+			_, _, err := service.NewAuthorization(mockEventService).Fetch(ctx, domain.EventFilter{AggregateTypes: tc.types, Num: 10})
+
+			mockEventService.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Service is an event service reading the event log of the tenant
+type Service struct {
+	repo domain.EventRepository
+}
+
+// New will create a new event service
+func New(repo domain.EventRepository) Service {
+	return Service{
+		repo: repo,
+	}
+}
+
+// Fetch will return the events of the filter written after AfterID.
+// The cursor is the id of the last event scanned, it moves past the events of other departments too
+func (s Service) Fetch(ctx context.Context, filter domain.EventFilter) (events []domain.Event, cursor string, err error) {
+	for _, aggregateType := range filter.AggregateTypes {
+		if aggregateType != domain.AggregateDepartment && aggregateType != domain.AggregateEmployee {
+			err = domain.ConstraintErrorf("types must be any of %s, %s", domain.AggregateDepartment, domain.AggregateEmployee)
+			return
+		}
+	}
+
+	res, err := s.repo.Fetch(ctx, filter)
+	if errors.Is(err, domain.ErrNotFound) {
+		err = domain.ConstraintErrorf("unknown last event id %s", filter.AfterID)
+		return
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to fetch events")
+		return
+	}
+
+	cursor = filter.AfterID
+	events = make([]domain.Event, 0, len(res))
+	for _, e := range res {
+		cursor = e.ID
+		if filter.DepartmentID == "" || contains(e.DepartmentIDs(), filter.DepartmentID) {
+			events = append(events, e)
+		}
+	}
+
+	return
+}
+
+// Latest will return the id of the latest event
+func (s Service) Latest(ctx context.Context) (id string, err error) {
+	id, err = s.repo.Latest(ctx)
+	if err != nil {
+		err = errors.Wrap(err, "failed to get the latest event")
+	}
+
+	return
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/event/service"
+)
+
+func TestFetch(t *testing.T) {
+	transfer, err := json.Marshal(domain.EmployeeTransfer{EmployeeID: "e1", FromDepartmentID: "d1", ToDepartmentID: "d2"})
+	require.NoError(t, err)
+	employee, err := json.Marshal(domain.Employee{ID: "e2", Department: domain.Department{ID: "d3"}})
+	require.NoError(t, err)
+
+	log := []domain.Event{
+		{ID: "1", Type: domain.EventDepartmentUpdated, AggregateType: domain.AggregateDepartment, AggregateID: "d2"},
+		{ID: "2", Type: domain.EventEmployeeTransferred, AggregateType: domain.AggregateEmployee, AggregateID: "e1", Data: transfer},
+		{ID: "3", Type: domain.EventEmployeeUpdated, AggregateType: domain.AggregateEmployee, AggregateID: "e2", Data: employee},
+	}
+
+	tests := map[string]struct {
+		filter         domain.EventFilter
+		repoEvents     []domain.Event
+		repoErr        error
+		called         bool
+		expectedIDs    []string
+		expectedCursor string
+		expectedErr    error
+	}{
+		"every event": {
+			filter:         domain.EventFilter{AfterID: "0"},
+			repoEvents:     log,
+			called:         true,
+			expectedIDs:    []string{"1", "2", "3"},
+			expectedCursor: "3",
+		},
+		"events of a department": {
+			filter:         domain.EventFilter{AfterID: "0", DepartmentID: "d2"},
+			repoEvents:     log,
+			called:         true,
+			expectedIDs:    []string{"1", "2"},
+			expectedCursor: "3",
+		},
+		"events of the previous department of a transfer": {
+			filter:         domain.EventFilter{AfterID: "0", DepartmentID: "d1"},
+			repoEvents:     log,
+			called:         true,
+			expectedIDs:    []string{"2"},
+			expectedCursor: "3",
+		},
+		"no new event": {
+			filter:         domain.EventFilter{AfterID: "3"},
+			called:         true,
+			expectedIDs:    []string{},
+			expectedCursor: "3",
+		},
+		"unknown last event": {
+			filter:      domain.EventFilter{AfterID: "9"},
+			repoErr:     domain.ErrNotFound,
+			called:      true,
+			expectedErr: domain.ConstraintErrorf("unknown last event id 9"),
+		},
+		"unknown type": {
+			filter:      domain.EventFilter{AggregateTypes: []string{"user"}},
+			expectedErr: domain.ConstraintErrorf("types must be any of department, employee"),
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockEventRepo := new(mocks.EventRepository)
+			if tc.called {
+				mockEventRepo.On("Fetch", context.Background(), tc.filter).Return(tc.repoEvents, tc.repoErr).Once()
+			}
+
+			events, cursor, err := service.New(mockEventRepo).Fetch(context.Background(), tc.filter)
+
+			mockEventRepo.AssertExpectations(t)
+			if tc.expectedErr != nil {
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCursor, cursor)
+			ids := []string{}
+			for _, e := range events {
+				ids = append(ids, e.ID)
+			}
+			require.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
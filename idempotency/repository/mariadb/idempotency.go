@@ -0,0 +1,147 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// errDuplicateEntry is the mysql error number of a duplicate primary key
+const errDuplicateEntry = 1062
+
+// Repository implement all idempotency repository method from interface
+type Repository struct {
+	DB *sql.DB
+}
+
+// New return new idempotency repository
+func New(db *sql.DB) Repository {
+	return Repository{
+		DB: db,
+	}
+}
+
+// Reserve is a repository to insert a pending idempotency record
+func (r Repository) Reserve(ctx context.Context, rec *domain.IdempotencyRecord) (err error) {
+	if rec.CreatedTime.IsZero() {
+		rec.CreatedTime = time.Now()
+	}
+
+	query, args, err := sq.Insert("idempotency_keys").
+		Columns("scope", "idempotency_key", "request_hash", "created_time", "expires_time").
+		Values(rec.Scope, rec.Key, rec.RequestHash, rec.CreatedTime, rec.ExpiresTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	_, err = r.DB.ExecContext(ctx, query, args...)
+	if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+		return domain.ErrIdempotencyKeyInUse
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed insert an idempotency record")
+	}
+
+	return
+}
+
+// Get is a repository to get an idempotency record
+func (r Repository) Get(ctx context.Context, scope, key string) (rec domain.IdempotencyRecord, err error) {
+	query, args, err := sq.Select("scope", "idempotency_key", "request_hash", "status_code", "response_header",
+		"response_body", "created_time", "expires_time").
+		From("idempotency_keys").
+		Where(sq.Eq{"scope": scope, "idempotency_key": key}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	var (
+		statusCode sql.NullInt64
+		header     sql.NullString
+	)
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(&rec.Scope, &rec.Key, &rec.RequestHash, &statusCode, &header,
+		&rec.Body, &rec.CreatedTime, &rec.ExpiresTime)
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	rec.StatusCode = int(statusCode.Int64)
+	if header.Valid {
+		if err = json.Unmarshal([]byte(header.String), &rec.Header); err != nil {
+			err = errors.Wrap(err, "failed decode the response header of an idempotency record")
+		}
+	}
+
+	return
+}
+
+// Complete is a repository to store the response of a pending idempotency record
+func (r Repository) Complete(ctx context.Context, rec domain.IdempotencyRecord) (err error) {
+	header, err := json.Marshal(rec.Header)
+	if err != nil {
+		return
+	}
+
+	query, args, err := sq.Update("idempotency_keys").
+		Set("status_code", rec.StatusCode).
+		Set("response_header", string(header)).
+		Set("response_body", rec.Body).
+		Where(sq.Eq{"scope": rec.Scope, "idempotency_key": rec.Key}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed update an idempotency record")
+	}
+
+	return
+}
+
+// Delete is a repository to delete an idempotency record
+func (r Repository) Delete(ctx context.Context, scope, key string) (err error) {
+	query, args, err := sq.Delete("idempotency_keys").
+		Where(sq.Eq{"scope": scope, "idempotency_key": key}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed delete an idempotency record")
+	}
+
+	return
+}
+
+// DeleteExpired is a repository to delete the idempotency records expired before the time
+func (r Repository) DeleteExpired(ctx context.Context, before time.Time) (count int64, err error) {
+	query, args, err := sq.Delete("idempotency_keys").
+		Where(sq.Lt{"expires_time": before}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed delete expired idempotency records")
+		return
+	}
+
+	return res.RowsAffected()
+}
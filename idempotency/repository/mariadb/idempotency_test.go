@@ -0,0 +1,84 @@
+package mariadb_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	mariadb "github.com/milhamhidayat/golang-clean-code-v2/driver/mariadb"
+	repo "github.com/milhamhidayat/golang-clean-code-v2/idempotency/repository/mariadb"
+)
+
+type idempotencySuite struct {
+	mariadb.DBSuite
+}
+
+func TestIdempotencySuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped for short testing")
+	}
+	suite.Run(t, new(idempotencySuite))
+}
+
+func (i *idempotencySuite) SetupTest() {
+	_, err := i.DB.Exec("TRUNCATE idempotency_keys")
+	require.NoError(i.T(), err)
+}
+
+func (i *idempotencySuite) TestReserveAndComplete() {
+	idempotencyRepo := repo.New(i.DB)
+
+	rec := domain.IdempotencyRecord{
+		Scope:       "default|sub:1|POST /departments",
+		Key:         "key-1",
+		RequestHash: "hash",
+		ExpiresTime: time.Now().Add(time.Hour),
+	}
+	require.NoError(i.T(), idempotencyRepo.Reserve(context.Background(), &rec))
+
+	i.T().Run("reserved key is in use", func(t *testing.T) {
+		err := idempotencyRepo.Reserve(context.Background(), &rec)
+		require.Equal(t, domain.ErrIdempotencyKeyInUse, err)
+
+		res, err := idempotencyRepo.Get(context.Background(), rec.Scope, rec.Key)
+		require.NoError(t, err)
+		require.False(t, res.Completed())
+	})
+
+	i.T().Run("completed", func(t *testing.T) {
+		rec.StatusCode = http.StatusCreated
+		rec.Header = http.Header{"Location": []string{"/departments/1"}}
+		rec.Body = []byte(`{"id":1}`)
+		require.NoError(t, idempotencyRepo.Complete(context.Background(), rec))
+
+		res, err := idempotencyRepo.Get(context.Background(), rec.Scope, rec.Key)
+		require.NoError(t, err)
+		require.True(t, res.Completed())
+		require.Equal(t, rec.Header, res.Header)
+		require.Equal(t, rec.Body, res.Body)
+	})
+}
+
+func (i *idempotencySuite) TestDeleteExpired() {
+	idempotencyRepo := repo.New(i.DB)
+
+	expired := domain.IdempotencyRecord{Scope: "s", Key: "expired", RequestHash: "hash", ExpiresTime: time.Now().Add(-time.Minute)}
+	active := domain.IdempotencyRecord{Scope: "s", Key: "active", RequestHash: "hash", ExpiresTime: time.Now().Add(time.Hour)}
+	require.NoError(i.T(), idempotencyRepo.Reserve(context.Background(), &expired))
+	require.NoError(i.T(), idempotencyRepo.Reserve(context.Background(), &active))
+
+	n, err := idempotencyRepo.DeleteExpired(context.Background(), time.Now())
+	require.NoError(i.T(), err)
+	require.Equal(i.T(), int64(1), n)
+
+	_, err = idempotencyRepo.Get(context.Background(), "s", "expired")
+	require.Equal(i.T(), domain.ErrNotFound, err)
+
+	_, err = idempotencyRepo.Get(context.Background(), "s", "active")
+	require.NoError(i.T(), err)
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+type importHandler struct {
+	service domain.ImportService
+	maxSize int64
+}
+
+// AddImportHandler adds the import handler, a CSV larger than maxSize bytes is rejected
+func AddImportHandler(e *echo.Echo, service domain.ImportService, maxSize int64) {
+	if service == nil {
+		panic("http: nil import service")
+	}
+
+	handler := &importHandler{service, maxSize}
+
+	e.POST("/imports", handler.Create)
+	e.GET("/imports/:id", handler.Get)
+}
+
+// Create schedules the import of the CSV sent as the body or as the file field of a multipart form
+func (h importHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	imp := domain.Import{Kind: c.QueryParam("kind")}
+	if dryRun := c.QueryParam("dry_run"); dryRun != "" {
+		var err error
+		if imp.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return domain.ConstraintErrorf("dry_run query-param is not valid. Got error when parsing value: %v", err)
+		}
+	}
+
+	data, err := h.readCSV(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.Create(ctx, &imp, data); err != nil {
+		return errors.Wrap(err, "failed to create an import")
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, "/imports/"+imp.ID)
+	return c.JSON(http.StatusAccepted, imp)
+}
+
+func (h importHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	imp, err := h.service.Get(ctx, c.Param("id"))
+	if err != nil {
+		return errors.Wrap(err, "failed get an import")
+	}
+
+	return c.JSON(http.StatusOK, imp)
+}
+
+func (h importHandler) readCSV(c echo.Context) (data []byte, err error) {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, h.maxSize)
+
+	var body io.Reader = req.Body
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		file, er := c.FormFile("file")
+		if er != nil {
+			return nil, h.bodyError(er, "file field of the form is required")
+		}
+
+		f, er := file.Open()
+		if er != nil {
+			return nil, er
+		}
+		defer f.Close()
+		body = f
+	}
+
+	data, err = ioutil.ReadAll(body)
+	if err != nil {
+		return nil, h.bodyError(err, "csv can't be read")
+	}
+
+	if len(data) == 0 {
+		return nil, domain.ConstraintErrorf("csv is empty")
+	}
+
+	return
+}
+
+// bodyError tells a body over the limit apart from a malformed one
+func (h importHandler) bodyError(err error, message string) error {
+	if strings.Contains(err.Error(), "request body too large") {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("csv must be at most %d bytes", h.maxSize))
+	}
+
+	return domain.ConstraintErrorf("%s: %v", message, err)
+}
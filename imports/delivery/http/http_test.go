@@ -0,0 +1,164 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	handler "github.com/milhamhidayat/golang-clean-code-v2/imports/delivery/http"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+)
+
+const csv = "name,description\nFinance,Counts things\n"
+
+func multipartBody(t *testing.T, field, content string) (body *bytes.Buffer, contentType string) {
+	body = new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile(field, "departments.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return body, w.FormDataContentType()
+}
+
+func TestCreate(t *testing.T) {
+	form, formContentType := multipartBody(t, "file", csv)
+	noFile, noFileContentType := multipartBody(t, "upload", csv)
+
+	tests := map[string]struct {
+		url            string
+		body           string
+		contentType    string
+		importService  testdata.FuncCall
+		expectedStatus int
+	}{
+		"csv body": {
+			url:         "/imports?kind=departments&dry_run=true",
+			body:        csv,
+			contentType: "text/csv",
+			importService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, &domain.Import{Kind: domain.ImportDepartments, DryRun: true}, []byte(csv)},
+				Output: []interface{}{nil},
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"multipart form": {
+			url:         "/imports?kind=departments",
+			body:        form.String(),
+			contentType: formContentType,
+			importService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, &domain.Import{Kind: domain.ImportDepartments}, []byte(csv)},
+				Output: []interface{}{nil},
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"multipart form without file": {
+			url:            "/imports?kind=departments",
+			body:           noFile.String(),
+			contentType:    noFileContentType,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"empty csv": {
+			url:            "/imports?kind=departments",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"too large": {
+			url:            "/imports?kind=departments",
+			body:           strings.Repeat("a", 1025),
+			contentType:    "text/csv",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		"invalid dry run": {
+			url:            "/imports?kind=departments&dry_run=maybe",
+			body:           csv,
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown kind": {
+			url:         "/imports?kind=users",
+			body:        csv,
+			contentType: "text/csv",
+			importService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mock.Anything, mock.Anything},
+				Output: []interface{}{domain.ConstraintErrorf("kind must be one of departments, employees")},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := testdata.GetEchoServer()
+			e.Use(middleware.ErrorMiddleware())
+
+			mockImportService := new(mocks.ImportService)
+			if tc.importService.Called {
+				mockImportService.On("Create", tc.importService.Input...).Run(func(args mock.Arguments) {
+					imp := args.Get(1).(*domain.Import)
+					imp.ID = "i1"
+					imp.Status = domain.ImportPending
+				}).Return(tc.importService.Output...).Once()
+			}
+			handler.AddImportHandler(e, mockImportService, 1024)
+
+			req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			mockImportService.AssertExpectations(t)
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusAccepted {
+				require.Equal(t, "/imports/i1", rec.Header().Get(echo.HeaderLocation))
+
+				var res domain.Import
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				require.Equal(t, domain.ImportPending, res.Status)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
+
+	report := domain.ImportReport{Rows: 2, Errors: []domain.ImportRowError{{Row: 3, Field: "name", Message: "name is required"}}}
+	mockImportService := new(mocks.ImportService)
+	mockImportService.On("Get", mock.Anything, "i1").
+		Return(domain.Import{ID: "i1", Kind: domain.ImportDepartments, Status: domain.ImportFailed, Report: report}, nil).Once()
+	mockImportService.On("Get", mock.Anything, "i2").Return(domain.Import{}, domain.ErrNotFound).Once()
+	handler.AddImportHandler(e, mockImportService, 1024)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/imports/i1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res domain.Import
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Equal(t, report, res.Report)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/imports/i2", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	mockImportService.AssertExpectations(t)
+}
@@ -0,0 +1,239 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/segmentio/ksuid"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// Repository implement all import repository method from interface
+type Repository struct {
+	DB *sql.DB
+}
+
+// New return new import repository
+func New(db *sql.DB) Repository {
+	return Repository{
+		DB: db,
+	}
+}
+
+var importColumns = []string{"id", "tenant_id", "kind", "status", "dry_run", "report", "error", "created_time", "updated_time", "finished_time"}
+
+// Create is a repository to insert an import of the tenant with its CSV
+func (r Repository) Create(ctx context.Context, imp *domain.Import, data []byte) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if imp.ID == "" {
+		imp.ID = ksuid.New().String()
+	}
+
+	imp.TenantID = domain.TenantFromContext(ctx)
+	imp.CreatedTime = localTime
+	imp.UpdatedTime = localTime
+
+	query, args, err := sq.Insert("imports").
+		Columns("id", "tenant_id", "kind", "status", "dry_run", "data", "created_time", "updated_time").
+		Values(imp.ID, imp.TenantID, imp.Kind, imp.Status, imp.DryRun, string(data), imp.CreatedTime, imp.UpdatedTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed insert an import")
+	}
+
+	return
+}
+
+// Get is a repository to get an import of the tenant
+func (r Repository) Get(ctx context.Context, id string) (imp domain.Import, err error) {
+	query, args, err := sq.Select(importColumns...).
+		From("imports").
+		Where(sq.Eq{"id": id, "tenant_id": domain.TenantFromContext(ctx)}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	imp, err = scanImport(r.DB.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+// Claim is a repository to mark the oldest pending import of every tenant as running, a running import
+// claimed more than runTimeout ago is claimed again. An import claimed by another worker meanwhile is skipped
+func (r Repository) Claim(ctx context.Context, runTimeout time.Duration) (imp domain.Import, data []byte, err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	claimable := sq.Or{
+		sq.Eq{"status": domain.ImportPending},
+		sq.And{sq.Eq{"status": domain.ImportRunning}, sq.Lt{"claimed_time": localTime.Add(-runTimeout)}},
+	}
+
+	for {
+		var id string
+		query, args, er := sq.Select("id").
+			From("imports").
+			Where(claimable).
+			OrderBy("id asc").
+			Limit(1).
+			ToSql()
+		if er != nil {
+			return imp, nil, er
+		}
+
+		err = r.DB.QueryRowContext(ctx, query, args...).Scan(&id)
+		if err == sql.ErrNoRows {
+			err = domain.ErrNotFound
+		}
+		if err != nil {
+			return
+		}
+
+		query, args, err = sq.Update("imports").
+			SetMap(sq.Eq{
+				"status":       domain.ImportRunning,
+				"updated_time": localTime,
+				"claimed_time": localTime,
+			}).
+			Where(sq.And{sq.Eq{"id": id}, claimable}).
+			ToSql()
+		if err != nil {
+			return
+		}
+
+		res, er := r.DB.ExecContext(ctx, query, args...)
+		if er != nil {
+			return imp, nil, errors.Wrap(er, "failed claim an import")
+		}
+
+		count, er := res.RowsAffected()
+		if er != nil {
+			return imp, nil, er
+		}
+
+		if count == 1 {
+			return r.getWithData(ctx, id)
+		}
+	}
+}
+
+func (r Repository) getWithData(ctx context.Context, id string) (imp domain.Import, data []byte, err error) {
+	query, args, err := sq.Select(append(importColumns, "data")...).
+		From("imports").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	var raw string
+	imp, err = scanImport(r.DB.QueryRowContext(ctx, query, args...), &raw)
+	return imp, []byte(raw), err
+}
+
+// Update is a repository to store the status and the report of an import
+func (r Repository) Update(ctx context.Context, imp domain.Import) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	report, err := json.Marshal(imp.Report)
+	if err != nil {
+		return
+	}
+
+	importError := sql.NullString{String: imp.Error, Valid: imp.Error != ""}
+
+	query, args, err := sq.Update("imports").
+		SetMap(sq.Eq{
+			"status":        imp.Status,
+			"report":        string(report),
+			"error":         importError,
+			"updated_time":  localTime,
+			"finished_time": imp.FinishedTime,
+		}).
+		Where(sq.Eq{"id": imp.ID}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed update an import")
+		return
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if count == 0 {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+// scanImport scans the import columns of the row followed by the extra columns
+func scanImport(row *sql.Row, extra ...interface{}) (imp domain.Import, err error) {
+	var (
+		report       sql.NullString
+		importError  sql.NullString
+		finishedTime mysql.NullTime
+	)
+
+	dest := append([]interface{}{
+		&imp.ID,
+		&imp.TenantID,
+		&imp.Kind,
+		&imp.Status,
+		&imp.DryRun,
+		&report,
+		&importError,
+		&imp.CreatedTime,
+		&imp.UpdatedTime,
+		&finishedTime,
+	}, extra...)
+
+	err = row.Scan(dest...)
+	if err != nil {
+		return
+	}
+
+	if report.Valid {
+		if err = json.Unmarshal([]byte(report.String), &imp.Report); err != nil {
+			return
+		}
+	}
+
+	imp.Error = importError.String
+	if finishedTime.Valid {
+		imp.FinishedTime = &finishedTime.Time
+	}
+
+	return
+}
@@ -0,0 +1,102 @@
+package mariadb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	mariadb "github.com/milhamhidayat/golang-clean-code-v2/driver/mariadb"
+	repo "github.com/milhamhidayat/golang-clean-code-v2/imports/repository/mariadb"
+)
+
+type importSuite struct {
+	mariadb.DBSuite
+}
+
+func TestImportSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped for short testing")
+	}
+	suite.Run(t, new(importSuite))
+}
+
+func (i *importSuite) SetupTest() {
+	_, err := i.DB.Exec("TRUNCATE imports")
+	require.NoError(i.T(), err)
+}
+
+func (i *importSuite) TestLifecycle() {
+	importRepo := repo.New(i.DB)
+	ctx := domain.NewContextWithTenant(context.Background(), "acme")
+	data := []byte("name\nFinance\n")
+
+	imp := domain.Import{Kind: domain.ImportDepartments, Status: domain.ImportPending, DryRun: true}
+	require.NoError(i.T(), importRepo.Create(ctx, &imp, data))
+
+	i.T().Run("claim the pending import", func(t *testing.T) {
+		claimed, claimedData, err := importRepo.Claim(context.Background(), time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, imp.ID, claimed.ID)
+		require.Equal(t, "acme", claimed.TenantID)
+		require.Equal(t, domain.ImportRunning, claimed.Status)
+		require.True(t, claimed.DryRun)
+		require.Equal(t, data, claimedData)
+
+		_, _, err = importRepo.Claim(context.Background(), time.Hour)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+
+	i.T().Run("store the report", func(t *testing.T) {
+		finishedTime := time.Now()
+		imp.Status = domain.ImportFailed
+		imp.Report = domain.ImportReport{Rows: 1, Errors: []domain.ImportRowError{{Row: 2, Field: "name", Message: "name is required"}}}
+		imp.Error = "1 rows are not valid"
+		imp.FinishedTime = &finishedTime
+		require.NoError(t, importRepo.Update(context.Background(), imp))
+
+		res, err := importRepo.Get(ctx, imp.ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.ImportFailed, res.Status)
+		require.Equal(t, imp.Report, res.Report)
+		require.Equal(t, imp.Error, res.Error)
+		require.NotNil(t, res.FinishedTime)
+	})
+
+	i.T().Run("import of another tenant", func(t *testing.T) {
+		_, err := importRepo.Get(domain.NewContextWithTenant(context.Background(), "globex"), imp.ID)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+}
+
+func (i *importSuite) TestClaimStale() {
+	importRepo := repo.New(i.DB)
+	ctx := domain.NewContextWithTenant(context.Background(), "acme")
+
+	imp := domain.Import{Kind: domain.ImportDepartments, Status: domain.ImportPending}
+	require.NoError(i.T(), importRepo.Create(ctx, &imp, []byte("name\nFinance\n")))
+
+	_, _, err := importRepo.Claim(context.Background(), time.Hour)
+	require.NoError(i.T(), err)
+
+	i.T().Run("running import isn't claimed again before the timeout", func(t *testing.T) {
+		_, _, err := importRepo.Claim(context.Background(), time.Hour)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+
+	i.T().Run("running import is claimed again after the timeout", func(t *testing.T) {
+		_, err := i.DB.Exec("UPDATE imports SET claimed_time = claimed_time - INTERVAL 2 HOUR WHERE id = ?", imp.ID)
+		require.NoError(t, err)
+
+		claimed, _, err := importRepo.Claim(context.Background(), time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, imp.ID, claimed.ID)
+		require.Equal(t, domain.ImportRunning, claimed.Status)
+
+		_, _, err = importRepo.Claim(context.Background(), time.Hour)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Authorization is an import service only allowing hr-admin to import departments and employees.
+// An API key is authorized by the departments:write or employees:write scope of the imported kind instead
+type Authorization struct {
+	next domain.ImportService
+}
+
+// NewAuthorization will create an import service only allowing hr-admin to import
+func NewAuthorization(next domain.ImportService) Authorization {
+	return Authorization{
+		next: next,
+	}
+}
+
+var writeScopes = map[string]string{
+	domain.ImportDepartments: domain.ScopeDepartmentsWrite,
+	domain.ImportEmployees:   domain.ScopeEmployeesWrite,
+}
+
+func authorize(ctx context.Context, kind string) (err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	if !p.HasRole(domain.RoleHRAdmin) && (writeScopes[kind] == "" || !p.HasScope(writeScopes[kind])) {
+		return domain.ErrForbidden
+	}
+
+	return
+}
+
+// Run will import the rows of the CSV when the caller may write the kind
+func (a Authorization) Run(ctx context.Context, kind string, r io.Reader, dryRun bool) (report domain.ImportReport, err error) {
+	if err = authorize(ctx, kind); err != nil {
+		return
+	}
+
+	return a.next.Run(ctx, kind, r, dryRun)
+}
+
+// Create will schedule an import when the caller may write the kind
+func (a Authorization) Create(ctx context.Context, imp *domain.Import, data []byte) (err error) {
+	if err = authorize(ctx, imp.Kind); err != nil {
+		return
+	}
+
+	return a.next.Create(ctx, imp, data)
+}
+
+// Get will get an import when the caller may write its kind
+func (a Authorization) Get(ctx context.Context, id string) (imp domain.Import, err error) {
+	if _, ok := domain.PrincipalFromContext(ctx); !ok {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	imp, err = a.next.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if err = authorize(ctx, imp.Kind); err != nil {
+		return domain.Import{}, err
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/imports/service"
+)
+
+func TestAuthorization(t *testing.T) {
+	tests := map[string]struct {
+		principal   *domain.Principal
+		kind        string
+		expectedErr error
+	}{
+		"hr admin imports employees": {
+			principal: &domain.Principal{Subject: "hr", Roles: []domain.Role{domain.RoleHRAdmin}},
+			kind:      domain.ImportEmployees,
+		},
+		"department head imports employees": {
+			principal:   &domain.Principal{Subject: "head", Roles: []domain.Role{domain.RoleDepartmentHead}, DepartmentID: "d1"},
+			kind:        domain.ImportEmployees,
+			expectedErr: domain.ErrForbidden,
+		},
+		"api key with departments write scope imports departments": {
+			principal: &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeDepartmentsWrite}},
+			kind:      domain.ImportDepartments,
+		},
+		"api key with departments write scope imports employees": {
+			principal:   &domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeDepartmentsWrite}},
+			kind:        domain.ImportEmployees,
+			expectedErr: domain.ErrForbidden,
+		},
+		"not authenticated": {
+			kind:        domain.ImportDepartments,
+			expectedErr: domain.ErrUnauthorized,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.principal != nil {
+				ctx = domain.NewContextWithPrincipal(ctx, *tc.principal)
+			}
+
+			mockImportService := new(mocks.ImportService)
+			if tc.expectedErr == nil {
+				mockImportService.On("Create", ctx, mock.Anything, []byte("name\n")).Return(nil).Once()
+			}
+
+			imp := domain.Import{Kind: tc.kind}
+			err := service.NewAuthorization(mockImportService).Create(ctx, &imp, []byte("name\n"))
+
+			mockImportService.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestAuthorizationGet(t *testing.T) {
+	ctx := domain.NewContextWithPrincipal(context.Background(), domain.Principal{Subject: "apikey:1", Scopes: []string{domain.ScopeEmployeesWrite}})
+
+	mockImportService := new(mocks.ImportService)
+	mockImportService.On("Get", ctx, "i1").Return(domain.Import{ID: "i1", Kind: domain.ImportDepartments}, nil).Once()
+
+	imp, err := service.NewAuthorization(mockImportService).Get(ctx, "i1")
+
+	mockImportService.AssertExpectations(t)
+	require.Equal(t, domain.ErrForbidden, err)
+	require.Equal(t, domain.Import{}, imp)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// utf8BOM is written by the spreadsheets at the start of a CSV
+const utf8BOM = "\uFEFF"
+
+// record is a row of the CSV keyed by its columns
+type record struct {
+	row    int
+	values map[string]string
+	// problem tells why the row can't be read, e.g. a wrong number of columns
+	problem string
+}
+
+func (r record) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+// readCSV reads the records of a CSV whose header names the columns,
+// the columns are matched case insensitively and the unknown ones are rejected
+func readCSV(r io.Reader, columns []string, required ...string) (records []record, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// a row with a wrong number of columns is reported with the other invalid rows
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, domain.ConstraintErrorf("csv is empty")
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	known := map[string]bool{}
+	for _, c := range columns {
+		known[c] = true
+	}
+
+	seen := map[string]bool{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, utf8BOM)))
+		if !known[h] {
+			return nil, domain.ConstraintErrorf("unknown column %q, the columns are %s", h, strings.Join(columns, ", "))
+		}
+		if seen[h] {
+			return nil, domain.ConstraintErrorf("column %s is repeated", h)
+		}
+		seen[h] = true
+		header[i] = h
+	}
+
+	for _, c := range required {
+		if !seen[c] {
+			return nil, domain.ConstraintErrorf("column %s is required", c)
+		}
+	}
+
+	for row := 2; ; row++ {
+		values, er := reader.Read()
+		if er == io.EOF {
+			return
+		}
+		if er != nil {
+			return nil, csvError(er)
+		}
+
+		rec := record{row: row, values: make(map[string]string, len(header))}
+		if len(values) != len(header) {
+			rec.problem = fmt.Sprintf("row has %d columns, the header has %d", len(values), len(header))
+		} else {
+			for i, h := range header {
+				rec.values[h] = values[i]
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return domain.ConstraintErrorf("csv is not valid: %v", parseErr)
+	}
+
+	return err
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
+)
+
+// Columns of the CSV of each kind, they are named as the JSON fields of the department and the employee.
+// An employee is assigned by the name of its department or by department_id
+var (
+	DepartmentColumns = []string{"id", "name", "description"}
+	EmployeeColumns   = []string{"id", "first_name", "last_name", "birth_place", "date_of_birth", "title", "department", "department_id"}
+)
+
+// fetchSize is the number of departments fetched at a time to resolve their names
+const fetchSize = 100
+
+// Service is an import service, the rows are upserted by batches, each one in its own transaction
+type Service struct {
+	repo           domain.ImportRepository
+	departmentRepo domain.DepartmentRepository
+	employeeRepo   domain.EmployeeRepository
+	batchSize      int
+	runTimeout     time.Duration
+}
+
+// New will create a new import service, an import is cancelled after runTimeout
+// and a running import claimed before runTimeout is claimed again
+func New(repo domain.ImportRepository, departmentRepo domain.DepartmentRepository, employeeRepo domain.EmployeeRepository, batchSize int, runTimeout time.Duration) Service {
+	return Service{
+		repo:           repo,
+		departmentRepo: departmentRepo,
+		employeeRepo:   employeeRepo,
+		batchSize:      batchSize,
+		runTimeout:     runTimeout,
+	}
+}
+
+// Run will validate every row of the CSV and upsert them when they are all valid and it isn't a dry run.
+// A row with an id updates the department or the employee of the id, which must exist in the tenant,
+// a department without id updates the department of the same name. The rows of the batches committed before a failure stay written
+func (s Service) Run(ctx context.Context, kind string, r io.Reader, dryRun bool) (report domain.ImportReport, err error) {
+	switch kind {
+	case domain.ImportDepartments:
+		return s.runDepartments(ctx, r, dryRun)
+	case domain.ImportEmployees:
+		return s.runEmployees(ctx, r, dryRun)
+	}
+
+	err = validateKind(kind)
+	return
+}
+
+// Create will schedule an import of the CSV
+func (s Service) Create(ctx context.Context, imp *domain.Import, data []byte) (err error) {
+	if err = validateKind(imp.Kind); err != nil {
+		return
+	}
+
+	imp.Status = domain.ImportPending
+	if err = s.repo.Create(ctx, imp, data); err != nil {
+		err = errors.Wrap(err, "failed to create an import")
+	}
+
+	return
+}
+
+// Get will return an import
+func (s Service) Get(ctx context.Context, id string) (imp domain.Import, err error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Process will run the oldest pending import in the tenant of the import within the run timeout,
+// ErrNotFound is returned when there is no pending import
+func (s Service) Process(ctx context.Context) (imp domain.Import, err error) {
+	imp, data, err := s.repo.Claim(ctx, s.runTimeout)
+	if err != nil {
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(domain.NewContextWithTenant(ctx, imp.TenantID), s.runTimeout)
+	report, runErr := s.Run(runCtx, imp.Kind, bytes.NewReader(data), imp.DryRun)
+	cancel()
+
+	finishedTime := time.Now()
+	imp.Report = report
+	imp.FinishedTime = &finishedTime
+	switch {
+	case runErr != nil:
+		imp.Status = domain.ImportFailed
+		imp.Error = runErr.Error()
+	case len(report.Errors) > 0:
+		imp.Status = domain.ImportFailed
+		imp.Error = fmt.Sprintf("%d rows are not valid", countRows(report.Errors))
+	default:
+		imp.Status = domain.ImportSucceeded
+	}
+
+	if err = s.repo.Update(ctx, imp); err != nil {
+		err = errors.Wrapf(err, "failed to store the outcome of import %s", imp.ID)
+	}
+
+	return
+}
+
+func (s Service) runDepartments(ctx context.Context, r io.Reader, dryRun bool) (report domain.ImportReport, err error) {
+	records, err := readCSV(r, DepartmentColumns, "name")
+	if err != nil {
+		return
+	}
+
+	existing, err := s.departments(ctx)
+	if err != nil {
+		return
+	}
+
+	report.Rows = len(records)
+	departments := make([]domain.Department, 0, len(records))
+	seenIDs := map[string]int{}
+	seenNames := map[string]int{}
+	for _, rec := range records {
+		if rec.problem != "" {
+			report.Errors = append(report.Errors, domain.ImportRowError{Row: rec.row, Message: rec.problem})
+			continue
+		}
+
+		d := domain.Department{
+			ID:          rec.get("id"),
+			Name:        rec.get("name"),
+			Description: rec.get("description"),
+		}
+
+		rowErrors := violations(rec.row, validator.Validate(d))
+		if d.ID != "" && !existing.byID[d.ID] {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "id", Message: fmt.Sprintf("id %s is not found", d.ID)})
+		}
+
+		name := strings.ToLower(d.Name)
+		if row, ok := seenNames[name]; ok && d.Name != "" {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "name", Message: fmt.Sprintf("name is already imported by row %d", row)})
+		}
+		seenNames[name] = rec.row
+
+		if d.ID == "" && d.Name != "" {
+			switch ids := existing.byName[name]; len(ids) {
+			case 0:
+			case 1:
+				d.ID = ids[0]
+			default:
+				rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "name", Message: fmt.Sprintf("%d departments are named %s, set the id of the one to update", len(ids), d.Name)})
+			}
+		}
+
+		if row, ok := seenIDs[d.ID]; ok && d.ID != "" {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "id", Message: fmt.Sprintf("id is already imported by row %d", row)})
+		}
+		seenIDs[d.ID] = rec.row
+
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+
+		departments = append(departments, d)
+	}
+
+	if len(report.Errors) > 0 {
+		return
+	}
+
+	if dryRun {
+		for _, d := range departments {
+			if d.ID != "" {
+				report.Updated++
+			} else {
+				report.Created++
+			}
+		}
+		return
+	}
+
+	for start := 0; start < len(departments); start += s.batchSize {
+		end := min(start+s.batchSize, len(departments))
+		created, updated, er := s.departmentRepo.Upsert(ctx, departments[start:end])
+		if er != nil {
+			err = errors.Wrapf(er, "failed to import departments %d to %d", start+1, end)
+			return
+		}
+		report.Created += created
+		report.Updated += updated
+	}
+
+	return
+}
+
+func (s Service) runEmployees(ctx context.Context, r io.Reader, dryRun bool) (report domain.ImportReport, err error) {
+	records, err := readCSV(r, EmployeeColumns, "first_name", "date_of_birth")
+	if err != nil {
+		return
+	}
+
+	existing, err := s.departments(ctx)
+	if err != nil {
+		return
+	}
+
+	existingEmployees, err := s.employees(ctx, records)
+	if err != nil {
+		return
+	}
+
+	report.Rows = len(records)
+	employees := make([]domain.Employee, 0, len(records))
+	seenIDs := map[string]int{}
+	for _, rec := range records {
+		if rec.problem != "" {
+			report.Errors = append(report.Errors, domain.ImportRowError{Row: rec.row, Message: rec.problem})
+			continue
+		}
+
+		e := domain.Employee{
+			ID:          rec.get("id"),
+			FirstName:   rec.get("first_name"),
+			LastName:    rec.get("last_name"),
+			BirthPlace:  rec.get("birth_place"),
+			DateOfBirth: rec.get("date_of_birth"),
+			Title:       rec.get("title"),
+			Department:  domain.Department{ID: rec.get("department_id")},
+		}
+
+		rowErrors := violations(rec.row, validator.Validate(e))
+		if _, er := time.Parse("2006-01-02", e.DateOfBirth); er != nil && e.DateOfBirth != "" {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "date_of_birth", Message: "date_of_birth must be a date formatted as YYYY-MM-DD"})
+		}
+		if e.ID != "" && !existingEmployees[e.ID] {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "id", Message: fmt.Sprintf("id %s is not found", e.ID)})
+		}
+
+		departmentName := rec.get("department")
+		switch {
+		case e.Department.ID != "":
+			if !existing.byID[e.Department.ID] {
+				rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "department_id", Message: fmt.Sprintf("department %s is not found", e.Department.ID)})
+			}
+		case departmentName != "":
+			switch ids := existing.byName[strings.ToLower(departmentName)]; len(ids) {
+			case 0:
+				rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "department", Message: fmt.Sprintf("department %s is not found", departmentName)})
+			case 1:
+				e.Department.ID = ids[0]
+			default:
+				rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "department", Message: fmt.Sprintf("%d departments are named %s, use department_id", len(ids), departmentName)})
+			}
+		default:
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "department", Message: "department or department_id is required"})
+		}
+
+		if row, ok := seenIDs[e.ID]; ok && e.ID != "" {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: rec.row, Field: "id", Message: fmt.Sprintf("id is already imported by row %d", row)})
+		}
+		seenIDs[e.ID] = rec.row
+
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+
+		employees = append(employees, e)
+	}
+
+	if len(report.Errors) > 0 {
+		return
+	}
+
+	if dryRun {
+		for _, e := range employees {
+			if e.ID != "" {
+				report.Updated++
+			} else {
+				report.Created++
+			}
+		}
+		return
+	}
+
+	for start := 0; start < len(employees); start += s.batchSize {
+		end := min(start+s.batchSize, len(employees))
+		created, updated, er := s.employeeRepo.Upsert(ctx, employees[start:end])
+		if er != nil {
+			err = errors.Wrapf(er, "failed to import employees %d to %d", start+1, end)
+			return
+		}
+		report.Created += created
+		report.Updated += updated
+	}
+
+	return
+}
+
+// employees returns the ids of the records which are employees of the tenant
+func (s Service) employees(ctx context.Context, records []record) (existing map[string]bool, err error) {
+	existing = map[string]bool{}
+
+	ids := make([]string, 0, len(records))
+	for _, rec := range records {
+		if id := rec.get("id"); id != "" && rec.problem == "" {
+			ids = append(ids, id)
+		}
+	}
+
+	for start := 0; start < len(ids); start += fetchSize {
+		end := min(start+fetchSize, len(ids))
+		employees, _, er := s.employeeRepo.Fetch(ctx, domain.EmployeeFilter{IDs: ids[start:end], Num: end - start})
+		if er != nil {
+			return existing, errors.Wrap(er, "failed to fetch employees")
+		}
+
+		for _, e := range employees {
+			existing[e.ID] = true
+		}
+	}
+
+	return
+}
+
+// departmentIndex resolves the existing departments by their id and by their lower cased name
+type departmentIndex struct {
+	byID   map[string]bool
+	byName map[string][]string
+}
+
+// departments indexes every department of the tenant
+func (s Service) departments(ctx context.Context) (index departmentIndex, err error) {
+	index = departmentIndex{byID: map[string]bool{}, byName: map[string][]string{}}
+
+	filter := domain.DepartmentFilter{Num: fetchSize}
+	for {
+		departments, pagination, er := s.departmentRepo.Fetch(ctx, filter)
+		if er != nil {
+			return index, errors.Wrap(er, "failed to fetch departments")
+		}
+
+		for _, d := range departments {
+			index.byID[d.ID] = true
+			name := strings.ToLower(d.Name)
+			index.byName[name] = append(index.byName[name], d.ID)
+		}
+
+		if len(departments) < fetchSize || pagination.NextCursor == "" {
+			return
+		}
+		filter.Cursor = pagination.NextCursor
+	}
+}
+
+// violations converts the violations of a row to row errors
+func violations(row int, err error) (rowErrors []domain.ImportRowError) {
+	verr, ok := err.(domain.ValidationError)
+	if !ok {
+		return
+	}
+
+	for _, v := range verr.Violations {
+		rowErrors = append(rowErrors, domain.ImportRowError{Row: row, Field: v.Field, Message: v.Message})
+	}
+
+	return
+}
+
+// countRows counts the rows having an error
+func countRows(rowErrors []domain.ImportRowError) int {
+	rows := map[int]bool{}
+	for _, e := range rowErrors {
+		rows[e.Row] = true
+	}
+
+	return len(rows)
+}
+
+func validateKind(kind string) (err error) {
+	if kind != domain.ImportDepartments && kind != domain.ImportEmployees {
+		err = domain.ConstraintErrorf("kind must be one of %s, %s", domain.ImportDepartments, domain.ImportEmployees)
+	}
+
+	return
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
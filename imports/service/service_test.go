@@ -0,0 +1,245 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/imports/service"
+)
+
+var existingDepartments = []domain.Department{
+	{ID: "d1", Name: "Engineering"},
+	{ID: "d2", Name: "Sales"},
+	{ID: "d3", Name: "Sales"},
+}
+
+func mockDepartments() *mocks.DepartmentRepository {
+	mockDepartmentRepo := new(mocks.DepartmentRepository)
+	mockDepartmentRepo.On("Fetch", mock.Anything, domain.DepartmentFilter{Num: 100}).
+		Return(existingDepartments, domain.Pagination{NextCursor: "ZDM="}, nil).Once()
+	return mockDepartmentRepo
+}
+
+func TestRunDepartments(t *testing.T) {
+	tests := map[string]struct {
+		csv            string
+		dryRun         bool
+		upserted       [][]domain.Department
+		expectedReport domain.ImportReport
+		expectedError  error
+	}{
+		"success": {
+			csv: "\uFEFFName,Description\nengineering,Builds things\nFinance,Counts things\nLegal,\n",
+			upserted: [][]domain.Department{
+				{{ID: "d1", Name: "engineering", Description: "Builds things"}, {Name: "Finance", Description: "Counts things"}},
+				{{Name: "Legal"}},
+			},
+			expectedReport: domain.ImportReport{Rows: 3, Created: 2, Updated: 1},
+		},
+		"dry run": {
+			csv:            "id,name\nd2,Marketing\n,Finance\n",
+			dryRun:         true,
+			expectedReport: domain.ImportReport{Rows: 2, Created: 1, Updated: 1},
+		},
+		"invalid rows": {
+			csv: "name,description\n,No name\nSales,Ambiguous\nFinance,Counts things\nfinance,Again\nLegal\n",
+			expectedReport: domain.ImportReport{Rows: 5, Errors: []domain.ImportRowError{
+				{Row: 2, Field: "name", Message: "name is required"},
+				{Row: 3, Field: "name", Message: "2 departments are named Sales, set the id of the one to update"},
+				{Row: 5, Field: "name", Message: "name is already imported by row 4"},
+				{Row: 6, Message: "row has 1 columns, the header has 2"},
+			}},
+		},
+		"unknown id": {
+			csv: "id,name\nd1,Engineering\n0ujzPyRiIAffKhBux4PvQdDqMHY,Finance\n",
+			expectedReport: domain.ImportReport{Rows: 2, Errors: []domain.ImportRowError{
+				{Row: 3, Field: "id", Message: "id 0ujzPyRiIAffKhBux4PvQdDqMHY is not found"},
+			}},
+		},
+		"unknown column": {
+			csv:           "name,budget\nFinance,10\n",
+			expectedError: domain.ConstraintErrorf(`unknown column "budget", the columns are id, name, description`),
+		},
+		"missing column": {
+			csv:           "description\nCounts things\n",
+			expectedError: domain.ConstraintErrorf("column name is required"),
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockDepartmentRepo := new(mocks.DepartmentRepository)
+			if tc.expectedError == nil {
+				mockDepartmentRepo = mockDepartments()
+			}
+			for _, batch := range tc.upserted {
+				created := 0
+				for _, d := range batch {
+					if d.ID == "" {
+						created++
+					}
+				}
+				mockDepartmentRepo.On("Upsert", mock.Anything, batch).Return(created, len(batch)-created, nil).Once()
+			}
+
+			importService := service.New(new(mocks.ImportRepository), mockDepartmentRepo, new(mocks.EmployeeRepository), 2, time.Minute)
+			report, err := importService.Run(context.Background(), domain.ImportDepartments, strings.NewReader(tc.csv), tc.dryRun)
+
+			mockDepartmentRepo.AssertExpectations(t)
+			if tc.expectedError != nil {
+				require.Equal(t, tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedReport, report)
+		})
+	}
+}
+
+func TestRunEmployees(t *testing.T) {
+	tests := map[string]struct {
+		csv            string
+		dryRun         bool
+		upserted       []domain.Employee
+		fetched        []string
+		existing       []domain.Employee
+		expectedReport domain.ImportReport
+	}{
+		"success": {
+			csv: "id,first_name,last_name,date_of_birth,title,department,department_id\n" +
+				"e1,John,Doe,1990-01-02,Engineer,Engineering,\n" +
+				",Jane,,1991-03-04,Seller,,d2\n",
+			upserted: []domain.Employee{
+				{ID: "e1", FirstName: "John", LastName: "Doe", DateOfBirth: "1990-01-02", Title: "Engineer", Department: domain.Department{ID: "d1"}},
+				{FirstName: "Jane", DateOfBirth: "1991-03-04", Title: "Seller", Department: domain.Department{ID: "d2"}},
+			},
+			fetched:        []string{"e1"},
+			existing:       []domain.Employee{{ID: "e1"}},
+			expectedReport: domain.ImportReport{Rows: 2, Created: 1, Updated: 1},
+		},
+		"dry run": {
+			csv:            "id,first_name,date_of_birth,department\ne1,John,1990-01-02,Engineering\n,Jane,1991-03-04,engineering\n",
+			dryRun:         true,
+			fetched:        []string{"e1"},
+			existing:       []domain.Employee{{ID: "e1"}},
+			expectedReport: domain.ImportReport{Rows: 2, Created: 1, Updated: 1},
+		},
+		"unknown id": {
+			csv:      "id,first_name,date_of_birth,department\ne1,John,1990-01-02,Engineering\ne9,Jane,1991-03-04,engineering\n",
+			fetched:  []string{"e1", "e9"},
+			existing: []domain.Employee{{ID: "e1"}},
+			expectedReport: domain.ImportReport{Rows: 2, Errors: []domain.ImportRowError{
+				{Row: 3, Field: "id", Message: "id e9 is not found"},
+			}},
+		},
+		"invalid rows": {
+			csv: "id,first_name,date_of_birth,department,department_id\n" +
+				",,1990-01-02,Engineering,\n" +
+				",John,02/01/1990,Engineering,\n" +
+				",John,1990-01-02,Finance,\n" +
+				",John,1990-01-02,Sales,\n" +
+				",John,1990-01-02,,d9\n" +
+				",John,1990-01-02,,\n",
+			expectedReport: domain.ImportReport{Rows: 6, Errors: []domain.ImportRowError{
+				{Row: 2, Field: "first_name", Message: "first_name is required"},
+				{Row: 3, Field: "date_of_birth", Message: "date_of_birth must be a date formatted as YYYY-MM-DD"},
+				{Row: 4, Field: "department", Message: "department Finance is not found"},
+				{Row: 5, Field: "department", Message: "2 departments are named Sales, use department_id"},
+				{Row: 6, Field: "department_id", Message: "department d9 is not found"},
+				{Row: 7, Field: "department", Message: "department or department_id is required"},
+			}},
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockEmployeeRepo := new(mocks.EmployeeRepository)
+			if tc.upserted != nil {
+				mockEmployeeRepo.On("Upsert", mock.Anything, tc.upserted).Return(1, 1, nil).Once()
+			}
+			if tc.fetched != nil {
+				mockEmployeeRepo.On("Fetch", mock.Anything, domain.EmployeeFilter{IDs: tc.fetched, Num: len(tc.fetched)}).
+					Return(tc.existing, domain.Pagination{}, nil).Once()
+			}
+
+			importService := service.New(new(mocks.ImportRepository), mockDepartments(), mockEmployeeRepo, 10, time.Minute)
+			report, err := importService.Run(context.Background(), domain.ImportEmployees, strings.NewReader(tc.csv), tc.dryRun)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedReport, report)
+		})
+	}
+}
+
+func TestRunUnknownKind(t *testing.T) {
+	importService := service.New(new(mocks.ImportRepository), new(mocks.DepartmentRepository), new(mocks.EmployeeRepository), 10, time.Minute)
+	_, err := importService.Run(context.Background(), "users", strings.NewReader("id\n"), false)
+	require.Equal(t, domain.ConstraintErrorf("kind must be one of departments, employees"), err)
+}
+
+func TestProcess(t *testing.T) {
+	tests := map[string]struct {
+		data           string
+		expectedStatus string
+		expectedError  string
+	}{
+		"succeeded": {
+			data:           "name\nFinance\n",
+			expectedStatus: domain.ImportSucceeded,
+		},
+		"invalid rows": {
+			data:           "name\n\"\"\n",
+			expectedStatus: domain.ImportFailed,
+			expectedError:  "1 rows are not valid",
+		},
+		"invalid csv": {
+			data:           "budget\n10\n",
+			expectedStatus: domain.ImportFailed,
+			expectedError:  `unknown column "budget", the columns are id, name, description`,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockImportRepo := new(mocks.ImportRepository)
+			mockImportRepo.On("Claim", mock.Anything, time.Minute).
+				Return(domain.Import{ID: "i1", Kind: domain.ImportDepartments, Status: domain.ImportRunning, TenantID: "acme"}, []byte(tc.data), nil).Once()
+			mockImportRepo.On("Update", mock.Anything, mock.MatchedBy(func(imp domain.Import) bool {
+				return imp.ID == "i1" && imp.Status == tc.expectedStatus && imp.Error == tc.expectedError && imp.FinishedTime != nil
+			})).Return(nil).Once()
+
+			mockDepartmentRepo := new(mocks.DepartmentRepository)
+			mockDepartmentRepo.On("Fetch", mock.MatchedBy(func(ctx context.Context) bool {
+				_, ok := ctx.Deadline()
+				return domain.TenantFromContext(ctx) == "acme" && ok
+			}), mock.Anything).Return([]domain.Department{}, domain.Pagination{}, nil).Maybe()
+			mockDepartmentRepo.On("Upsert", mock.Anything, mock.Anything).Return(1, 0, nil).Maybe()
+
+			imp, err := service.New(mockImportRepo, mockDepartmentRepo, new(mocks.EmployeeRepository), 10, time.Minute).Process(context.Background())
+
+			mockImportRepo.AssertExpectations(t)
+			mockDepartmentRepo.AssertExpectations(t)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStatus, imp.Status)
+		})
+	}
+}
+
+func TestProcessWithoutPendingImport(t *testing.T) {
+	mockImportRepo := new(mocks.ImportRepository)
+	mockImportRepo.On("Claim", mock.Anything, time.Minute).Return(domain.Import{}, nil, domain.ErrNotFound).Once()
+
+	_, err := service.New(mockImportRepo, new(mocks.DepartmentRepository), new(mocks.EmployeeRepository), 10, time.Minute).Process(context.Background())
+
+	mockImportRepo.AssertExpectations(t)
+	require.Equal(t, domain.ErrNotFound, err)
+}
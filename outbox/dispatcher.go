@@ -0,0 +1,77 @@
+// Package outbox publishes the domain events written to the outbox by the repositories
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+// Dispatcher publishes the pending events of the outbox in the order they were written.
+// An event is delivered at least once, it is published again when it can't be marked as published.
+// Only one dispatcher should run at a time so the order is kept
+type Dispatcher struct {
+	repository  domain.OutboxRepository
+	publisher   domain.Publisher
+	batchSize   int
+	maxAttempts int
+}
+
+// NewDispatcher will return a dispatcher publishing batchSize events at a time,
+// an event failing maxAttempts times is given up, zero retries it forever
+func NewDispatcher(repository domain.OutboxRepository, publisher domain.Publisher, batchSize, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		repository:  repository,
+		publisher:   publisher,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Dispatch publishes a batch of pending events, the batch stops at the first failure so the next
+// events aren't published before the failed one
+func (d *Dispatcher) Dispatch(ctx context.Context) (published int, err error) {
+	events, err := d.repository.FetchPending(ctx, d.batchSize, d.maxAttempts)
+	if err != nil {
+		return
+	}
+
+	for _, e := range events {
+		if err = d.publisher.Publish(ctx, e); err != nil {
+			if er := d.repository.MarkFailed(ctx, e.ID, err.Error()); er != nil {
+				logger.FromContext(ctx).Errorf("failed to mark outbox event %s as failed: %v", e.ID, er)
+			}
+
+			if d.maxAttempts > 0 && e.Attempts+1 >= d.maxAttempts {
+				logger.FromContext(ctx).Errorf("outbox event %s is given up after %d attempts", e.ID, d.maxAttempts)
+			}
+
+			err = errors.Wrapf(err, "failed publish outbox event %s", e.ID)
+			return
+		}
+
+		if err = d.repository.MarkPublished(ctx, e.ID, time.Now()); err != nil {
+			return
+		}
+		published++
+	}
+
+	return
+}
+
+// Drain dispatches the pending events until none is left, a failure or the context is done
+func (d *Dispatcher) Drain(ctx context.Context) (published int, err error) {
+	for ctx.Err() == nil {
+		n, err := d.Dispatch(ctx)
+		published += n
+		if err != nil || n < d.batchSize {
+			return published, err
+		}
+	}
+
+	return published, ctx.Err()
+}
@@ -0,0 +1,77 @@
+package outbox_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/outbox"
+	"github.com/milhamhidayat/golang-clean-code-v2/outbox/publisher"
+)
+
+func TestDispatch(t *testing.T) {
+	events := []domain.Event{
+		{ID: "1", Type: domain.EventDepartmentCreated, AggregateType: domain.AggregateDepartment, AggregateID: "d1"},
+		{ID: "2", Type: domain.EventEmployeeCreated, AggregateType: domain.AggregateEmployee, AggregateID: "e1"},
+		{ID: "3", Type: domain.EventEmployeeTransferred, AggregateType: domain.AggregateEmployee, AggregateID: "e1"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		repo := new(mocks.OutboxRepository)
+		repo.On("FetchPending", mock.Anything, 10, 3).Return(events, nil).Once()
+		for _, e := range events {
+			repo.On("MarkPublished", mock.Anything, e.ID, mock.Anything).Return(nil).Once()
+		}
+
+		pub := publisher.NewMemory()
+		published, err := outbox.NewDispatcher(repo, pub, 10, 3).Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, published)
+		require.Equal(t, events, pub.Events())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("failure stops the batch", func(t *testing.T) {
+		repo := new(mocks.OutboxRepository)
+		repo.On("FetchPending", mock.Anything, 10, 3).Return(events, nil).Once()
+		repo.On("MarkPublished", mock.Anything, "1", mock.Anything).Return(nil).Once()
+		repo.On("MarkFailed", mock.Anything, "2", "broker is down").Return(nil).Once()
+
+		pub := new(mocks.Publisher)
+		pub.On("Publish", mock.Anything, events[0]).Return(nil).Once()
+		pub.On("Publish", mock.Anything, events[1]).Return(errors.New("broker is down")).Once()
+
+		published, err := outbox.NewDispatcher(repo, pub, 10, 3).Dispatch(context.Background())
+		require.EqualError(t, err, "failed publish outbox event 2: broker is down")
+		require.Equal(t, 1, published)
+		repo.AssertExpectations(t)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("error fetch pending events", func(t *testing.T) {
+		repo := new(mocks.OutboxRepository)
+		repo.On("FetchPending", mock.Anything, 10, 3).Return(nil, errors.New("unexpected error")).Once()
+
+		_, err := outbox.NewDispatcher(repo, publisher.NewMemory(), 10, 3).Dispatch(context.Background())
+		require.EqualError(t, err, "unexpected error")
+	})
+}
+
+func TestDrain(t *testing.T) {
+	repo := new(mocks.OutboxRepository)
+	repo.On("FetchPending", mock.Anything, 2, 0).Return([]domain.Event{{ID: "1"}, {ID: "2"}}, nil).Once()
+	repo.On("FetchPending", mock.Anything, 2, 0).Return([]domain.Event{{ID: "3"}}, nil).Once()
+	repo.On("MarkPublished", mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+
+	pub := publisher.NewMemory()
+	published, err := outbox.NewDispatcher(repo, pub, 2, 0).Drain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, published)
+	require.Len(t, pub.Events(), 3)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,39 @@
+// Package publisher implements domain.Publisher
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Memory keeps the published events in memory, it is meant for the tests
+type Memory struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+// NewMemory will return an in-memory publisher
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Publish appends the event
+func (m *Memory) Publish(ctx context.Context, e domain.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, e)
+	return nil
+}
+
+// Events returns the published events in their publishing order
+func (m *Memory) Events() []domain.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := make([]domain.Event, len(m.events))
+	copy(events, m.events)
+	return events
+}
@@ -0,0 +1,22 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Multi publishes every event to each of its publishers in turn, an event failing on one of them is published
+// again to every publisher on retry
+type Multi []domain.Publisher
+
+// Publish publishes the event to each publisher until one fails
+func (m Multi) Publish(ctx context.Context, e domain.Event) (err error) {
+	for _, p := range m {
+		if err = p.Publish(ctx, e); err != nil {
+			return
+		}
+	}
+
+	return
+}
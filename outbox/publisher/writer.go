@@ -0,0 +1,46 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Writer writes every event as a JSON line
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriter will return a publisher writing the events to w, e.g. os.Stdout
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// NewFile will return a publisher appending the events to the file and the function closing it
+func NewFile(path string) (w *Writer, closeFile func() error, err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+
+	return NewWriter(f), f.Close, nil
+}
+
+// Publish writes the event
+func (w *Writer) Publish(ctx context.Context, e domain.Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err = w.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,56 @@
+package publisher_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/outbox/publisher"
+)
+
+func TestWriter(t *testing.T) {
+	e := domain.Event{
+		ID:            "1",
+		Type:          domain.EventDepartmentCreated,
+		TenantID:      "acme",
+		AggregateType: domain.AggregateDepartment,
+		AggregateID:   "d1",
+		OccurredTime:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:          json.RawMessage(`{"id":"d1","name":"Engineering"}`),
+	}
+
+	var buf bytes.Buffer
+	w := publisher.NewWriter(&buf)
+	require.NoError(t, w.Publish(context.Background(), e))
+	require.NoError(t, w.Publish(context.Background(), e))
+
+	expected := `{"id":"1","type":"department.created","tenant_id":"acme","aggregate_type":"department","aggregate_id":"d1",` +
+		`"occurred_time":"2020-01-02T03:04:05Z","data":{"id":"d1","name":"Engineering"}}` + "\n"
+	require.Equal(t, expected+expected, buf.String())
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "events.ndjson")
+	for i := 0; i < 2; i++ {
+		w, closeFile, err := publisher.NewFile(path)
+		require.NoError(t, err)
+		require.NoError(t, w.Publish(context.Background(), domain.Event{ID: "1", Data: json.RawMessage(`{}`)}))
+		require.NoError(t, closeFile())
+	}
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, bytes.Split(bytes.TrimSpace(content), []byte("\n")), 2)
+}
@@ -0,0 +1,226 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+// maxErrorLength bounds the stored reason of a failed attempt
+const maxErrorLength = 1024
+
+// Execer executes a statement, it is satisfied by *sql.DB and *sql.Tx
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Insert writes the events to the outbox, the repositories of the aggregates call it with the transaction
+// of the change so the events are only published when the change is committed
+func Insert(ctx context.Context, exec Execer, events ...domain.Event) (err error) {
+	if len(events) == 0 {
+		return
+	}
+
+	qInsert := sq.Insert("outbox_events").
+		Columns("id", "tenant_id", "event_type", "aggregate_type", "aggregate_id", "data", "occurred_time")
+	for _, e := range events {
+		qInsert = qInsert.Values(e.ID, e.TenantID, e.Type, e.AggregateType, e.AggregateID, string(e.Data), e.OccurredTime)
+	}
+
+	query, args, err := qInsert.ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = exec.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed insert outbox events")
+	}
+
+	return
+}
+
+// Repository implement all outbox and event repository method from interface
+type Repository struct {
+	DB *sql.DB
+	// CommitLag is the time after which a written event is read by the event log. The seq is taken
+	// when the event is inserted but the transactions commit in any order, an event of a lower seq
+	// can appear after an event of a higher seq was read, so the lag must exceed the longest transaction
+	CommitLag time.Duration
+}
+
+// New return new outbox repository
+func New(db *sql.DB, commitLag time.Duration) Repository {
+	return Repository{
+		DB:        db,
+		CommitLag: commitLag,
+	}
+}
+
+// FetchPending is a repository to fetch the oldest unpublished events
+func (r Repository) FetchPending(ctx context.Context, num, maxAttempts int) (events []domain.Event, err error) {
+	qSelect := sq.Select("id", "tenant_id", "event_type", "aggregate_type", "aggregate_id", "data", "occurred_time", "attempts").
+		From("outbox_events").
+		Where(sq.Eq{"published_time": nil}).
+		OrderBy("seq asc").
+		Limit(uint64(num))
+	if maxAttempts > 0 {
+		qSelect = qSelect.Where(sq.Lt{"attempts": maxAttempts})
+	}
+
+	return r.fetch(ctx, qSelect)
+}
+
+// Fetch is a repository to fetch the events of the tenant older than the commit lag in the order they were written
+func (r Repository) Fetch(ctx context.Context, filter domain.EventFilter) (events []domain.Event, err error) {
+	qSelect := r.committed(sq.Select("id", "tenant_id", "event_type", "aggregate_type", "aggregate_id", "data", "occurred_time", "attempts").
+		From("outbox_events").
+		Where(sq.Eq{"tenant_id": domain.TenantFromContext(ctx)}).
+		OrderBy("seq asc"))
+
+	if len(filter.AggregateTypes) > 0 {
+		qSelect = qSelect.Where(sq.Eq{"aggregate_type": filter.AggregateTypes})
+	}
+
+	if filter.AfterID != "" {
+		var seq int64
+		if seq, err = r.seqOf(ctx, filter.AfterID); err != nil {
+			return
+		}
+		qSelect = qSelect.Where(sq.Gt{"seq": seq})
+	}
+
+	if filter.Num > 0 {
+		qSelect = qSelect.Limit(uint64(filter.Num))
+	}
+
+	return r.fetch(ctx, qSelect)
+}
+
+// Latest is a repository to get the id of the latest event of the tenant older than the commit lag
+func (r Repository) Latest(ctx context.Context) (id string, err error) {
+	query, args, err := r.committed(sq.Select("id").
+		From("outbox_events").
+		Where(sq.Eq{"tenant_id": domain.TenantFromContext(ctx)}).
+		OrderBy("seq desc").
+		Limit(1)).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+
+	return
+}
+
+// committed keeps the events written before the commit lag, their seq can't be passed by an uncommitted event anymore
+func (r Repository) committed(qSelect sq.SelectBuilder) sq.SelectBuilder {
+	if r.CommitLag <= 0 {
+		return qSelect
+	}
+
+	return qSelect.Where("created_time <= NOW(6) - INTERVAL ? MICROSECOND", int64(r.CommitLag/time.Microsecond))
+}
+
+func (r Repository) seqOf(ctx context.Context, id string) (seq int64, err error) {
+	query, args, err := sq.Select("seq").
+		From("outbox_events").
+		Where(sq.Eq{"id": id, "tenant_id": domain.TenantFromContext(ctx)}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(&seq)
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+func (r Repository) fetch(ctx context.Context, qSelect sq.SelectBuilder) (events []domain.Event, err error) {
+	query, args, err := qSelect.ToSql()
+	if err != nil {
+		return
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed fetch outbox events")
+		return
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.FromContext(ctx).Errorf("failed to close outbox rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var (
+			e    domain.Event
+			data string
+		)
+		err = rows.Scan(&e.ID, &e.TenantID, &e.Type, &e.AggregateType, &e.AggregateID, &data, &e.OccurredTime, &e.Attempts)
+		if err != nil {
+			return
+		}
+
+		e.Data = []byte(data)
+		events = append(events, e)
+	}
+
+	err = rows.Err()
+	return
+}
+
+// MarkPublished is a repository to mark an event as published
+func (r Repository) MarkPublished(ctx context.Context, eventID string, publishedTime time.Time) (err error) {
+	query, args, err := sq.Update("outbox_events").
+		Set("published_time", publishedTime).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Where(sq.Eq{"id": eventID}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed mark an outbox event as published")
+	}
+
+	return
+}
+
+// MarkFailed is a repository to count a failed attempt of publishing an event
+func (r Repository) MarkFailed(ctx context.Context, eventID string, reason string) (err error) {
+	if len(reason) > maxErrorLength {
+		reason = reason[:maxErrorLength]
+	}
+
+	query, args, err := sq.Update("outbox_events").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Where(sq.Eq{"id": eventID}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed mark an outbox event as failed")
+	}
+
+	return
+}
@@ -0,0 +1,173 @@
+package mariadb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	mariadb "github.com/milhamhidayat/golang-clean-code-v2/driver/mariadb"
+	repo "github.com/milhamhidayat/golang-clean-code-v2/outbox/repository/mariadb"
+)
+
+type outboxSuite struct {
+	mariadb.DBSuite
+}
+
+func TestOutboxSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped for short testing")
+	}
+	suite.Run(t, new(outboxSuite))
+}
+
+func (o *outboxSuite) SetupTest() {
+	_, err := o.DB.Exec("TRUNCATE outbox_events")
+	require.NoError(o.T(), err)
+}
+
+func (o *outboxSuite) TestPublish() {
+	outboxRepo := repo.New(o.DB, 0)
+
+	var events []domain.Event
+	for _, id := range []string{"d1", "d2", "d3"} {
+		e, err := domain.NewEvent(context.Background(), domain.EventDepartmentCreated, domain.AggregateDepartment, id, map[string]string{"id": id})
+		require.NoError(o.T(), err)
+		events = append(events, e)
+	}
+
+	tx, err := o.DB.Begin()
+	require.NoError(o.T(), err)
+	require.NoError(o.T(), repo.Insert(context.Background(), tx, events...))
+	require.NoError(o.T(), tx.Commit())
+
+	o.T().Run("pending events in written order", func(t *testing.T) {
+		res, err := outboxRepo.FetchPending(context.Background(), 10, 2)
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+		for i, e := range res {
+			require.Equal(t, events[i].ID, e.ID)
+			require.JSONEq(t, string(events[i].Data), string(e.Data))
+		}
+	})
+
+	o.T().Run("published and given up events are excluded", func(t *testing.T) {
+		require.NoError(t, outboxRepo.MarkPublished(context.Background(), events[0].ID, time.Now()))
+		require.NoError(t, outboxRepo.MarkFailed(context.Background(), events[1].ID, "broker is down"))
+		require.NoError(t, outboxRepo.MarkFailed(context.Background(), events[1].ID, "broker is down"))
+
+		res, err := outboxRepo.FetchPending(context.Background(), 10, 2)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, events[2].ID, res[0].ID)
+	})
+}
+
+func (o *outboxSuite) TestRolledBackChangeHasNoEvent() {
+	outboxRepo := repo.New(o.DB, 0)
+
+	e, err := domain.NewEvent(context.Background(), domain.EventDepartmentDeleted, domain.AggregateDepartment, "d1", map[string]string{"id": "d1"})
+	require.NoError(o.T(), err)
+
+	tx, err := o.DB.Begin()
+	require.NoError(o.T(), err)
+	require.NoError(o.T(), repo.Insert(context.Background(), tx, e))
+	require.NoError(o.T(), tx.Rollback())
+
+	res, err := outboxRepo.FetchPending(context.Background(), 10, 0)
+	require.NoError(o.T(), err)
+	require.Len(o.T(), res, 0)
+}
+
+func (o *outboxSuite) TestEventLog() {
+	outboxRepo := repo.New(o.DB, 0)
+	ctx := domain.NewContextWithTenant(context.Background(), "acme")
+
+	var events []domain.Event
+	for _, aggregateType := range []string{domain.AggregateDepartment, domain.AggregateEmployee, domain.AggregateDepartment} {
+		e, err := domain.NewEvent(ctx, aggregateType+".created", aggregateType, "1", map[string]string{"id": "1"})
+		require.NoError(o.T(), err)
+		events = append(events, e)
+	}
+	other, err := domain.NewEvent(domain.NewContextWithTenant(context.Background(), "globex"),
+		domain.EventDepartmentCreated, domain.AggregateDepartment, "2", map[string]string{"id": "2"})
+	require.NoError(o.T(), err)
+
+	tx, err := o.DB.Begin()
+	require.NoError(o.T(), err)
+	require.NoError(o.T(), repo.Insert(context.Background(), tx, append(events, other)...))
+	require.NoError(o.T(), tx.Commit())
+
+	o.T().Run("latest event of the tenant", func(t *testing.T) {
+		id, err := outboxRepo.Latest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, events[2].ID, id)
+	})
+
+	o.T().Run("events after an event", func(t *testing.T) {
+		res, err := outboxRepo.Fetch(ctx, domain.EventFilter{AfterID: events[0].ID, Num: 10})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Equal(t, events[1].ID, res[0].ID)
+		require.Equal(t, events[2].ID, res[1].ID)
+	})
+
+	o.T().Run("events of an aggregate", func(t *testing.T) {
+		res, err := outboxRepo.Fetch(ctx, domain.EventFilter{AggregateTypes: []string{domain.AggregateDepartment}, Num: 10})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Equal(t, events[0].ID, res[0].ID)
+		require.Equal(t, events[2].ID, res[1].ID)
+	})
+
+	o.T().Run("unknown event", func(t *testing.T) {
+		_, err := outboxRepo.Fetch(ctx, domain.EventFilter{AfterID: other.ID, Num: 10})
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+}
+
+func (o *outboxSuite) TestEventLogOutOfOrderCommit() {
+	outboxRepo := repo.New(o.DB, time.Second)
+	ctx := domain.NewContextWithTenant(context.Background(), "acme")
+
+	var events []domain.Event
+	for _, id := range []string{"d1", "d2"} {
+		e, err := domain.NewEvent(ctx, domain.EventDepartmentCreated, domain.AggregateDepartment, id, map[string]string{"id": id})
+		require.NoError(o.T(), err)
+		events = append(events, e)
+	}
+
+	// the first event takes the lower seq but its transaction commits after the second one
+	slow, err := o.DB.Begin()
+	require.NoError(o.T(), err)
+	require.NoError(o.T(), repo.Insert(context.Background(), slow, events[0]))
+
+	fast, err := o.DB.Begin()
+	require.NoError(o.T(), err)
+	require.NoError(o.T(), repo.Insert(context.Background(), fast, events[1]))
+	require.NoError(o.T(), fast.Commit())
+
+	o.T().Run("event within the commit lag isn't read", func(t *testing.T) {
+		res, err := outboxRepo.Fetch(ctx, domain.EventFilter{Num: 10})
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+
+		id, err := outboxRepo.Latest(ctx)
+		require.NoError(t, err)
+		require.Empty(t, id)
+	})
+
+	require.NoError(o.T(), slow.Commit())
+	time.Sleep(1100 * time.Millisecond)
+
+	o.T().Run("events after the commit lag in written order", func(t *testing.T) {
+		res, err := outboxRepo.Fetch(ctx, domain.EventFilter{Num: 10})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Equal(t, events[0].ID, res[0].ID)
+		require.Equal(t, events[1].ID, res[1].ID)
+	})
+}
@@ -0,0 +1,218 @@
+// Package config loads the typed configuration of the application from the defaults, a YAML or TOML file,
+// the environment and the command line flags, a later source overrides an earlier one.
+//
+// Every field is described by its tags:
+//
+//	config   the key of the field in the file, nested keys are joined with dots e.g. mysql.uri
+//	env      the environment variable of the field
+//	default  the default value
+//	secret   the value is redacted when the config is printed
+//	validate the rules of go-playground/validator
+//	usage    the description of the flag
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+)
+
+// Config is the configuration of the application
+type Config struct {
+	Log         Log         `config:"log"`
+	Tracing     Tracing     `config:"tracing"`
+	Server      Server      `config:"server"`
+	Timeout     Timeout     `config:"timeout"`
+	MySQL       MySQL       `config:"mysql"`
+	Health      Health      `config:"health"`
+	JWT         JWT         `config:"jwt"`
+	OIDC        OIDC        `config:"oidc"`
+	Tenant      Tenant      `config:"tenant"`
+	RateLimit   RateLimit   `config:"rate_limit"`
+	Idempotency Idempotency `config:"idempotency"`
+	Outbox      Outbox      `config:"outbox"`
+	Webhook     Webhook     `config:"webhook"`
+	Events      Events      `config:"events"`
+	Import      Import      `config:"import"`
+}
+
+// Log is the configuration of the logger
+type Log struct {
+	Level  string `config:"level" env:"LOG_LEVEL" default:"info" validate:"oneof=debug info warn error fatal" usage:"debug, info, warn, error or fatal"`
+	Format string `config:"format" env:"LOG_FORMAT" default:"text" validate:"oneof=json text" usage:"json or text"`
+}
+
+// Tracing is the configuration of the tracer
+type Tracing struct {
+	Exporter    string  `config:"exporter" env:"TRACING_EXPORTER" default:"none" validate:"oneof=none stdout file otlp" usage:"none, stdout, file or otlp"`
+	File        string  `config:"file" env:"TRACING_FILE" usage:"file the spans are appended to by the file exporter"`
+	SampleRatio float64 `config:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1" validate:"min=0,max=1" usage:"ratio of the sampled traces which aren't sampled by the caller"`
+}
+
+// Server is the configuration of the servers
+type Server struct {
+	ShutdownTimeout time.Duration `config:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" default:"10s" validate:"min=0" usage:"time given to drain in-flight requests and stop the workers"`
+	TrustedProxies  string        `config:"trusted_proxies" env:"TRUSTED_PROXIES" usage:"comma separated CIDRs of the proxies whose X-Forwarded-For is trusted, the client ip is the peer address without them"`
+	Timezone        string        `config:"timezone" env:"TIMEZONE" default:"Asia/Jakarta" validate:"required" usage:"IANA zone the times are stored in, a date only bound of a time range filter is a day in it"`
+}
+
+// Timeout is the configuration of the deadlines of the requests and the service calls
+type Timeout struct {
+	RequestMS int    `config:"request_ms" env:"CONTEXT_TIMEOUT_MS" default:"2000" validate:"min=0" usage:"deadline of a request in milliseconds, zero disables it"`
+	Routes    string `config:"routes" env:"CONTEXT_TIMEOUT_ROUTES" usage:"comma separated per route deadlines, e.g. GET /employees=5s"`
+	ServiceMS int    `config:"service_ms" env:"SERVICE_TIMEOUT_MS" default:"2000" validate:"min=0" usage:"deadline in milliseconds of a service call which isn't bounded by its caller, e.g. a command"`
+	Methods   string `config:"methods" env:"SERVICE_TIMEOUT_METHODS" usage:"comma separated per method deadlines of the service calls, e.g. employee.Fetch=5s"`
+}
+
+// Request returns the deadline of a request as a duration
+func (t Timeout) Request() time.Duration {
+	return time.Duration(t.RequestMS) * time.Millisecond
+}
+
+// Service returns the deadline of a service call as a duration
+func (t Timeout) Service() time.Duration {
+	return time.Duration(t.ServiceMS) * time.Millisecond
+}
+
+// MySQL is the configuration of the database
+type MySQL struct {
+	URI                 string `config:"uri" env:"MYSQL_URI" secret:"true" validate:"required" usage:"dsn of the database"`
+	MaxOpenConnection   int    `config:"max_open_connection" env:"MYSQL_MAX_OPEN_CONNECTION" default:"100" validate:"min=1" usage:"max open connections"`
+	MaxIdleConnection   int    `config:"max_idle_connection" env:"MYSQL_MAX_IDLE_CONNECTION" default:"10" validate:"min=0" usage:"max idle connections"`
+	ConnectionLifetimeM int    `config:"connection_lifetime_m" env:"MYSQL_CONNECTION_LIFETIME_M" default:"5" validate:"min=0" usage:"connection lifetime in minutes"`
+}
+
+// ConnectionLifetime returns the connection lifetime as a duration
+func (m MySQL) ConnectionLifetime() time.Duration {
+	return time.Duration(m.ConnectionLifetimeM) * time.Minute
+}
+
+// Health is the configuration of the readiness checks
+type Health struct {
+	CheckTimeout   time.Duration `config:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" default:"2s" validate:"min=0" usage:"timeout of each readiness check"`
+	MigrationsPath string        `config:"migrations_path" env:"MIGRATIONS_PATH" default:"driver/mariadb/migrations" usage:"directory of the migrations checked by the readiness probe"`
+}
+
+// JWT is the configuration of the local tokens
+type JWT struct {
+	HMACSecret       string `config:"hmac_secret" env:"JWT_HMAC_SECRET" secret:"true" usage:"secret signing and verifying the tokens of local users"`
+	RSAPublicKeyFile string `config:"rsa_public_key_file" env:"JWT_RSA_PUBLIC_KEY_FILE" usage:"PEM encoded RSA public key verifying the tokens"`
+	JWKSFile         string `config:"jwks_file" env:"JWT_JWKS_FILE" usage:"JWKS file verifying the tokens"`
+	Issuer           string `config:"issuer" env:"JWT_ISSUER" usage:"expected iss claim"`
+	Audience         string `config:"audience" env:"JWT_AUDIENCE" usage:"expected aud claim"`
+}
+
+// OIDC is the configuration of the OpenID Connect provider
+type OIDC struct {
+	Issuer          string `config:"issuer" env:"OIDC_ISSUER" usage:"issuer of the provider, its tokens are accepted next to the local ones"`
+	DiscoveryURL    string `config:"discovery_url" env:"OIDC_DISCOVERY_URL" usage:"discovery document, defaults to <issuer>/.well-known/openid-configuration"`
+	JWKSURL         string `config:"jwks_url" env:"OIDC_JWKS_URL" usage:"JWKS of the provider, defaults to the one of the discovery document"`
+	Audience        string `config:"audience" env:"OIDC_AUDIENCE" usage:"expected aud claim"`
+	RolesClaim      string `config:"roles_claim" env:"OIDC_ROLES_CLAIM" default:"roles" usage:"claim holding the roles, dotted paths read nested claims"`
+	RoleMapping     string `config:"role_mapping" env:"OIDC_ROLE_MAPPING" usage:"comma separated <claim value>:<role> pairs"`
+	EmployeeIDClaim string `config:"employee_id_claim" env:"OIDC_EMPLOYEE_ID_CLAIM" default:"employee_id" usage:"claim holding the employee id"`
+	TenantIDClaim   string `config:"tenant_id_claim" env:"OIDC_TENANT_ID_CLAIM" default:"tenant_id" usage:"claim holding the tenant id"`
+}
+
+// Tenant is the configuration of the tenant of the callers
+type Tenant struct {
+	Default string `config:"default" env:"TENANT_DEFAULT" validate:"omitempty,max=50" usage:"tenant of the tokens without tenant claim, they are forbidden when it's empty"`
+}
+
+// RateLimit is the configuration of the rate limits
+type RateLimit struct {
+	Default      string `config:"default" env:"RATE_LIMIT" default:"100/1m" validate:"required" usage:"limit of each api key, user or client ip, written as <rate>/<period>[:<burst>]"`
+	AuthFailures string `config:"auth_failures" env:"RATE_LIMIT_AUTH_FAILURES" default:"10/1m" validate:"required" usage:"limit of the failed authentications and logins of each client ip"`
+	Routes       string `config:"routes" env:"RATE_LIMIT_ROUTES" usage:"comma separated per route limits, e.g. POST /departments=10/1m"`
+}
+
+// Idempotency is the configuration of the idempotency keys
+type Idempotency struct {
+	TTL            time.Duration `config:"ttl" env:"IDEMPOTENCY_KEY_TTL" default:"24h" validate:"min=1" usage:"window an idempotency key is replayed in"`
+	PendingTimeout time.Duration `config:"pending_timeout" env:"IDEMPOTENCY_PENDING_TIMEOUT" default:"1m" validate:"min=0" usage:"time after which the key of a request which never completed can be retried"`
+	SweepInterval  time.Duration `config:"sweep_interval" env:"IDEMPOTENCY_SWEEP_INTERVAL" default:"1h" validate:"min=1" usage:"interval of deleting the expired idempotency keys"`
+	MaxBodySizeMB  int           `config:"max_body_size_mb" env:"IDEMPOTENCY_MAX_BODY_SIZE_MB" default:"16" validate:"min=1" usage:"max size in megabytes of a request body with an idempotency key, it's read in memory"`
+}
+
+// MaxBodySize returns the max size of a request body with an idempotency key in bytes
+func (i Idempotency) MaxBodySize() int64 {
+	return int64(i.MaxBodySizeMB) << 20
+}
+
+// Outbox is the configuration of the dispatcher of the domain events
+type Outbox struct {
+	Publisher   string        `config:"publisher" env:"OUTBOX_PUBLISHER" default:"stdout" validate:"oneof=none stdout file" usage:"none, stdout or file, the events are published to the webhooks too"`
+	File        string        `config:"file" env:"OUTBOX_FILE" usage:"file the events are appended to by the file publisher"`
+	Interval    time.Duration `config:"interval" env:"OUTBOX_INTERVAL" default:"1s" validate:"min=1" usage:"interval of publishing the pending events"`
+	BatchSize   int           `config:"batch_size" env:"OUTBOX_BATCH_SIZE" default:"100" validate:"min=1" usage:"number of events fetched at a time"`
+	MaxAttempts int           `config:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS" default:"10" validate:"min=0" usage:"attempts after which an event is given up, zero retries it forever"`
+}
+
+// Webhook is the configuration of the sender of the webhook deliveries
+type Webhook struct {
+	Interval             time.Duration `config:"interval" env:"WEBHOOK_INTERVAL" default:"1s" validate:"min=1" usage:"interval of sending the due deliveries"`
+	BatchSize            int           `config:"batch_size" env:"WEBHOOK_BATCH_SIZE" default:"50" validate:"min=1" usage:"number of due deliveries sent at a time"`
+	Timeout              time.Duration `config:"timeout" env:"WEBHOOK_TIMEOUT" default:"10s" validate:"min=1" usage:"timeout of a delivery attempt"`
+	MaxAttempts          int           `config:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" default:"8" validate:"min=1" usage:"attempts after which a delivery is dead"`
+	Backoff              time.Duration `config:"backoff" env:"WEBHOOK_BACKOFF" default:"30s" validate:"min=1" usage:"delay after the first failed attempt, it doubles after every attempt"`
+	MaxBackoff           time.Duration `config:"max_backoff" env:"WEBHOOK_MAX_BACKOFF" default:"1h" validate:"min=1" usage:"maximum delay between two attempts"`
+	AllowPrivateNetworks bool          `config:"allow_private_networks" env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS" default:"false" usage:"allow the webhooks to loopback, link-local and private addresses"`
+}
+
+// Events is the configuration of the stream of the domain events
+type Events struct {
+	PollInterval time.Duration `config:"poll_interval" env:"EVENTS_POLL_INTERVAL" default:"1s" validate:"min=1" usage:"interval of reading the new events of a stream"`
+	Heartbeat    time.Duration `config:"heartbeat" env:"EVENTS_HEARTBEAT" default:"15s" validate:"min=1" usage:"interval of the comments keeping an idle stream open"`
+	CommitLag    time.Duration `config:"commit_lag" env:"EVENTS_COMMIT_LAG" default:"5s" validate:"min=0" usage:"time after which a written event is read, it must exceed the longest transaction writing events"`
+}
+
+// Import is the configuration of the CSV imports of departments and employees
+type Import struct {
+	BatchSize  int           `config:"batch_size" env:"IMPORT_BATCH_SIZE" default:"500" validate:"min=1" usage:"number of rows upserted in a transaction"`
+	MaxSizeMB  int           `config:"max_size_mb" env:"IMPORT_MAX_SIZE_MB" default:"10" validate:"min=1,max=16" usage:"max size in megabytes of a CSV sent to POST /imports"`
+	Interval   time.Duration `config:"interval" env:"IMPORT_INTERVAL" default:"5s" validate:"min=1" usage:"interval of running the pending imports"`
+	RunTimeout time.Duration `config:"run_timeout" env:"IMPORT_RUN_TIMEOUT" default:"30m" validate:"min=1" usage:"deadline of running an import, a running import claimed before it is claimed again"`
+}
+
+// MaxSize returns the max size of a CSV in bytes
+func (i Import) MaxSize() int64 {
+	return int64(i.MaxSizeMB) << 20
+}
+
+// validate checks the rules spanning several fields
+func (c Config) validate() (problems []string) {
+	if c.JWT.HMACSecret == "" && c.JWT.RSAPublicKeyFile == "" && c.JWT.JWKSFile == "" && c.OIDC.Issuer == "" {
+		problems = append(problems, "one of jwt.hmac_secret, jwt.rsa_public_key_file, jwt.jwks_file or oidc.issuer is required")
+	}
+
+	if _, err := ratelimit.ParseLimit(c.RateLimit.Default); c.RateLimit.Default != "" && err != nil {
+		problems = append(problems, fmt.Sprintf("rate_limit.default is invalid: %v", err))
+	}
+
+	if _, err := ratelimit.ParseLimit(c.RateLimit.AuthFailures); c.RateLimit.AuthFailures != "" && err != nil {
+		problems = append(problems, fmt.Sprintf("rate_limit.auth_failures is invalid: %v", err))
+	}
+
+	if c.Idempotency.MaxBodySizeMB < c.Import.MaxSizeMB {
+		problems = append(problems, "idempotency.max_body_size_mb must be at least import.max_size_mb")
+	}
+
+	if c.Events.CommitLag <= c.Timeout.Request() || c.Events.CommitLag <= c.Timeout.Service() {
+		problems = append(problems, "events.commit_lag must exceed timeout.request_ms and timeout.service_ms")
+	}
+
+	if _, err := time.LoadLocation(c.Server.Timezone); c.Server.Timezone != "" && err != nil {
+		problems = append(problems, fmt.Sprintf("server.timezone is invalid: %v", err))
+	}
+
+	if c.Tracing.Exporter == "file" && c.Tracing.File == "" {
+		problems = append(problems, "tracing.file is required by the file exporter")
+	}
+
+	if c.Outbox.Publisher == "file" && c.Outbox.File == "" {
+		problems = append(problems, "outbox.file is required by the file publisher")
+	}
+
+	return
+}
@@ -0,0 +1,183 @@
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/config"
+)
+
+// setEnv sets the environment variables, the returned function restores them
+func setEnv(t *testing.T, env map[string]string) (restore func()) {
+	old := map[string]*string{}
+	for k, v := range env {
+		if o, ok := os.LookupEnv(k); ok {
+			old[k] = &o
+		} else {
+			old[k] = nil
+		}
+		require.NoError(t, os.Setenv(k, v))
+	}
+
+	return func() {
+		for k, v := range old {
+			if v == nil {
+				os.Unsetenv(k)
+				continue
+			}
+			os.Setenv(k, *v)
+		}
+	}
+}
+
+// writeFile writes the config file into a temporary directory, the returned function removes it
+func writeFile(t *testing.T, name, content string) (path string, remove func()) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+
+	path = filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func newFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	config.BindFlags(flags)
+	require.NoError(t, flags.Parse(args))
+	return flags
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file, remove := writeFile(t, "config.yaml", `
+mysql:
+  uri: root:secret@tcp(localhost:3306)/employee
+  max_open_connection: 50
+  max_idle_connection: 5
+log:
+  level: debug
+jwt:
+  hmac_secret: file-secret
+`)
+	defer remove()
+
+	defer setEnv(t, map[string]string{
+		"MYSQL_MAX_IDLE_CONNECTION": "7",
+		"LOG_LEVEL":                 "warn",
+	})()
+	flags := newFlags(t, "--log-level", "error")
+
+	cfg, err := config.Load(file, flags)
+	require.NoError(t, err)
+
+	// default
+	require.Equal(t, 5, cfg.MySQL.ConnectionLifetimeM)
+	require.Equal(t, 10*time.Second, cfg.Server.ShutdownTimeout)
+	require.Equal(t, "text", cfg.Log.Format)
+	// file
+	require.Equal(t, "root:secret@tcp(localhost:3306)/employee", cfg.MySQL.URI)
+	require.Equal(t, 50, cfg.MySQL.MaxOpenConnection)
+	// env over file
+	require.Equal(t, 7, cfg.MySQL.MaxIdleConnection)
+	// flag over env
+	require.Equal(t, "error", cfg.Log.Level)
+}
+
+func TestLoadTOML(t *testing.T) {
+	file, remove := writeFile(t, "config.toml", `
+[mysql]
+uri = "root:secret@tcp(localhost:3306)/employee"
+
+[oidc]
+issuer = "https://idp.example.com"
+
+[server]
+shutdown_timeout = "30s"
+
+[tracing]
+sample_ratio = 0.25
+`)
+	defer remove()
+
+	cfg, err := config.Load(file, nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://idp.example.com", cfg.OIDC.Issuer)
+	require.Equal(t, 30*time.Second, cfg.Server.ShutdownTimeout)
+	require.Equal(t, 0.25, cfg.Tracing.SampleRatio)
+}
+
+func TestLoadProblems(t *testing.T) {
+	file, remove := writeFile(t, "config.yaml", `
+mysql:
+  max_open_connection: many
+log:
+  colour: red
+`)
+	defer remove()
+
+	defer setEnv(t, map[string]string{
+		"SHUTDOWN_TIMEOUT":  "soon",
+		"LOG_FORMAT":        "xml",
+		"EVENTS_COMMIT_LAG": "1s",
+		"TIMEZONE":          "Mars/Olympus",
+	})()
+	flags := newFlags(t, "--tracing-sample-ratio", "2", "--rate-limit-default", "fast")
+
+	_, err := config.Load(file, flags)
+	require.Error(t, err)
+
+	cfgErr, ok := err.(*config.Error)
+	require.True(t, ok)
+	require.ElementsMatch(t, []string{
+		"file " + file + ": log.colour: unknown key",
+		"file " + file + `: mysql.max_open_connection: invalid integer "many"`,
+		`env SHUTDOWN_TIMEOUT: invalid duration "soon"`,
+		"log.format failed on the 'oneof=json text' rule",
+		"tracing.sample_ratio failed on the 'max=1' rule",
+		"mysql.uri failed on the 'required' rule",
+		"one of jwt.hmac_secret, jwt.rsa_public_key_file, jwt.jwks_file or oidc.issuer is required",
+		`rate_limit.default is invalid: ratelimit: limit "fast" must be <rate>/<period>`,
+		"events.commit_lag must exceed timeout.request_ms and timeout.service_ms",
+		"server.timezone is invalid: unknown time zone Mars/Olympus",
+	}, cfgErr.Problems)
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	_, err := config.Load(filepath.Join(os.TempDir(), "missing.yaml"), nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read config file")
+
+	file, remove := writeFile(t, "config.json", "{}")
+	defer remove()
+
+	_, err = config.Load(file, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unsupported format ".json"`)
+}
+
+func TestPrint(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		"MYSQL_URI":       "root:secret@tcp(localhost:3306)/employee",
+		"JWT_HMAC_SECRET": "hmac-secret",
+	})()
+
+	cfg, err := config.Load("", nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Print(&buf))
+
+	out := buf.String()
+	require.NotContains(t, out, "secret@tcp")
+	require.NotContains(t, out, "hmac-secret")
+	require.Contains(t, out, "uri: '******'")
+	require.Contains(t, out, "hmac_secret: '******'")
+	require.Contains(t, out, "shutdown_timeout: 10s")
+	require.Contains(t, out, "max_open_connection: 100")
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Error lists every problem found while loading the config
+type Error struct {
+	Problems []string
+}
+
+// Error returns the problems, one per line
+func (e *Error) Error() string {
+	return "invalid config:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// field is a leaf field of the config
+type field struct {
+	key    string
+	env    string
+	def    string
+	secret bool
+	usage  string
+	value  reflect.Value
+}
+
+// flagName returns the name of the flag of the field, e.g. mysql-max-open-connection of mysql.max_open_connection
+func (f field) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(f.key)
+}
+
+// set parses the raw value into the field
+func (f field) set(raw string) (err error) {
+	switch f.value.Interface().(type) {
+	case string:
+		f.value.SetString(raw)
+	case time.Duration:
+		var d time.Duration
+		if d, err = time.ParseDuration(raw); err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		f.value.SetInt(int64(d))
+	case int:
+		var i int
+		if i, err = strconv.Atoi(raw); err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.value.SetInt(int64(i))
+	case float64:
+		var fl float64
+		if fl, err = strconv.ParseFloat(raw, 64); err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		f.value.SetFloat(fl)
+	case bool:
+		var b bool
+		if b, err = strconv.ParseBool(raw); err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.value.SetBool(b)
+	default:
+		err = fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+
+	return
+}
+
+// fields returns the leaf fields of the config in order of their declaration
+func fields(cfg *Config) (fs []field) {
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := prefix + sf.Tag.Get("config")
+
+			if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+				walk(v.Field(i), key+".")
+				continue
+			}
+
+			fs = append(fs, field{
+				key:    key,
+				env:    sf.Tag.Get("env"),
+				def:    sf.Tag.Get("default"),
+				secret: sf.Tag.Get("secret") == "true",
+				usage:  sf.Tag.Get("usage"),
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+
+	return
+}
+
+// BindFlags registers a flag for every field of the config, e.g. --mysql-uri
+func BindFlags(flags *pflag.FlagSet) {
+	for _, f := range fields(&Config{}) {
+		usage := f.usage
+		if f.env != "" {
+			usage = fmt.Sprintf("%s (env %s)", usage, f.env)
+		}
+		flags.String(f.flagName(), f.def, usage)
+	}
+}
+
+// Load loads the config from the defaults, the file, the environment and the changed flags in that precedence.
+// The file is optional, its format is read from the extension: .yaml, .yml or .toml.
+// Every problem is returned at once as *Error, the config holds the valid values even when there are problems
+func Load(file string, flags *pflag.FlagSet) (cfg Config, err error) {
+	var problems []string
+	fs := fields(&cfg)
+
+	for _, f := range fs {
+		if f.def == "" {
+			continue
+		}
+		if er := f.set(f.def); er != nil {
+			problems = append(problems, fmt.Sprintf("default of %s: %v", f.key, er))
+		}
+	}
+
+	if file != "" {
+		problems = append(problems, loadFile(file, fs)...)
+	}
+
+	for _, f := range fs {
+		raw, ok := os.LookupEnv(f.env)
+		if f.env == "" || !ok || raw == "" {
+			continue
+		}
+		if er := f.set(raw); er != nil {
+			problems = append(problems, fmt.Sprintf("env %s: %v", f.env, er))
+		}
+	}
+
+	if flags != nil {
+		for _, f := range fs {
+			fl := flags.Lookup(f.flagName())
+			if fl == nil || !fl.Changed {
+				continue
+			}
+			if er := f.set(fl.Value.String()); er != nil {
+				problems = append(problems, fmt.Sprintf("flag --%s: %v", fl.Name, er))
+			}
+		}
+	}
+
+	problems = append(problems, validate(cfg)...)
+	if len(problems) > 0 {
+		err = &Error{Problems: problems}
+	}
+
+	return
+}
+
+// loadFile sets the fields from the file, the unknown keys are reported as problems
+func loadFile(file string, fs []field) (problems []string) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return []string{fmt.Sprintf("can't read config file: %v", err)}
+	}
+
+	values := map[string]interface{}{}
+	switch ext := strings.ToLower(filepath.Ext(file)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &values)
+	case ".toml":
+		_, err = toml.Decode(string(raw), &values)
+	default:
+		err = fmt.Errorf("unsupported format %q", ext)
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("can't parse config file %s: %v", file, err)}
+	}
+
+	flat := map[string]interface{}{}
+	flatten(values, "", flat)
+
+	byKey := make(map[string]field, len(fs))
+	for _, f := range fs {
+		byKey[f.key] = f
+	}
+
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		f, ok := byKey[k]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("file %s: %s: unknown key", file, k))
+			continue
+		}
+		if er := f.set(fmt.Sprint(flat[k])); er != nil {
+			problems = append(problems, fmt.Sprintf("file %s: %s: %v", file, k, er))
+		}
+	}
+
+	return
+}
+
+// flatten joins the nested keys of the values with dots
+func flatten(values map[string]interface{}, prefix string, flat map[string]interface{}) {
+	for k, v := range values {
+		if nested, ok := v.(map[string]interface{}); ok {
+			flatten(nested, prefix+k+".", flat)
+			continue
+		}
+		flat[prefix+k] = v
+	}
+}
+
+// validate checks the fields with their validate tags and the rules spanning several fields
+func validate(cfg Config) (problems []string) {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(sf reflect.StructField) string {
+		return sf.Tag.Get("config")
+	})
+
+	if err := v.Struct(cfg); err != nil {
+		for _, e := range err.(validator.ValidationErrors) {
+			// the namespace starts with the name of the root struct
+			key := e.Namespace()[strings.Index(e.Namespace(), ".")+1:]
+
+			rule := e.Tag()
+			if e.Param() != "" {
+				rule += "=" + e.Param()
+			}
+			problems = append(problems, fmt.Sprintf("%s failed on the '%s' rule", key, rule))
+		}
+	}
+
+	return append(problems, cfg.validate()...)
+}
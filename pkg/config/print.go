@@ -0,0 +1,44 @@
+package config
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces the value of a secret when the config is printed
+const Redacted = "******"
+
+// Print writes the config as YAML, the secrets which are set are redacted
+func (c Config) Print(w io.Writer) (err error) {
+	out := map[string]interface{}{}
+	for _, f := range fields(&c) {
+		value := f.value.Interface()
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		if f.secret && f.value.String() != "" {
+			value = Redacted
+		}
+
+		section := out
+		parts := strings.Split(f.key, ".")
+		for _, p := range parts[:len(parts)-1] {
+			if _, ok := section[p]; !ok {
+				section[p] = map[string]interface{}{}
+			}
+			section = section[p].(map[string]interface{})
+		}
+		section[parts[len(parts)-1]] = value
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err = enc.Encode(out); err != nil {
+		return
+	}
+
+	return enc.Close()
+}
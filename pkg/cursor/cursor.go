@@ -3,6 +3,9 @@ package cursor
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
 )
 
 // Cursor represent cursor model
@@ -51,3 +54,27 @@ func DecodeBase64(value string) (res string, err error) {
 	res = string(cursorByte)
 	return
 }
+
+// LinkHeader builds RFC 5988 Link header value pointing to the next and previous page of the given url
+func LinkHeader(u url.URL, nextCursor, prevCursor string) string {
+	links := make([]string, 0)
+	if nextCursor != "" {
+		links = append(links, buildLink(u, "cursor", nextCursor, "next"))
+	}
+
+	if prevCursor != "" {
+		links = append(links, buildLink(u, "before", prevCursor, "prev"))
+	}
+
+	return strings.Join(links, ", ")
+}
+
+func buildLink(u url.URL, param, value, rel string) string {
+	query := u.Query()
+	query.Del("cursor")
+	query.Del("before")
+	query.Set(param, value)
+	u.RawQuery = query.Encode()
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
@@ -1,6 +1,7 @@
 package cursor_test
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -48,3 +49,25 @@ func TestDecodeBase64(t *testing.T) {
 	require.Equal(t, want, get)
 	require.NoError(t, err)
 }
+
+func TestLinkHeader(t *testing.T) {
+	u, err := url.Parse("/departments?keyword=marketing&num=2&cursor=MQ==")
+	require.NoError(t, err)
+
+	t.Run("with next and prev cursor", func(t *testing.T) {
+		want := `</departments?cursor=Mw%3D%3D&keyword=marketing&num=2>; rel="next", </departments?before=Mg%3D%3D&keyword=marketing&num=2>; rel="prev"`
+		get := cursor.LinkHeader(*u, "Mw==", "Mg==")
+		require.Equal(t, want, get)
+	})
+
+	t.Run("with next cursor only", func(t *testing.T) {
+		want := `</departments?cursor=Mw%3D%3D&keyword=marketing&num=2>; rel="next"`
+		get := cursor.LinkHeader(*u, "Mw==", "")
+		require.Equal(t, want, get)
+	})
+
+	t.Run("without cursor", func(t *testing.T) {
+		get := cursor.LinkHeader(*u, "", "")
+		require.Empty(t, get)
+	})
+}
@@ -0,0 +1,40 @@
+// Package deadline bounds the contexts which aren't bounded by their caller
+package deadline
+
+import (
+	"context"
+	"time"
+)
+
+// WithDefault returns a context cancelled after the timeout unless ctx already has a deadline,
+// so a deadline set by the caller, e.g. the per route deadline of a request, is never extended nor shortened.
+// A non-positive timeout leaves ctx unbounded
+func WithDefault(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Timeouts are the deadlines of the calls of a service which aren't bounded by their caller
+type Timeouts struct {
+	// Default bounds the methods without their own timeout
+	Default time.Duration
+	// Methods are the timeouts by method name, e.g. Fetch
+	Methods map[string]time.Duration
+}
+
+// Of returns the timeout of the method
+func (t Timeouts) Of(method string) time.Duration {
+	if timeout, ok := t.Methods[method]; ok {
+		return timeout
+	}
+
+	return t.Default
+}
+
+// Bound returns ctx bounded by the timeout of the method, see WithDefault
+func (t Timeouts) Bound(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	return WithDefault(ctx, t.Of(method))
+}
@@ -0,0 +1,53 @@
+package deadline_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/deadline"
+)
+
+func TestWithDefault(t *testing.T) {
+	ctx, cancel := deadline.WithDefault(context.Background(), time.Second)
+	defer cancel()
+	dl, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Second), dl, 100*time.Millisecond)
+
+	bounded, cancelBounded := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelBounded()
+	expected, _ := bounded.Deadline()
+
+	ctx, cancel = deadline.WithDefault(bounded, time.Second)
+	defer cancel()
+	dl, _ = ctx.Deadline()
+	require.Equal(t, expected, dl)
+
+	ctx, cancel = deadline.WithDefault(context.Background(), 0)
+	_, ok = ctx.Deadline()
+	require.False(t, ok)
+	cancel()
+	require.Error(t, ctx.Err())
+}
+
+func TestTimeouts(t *testing.T) {
+	timeouts := deadline.Timeouts{Default: time.Second, Methods: map[string]time.Duration{"Fetch": time.Minute, "Delete": 0}}
+
+	require.Equal(t, time.Minute, timeouts.Of("Fetch"))
+	require.Equal(t, time.Second, timeouts.Of("Get"))
+	require.Equal(t, time.Duration(0), timeouts.Of("Delete"))
+
+	ctx, cancel := timeouts.Bound(context.Background(), "Fetch")
+	defer cancel()
+	dl, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Minute), dl, 100*time.Millisecond)
+
+	ctx, cancel = timeouts.Bound(context.Background(), "Delete")
+	defer cancel()
+	_, ok = ctx.Deadline()
+	require.False(t, ok)
+}
@@ -0,0 +1,256 @@
+package fieldset
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Fieldset represent the selected json fields in the requested order, nested fields are separated by dot e.g. department.name
+type Fieldset []Field
+
+// Field represent a selected json field, a field without nested fields selects all of them
+type Field struct {
+	Name   string
+	Nested Fieldset
+}
+
+// Parse parses comma-separated fields and validates them against json attributes of the model.
+// A field selects all of its nested fields, so department,department.name selects the whole department
+func Parse(raw string, model interface{}) (fs Fieldset, err error) {
+	if strings.TrimSpace(raw) == "" {
+		return
+	}
+
+	fs = Fieldset{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if !isValidPath(reflect.TypeOf(model), strings.Split(field, ".")) {
+			err = fmt.Errorf("unknown field %s", field)
+			return
+		}
+
+		fs = fs.add(strings.Split(field, "."))
+	}
+
+	return
+}
+
+// add selects the path, a field keeps the position of its first selection
+func (fs Fieldset) add(names []string) Fieldset {
+	i := fs.index(names[0])
+	if i < 0 {
+		f := Field{Name: names[0]}
+		if len(names) > 1 {
+			f.Nested = Fieldset(nil).add(names[1:])
+		}
+		return append(fs, f)
+	}
+
+	switch {
+	case len(names) == 1:
+		// a bare parent selects all of its nested fields
+		fs[i].Nested = nil
+	case len(fs[i].Nested) != 0:
+		fs[i].Nested = fs[i].Nested.add(names[1:])
+	}
+
+	return fs
+}
+
+func (fs Fieldset) index(name string) int {
+	for i, f := range fs {
+		if f.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Lookup returns the selected field of the name
+func (fs Fieldset) Lookup(name string) (f Field, ok bool) {
+	i := fs.index(name)
+	if i < 0 {
+		return
+	}
+
+	return fs[i], true
+}
+
+// Has reports whether the path is selected, a nil fieldset selects every field
+func (fs Fieldset) Has(path string) bool {
+	if fs == nil {
+		return true
+	}
+
+	current := fs
+	for _, name := range strings.Split(path, ".") {
+		f, ok := current.Lookup(name)
+		if !ok {
+			return false
+		}
+
+		if len(f.Nested) == 0 {
+			return true
+		}
+		current = f.Nested
+	}
+
+	return true
+}
+
+// Apply projects the value to the selected fields, a nil fieldset returns the value as is.
+// The projection is a struct of the selected fields in the requested order, so every representation
+// keeps that order and an empty list still knows its fields
+func (fs Fieldset) Apply(value interface{}) (res interface{}, err error) {
+	if fs == nil {
+		res = value
+		return
+	}
+
+	v := reflect.ValueOf(value)
+	t, err := fs.projectType(v.Type())
+	if err != nil {
+		return
+	}
+
+	projected := reflect.New(t).Elem()
+	fs.project(projected, v)
+
+	res = projected.Interface()
+	return
+}
+
+func (fs Fieldset) projectType(t reflect.Type) (projected reflect.Type, err error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		if projected, err = fs.projectType(t.Elem()); err == nil {
+			projected = reflect.PtrTo(projected)
+		}
+		return
+	case reflect.Slice:
+		if projected, err = fs.projectType(t.Elem()); err == nil {
+			projected = reflect.SliceOf(projected)
+		}
+		return
+	case reflect.Array:
+		if projected, err = fs.projectType(t.Elem()); err == nil {
+			projected = reflect.ArrayOf(t.Len(), projected)
+		}
+		return
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		err = fmt.Errorf("can't select fields of %s", t)
+		return
+	}
+
+	fields := make([]reflect.StructField, len(fs))
+	for i, f := range fs {
+		sf, ok := fieldOf(t, f.Name)
+		if !ok {
+			err = fmt.Errorf("unknown field %s", f.Name)
+			return
+		}
+
+		if len(f.Nested) != 0 {
+			if sf.Type, err = f.Nested.projectType(sf.Type); err != nil {
+				return
+			}
+		}
+
+		fields[i] = reflect.StructField{Name: sf.Name, Type: sf.Type, Tag: sf.Tag}
+	}
+
+	projected = reflect.StructOf(fields)
+	return
+}
+
+// project copies the selected fields of the value to the projected value
+func (fs Fieldset) project(projected, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			projected.Set(reflect.New(projected.Type().Elem()))
+			fs.project(projected.Elem(), value.Elem())
+		}
+		return
+	case reflect.Slice:
+		if !value.IsNil() {
+			projected.Set(reflect.MakeSlice(projected.Type(), value.Len(), value.Len()))
+			for i := 0; i < value.Len(); i++ {
+				fs.project(projected.Index(i), value.Index(i))
+			}
+		}
+		return
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			fs.project(projected.Index(i), value.Index(i))
+		}
+		return
+	}
+
+	for i, f := range fs {
+		sf, _ := fieldOf(value.Type(), f.Name)
+		if len(f.Nested) == 0 {
+			projected.Field(i).Set(value.FieldByIndex(sf.Index))
+			continue
+		}
+
+		f.Nested.project(projected.Field(i), value.FieldByIndex(sf.Index))
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func isValidPath(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	if len(path) == 0 {
+		return true
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+
+	f, ok := fieldOf(t, path[0])
+	if !ok {
+		return false
+	}
+
+	return isValidPath(f.Type, path[1:])
+}
+
+// fieldOf returns the exported field of the struct encoded with the json name
+func fieldOf(t reflect.Type, name string) (f reflect.StructField, ok bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f = t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+		if jsonName == "-" {
+			continue
+		}
+
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+
+		if jsonName == name {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,122 @@
+package fieldset_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/fieldset"
+)
+
+type department struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type employee struct {
+	ID          string     `json:"id"`
+	FirstName   string     `json:"first_name"`
+	Department  department `json:"department"`
+	CreatedTime time.Time  `json:"created_time"`
+}
+
+func TestParse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fs, err := fieldset.Parse("first_name, id,department.name", employee{})
+		require.NoError(t, err)
+		require.Equal(t, fieldset.Fieldset{
+			{Name: "first_name"},
+			{Name: "id"},
+			{Name: "department", Nested: fieldset.Fieldset{{Name: "name"}}},
+		}, fs)
+	})
+
+	t.Run("nested fields are grouped at their first selection", func(t *testing.T) {
+		fs, err := fieldset.Parse("department.name,id,department.id", employee{})
+		require.NoError(t, err)
+		require.Equal(t, fieldset.Fieldset{
+			{Name: "department", Nested: fieldset.Fieldset{{Name: "name"}, {Name: "id"}}},
+			{Name: "id"},
+		}, fs)
+	})
+
+	t.Run("parent selects every nested field", func(t *testing.T) {
+		expected := fieldset.Fieldset{{Name: "id"}, {Name: "department"}}
+		for _, raw := range []string{"id,department,department.name", "id,department.name,department"} {
+			fs, err := fieldset.Parse(raw, employee{})
+			require.NoError(t, err)
+			require.Equal(t, expected, fs, raw)
+		}
+	})
+
+	t.Run("empty fields", func(t *testing.T) {
+		fs, err := fieldset.Parse("", employee{})
+		require.NoError(t, err)
+		require.Nil(t, fs)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := fieldset.Parse("id,password", employee{})
+		require.EqualError(t, err, "unknown field password")
+	})
+
+	t.Run("unknown nested field", func(t *testing.T) {
+		_, err := fieldset.Parse("created_time.year", []employee{})
+		require.EqualError(t, err, "unknown field created_time.year")
+	})
+}
+
+func TestHas(t *testing.T) {
+	fs, err := fieldset.Parse("id,department.name", employee{})
+	require.NoError(t, err)
+
+	require.True(t, fs.Has("id"))
+	require.True(t, fs.Has("department"))
+	require.True(t, fs.Has("department.name"))
+	require.False(t, fs.Has("department.id"))
+	require.False(t, fs.Has("first_name"))
+
+	var all fieldset.Fieldset
+	require.True(t, all.Has("first_name"))
+}
+
+func TestApply(t *testing.T) {
+	employees := []employee{
+		{ID: "1", FirstName: "Emilia", Department: department{ID: "10", Name: "Marketing"}},
+		{ID: "2", FirstName: "Casey", Department: department{ID: "20", Name: "Human Resources"}},
+	}
+
+	fs, err := fieldset.Parse("department.name,id", employees)
+	require.NoError(t, err)
+
+	res, err := fs.Apply(employees)
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(res)
+	require.NoError(t, err)
+	require.Equal(t, `[{"department":{"name":"Marketing"},"id":"1"},{"department":{"name":"Human Resources"},"id":"2"}]`, string(raw))
+
+	res, err = fs.Apply(&employees[0])
+	require.NoError(t, err)
+
+	raw, err = json.Marshal(res)
+	require.NoError(t, err)
+	require.Equal(t, `{"department":{"name":"Marketing"},"id":"1"}`, string(raw))
+
+	res, err = fs.Apply([]employee{})
+	require.NoError(t, err)
+
+	elem := reflect.TypeOf(res).Elem()
+	require.Equal(t, 0, reflect.ValueOf(res).Len())
+	require.Equal(t, 2, elem.NumField())
+	require.Equal(t, "department", elem.Field(0).Tag.Get("json"))
+	require.Equal(t, "id", elem.Field(1).Tag.Get("json"))
+
+	var all fieldset.Fieldset
+	res, err = all.Apply(employees[0])
+	require.NoError(t, err)
+	require.Equal(t, employees[0], res)
+}
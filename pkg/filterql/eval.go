@@ -0,0 +1,168 @@
+package filterql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Record is a flattened representation of a resource used to evaluate an expression without SQL
+type Record map[string]interface{}
+
+// Match evaluates the expression against the record, used by non SQL backends
+func Match(expr Expr, record Record) (matched bool, err error) {
+	switch e := expr.(type) {
+	case And:
+		if matched, err = Match(e.Left, record); err != nil || !matched {
+			return
+		}
+		return Match(e.Right, record)
+	case Or:
+		if matched, err = Match(e.Left, record); err != nil || matched {
+			return
+		}
+		return Match(e.Right, record)
+	case Not:
+		matched, err = Match(e.Expr, record)
+		matched = !matched
+		return
+	case Comparison:
+		return matchComparison(e, record)
+	}
+
+	err = fmt.Errorf("filterql: unsupported expression %T", expr)
+	return
+}
+
+func matchComparison(c Comparison, record Record) (matched bool, err error) {
+	actual, ok := record[c.Field]
+	if !ok {
+		err = fmt.Errorf("filterql: field %s is not found in record", c.Field)
+		return
+	}
+
+	if len(c.Values) == 0 {
+		err = fmt.Errorf("filterql: missing value of field %s", c.Field)
+		return
+	}
+
+	switch c.Operator {
+	case In:
+		for _, v := range c.Values {
+			cmp, er := compare(actual, v)
+			if er != nil {
+				err = er
+				return
+			}
+			if cmp == 0 {
+				matched = true
+				return
+			}
+		}
+		return
+	case Like:
+		s, ok := actual.(string)
+		pattern, okPattern := c.Values[0].(string)
+		if !ok || !okPattern {
+			err = fmt.Errorf("filterql: like is only supported on string field %s", c.Field)
+			return
+		}
+		matched = likeToRegexp(pattern).MatchString(s)
+		return
+	}
+
+	cmp, err := compare(actual, c.Values[0])
+	if err != nil {
+		return
+	}
+
+	switch c.Operator {
+	case Eq:
+		matched = cmp == 0
+	case Ne:
+		matched = cmp != 0
+	case Gt:
+		matched = cmp > 0
+	case Ge:
+		matched = cmp >= 0
+	case Lt:
+		matched = cmp < 0
+	case Le:
+		matched = cmp <= 0
+	default:
+		err = fmt.Errorf("filterql: unsupported operator %s", c.Operator)
+	}
+
+	return
+}
+
+func compare(actual, expected interface{}) (cmp int, err error) {
+	switch a := actual.(type) {
+	case string:
+		e, ok := expected.(string)
+		if !ok {
+			break
+		}
+		cmp = strings.Compare(a, e)
+		return
+	case time.Time:
+		e, ok := expected.(time.Time)
+		if !ok {
+			break
+		}
+		switch {
+		case a.Before(e):
+			cmp = -1
+		case a.After(e):
+			cmp = 1
+		}
+		return
+	case bool:
+		e, ok := expected.(bool)
+		if !ok {
+			break
+		}
+		if a != e {
+			cmp = 1
+		}
+		return
+	case int:
+		return compare(float64(a), expected)
+	case int64:
+		return compare(float64(a), expected)
+	case float64:
+		e, ok := expected.(float64)
+		if !ok {
+			break
+		}
+		switch {
+		case a < e:
+			cmp = -1
+		case a > e:
+			cmp = 1
+		}
+		return
+	}
+
+	err = fmt.Errorf("filterql: can't compare %T with %T", actual, expected)
+	return
+}
+
+func likeToRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+
+	return regexp.MustCompile(sb.String())
+}
@@ -0,0 +1,112 @@
+// Package filterql implements a small filter expression language for list endpoints, e.g.
+//
+//	title eq "Engineer" and dept_id in ("a","b") and created_time gt 2025-01-01
+//
+// Supported operators are eq, ne, gt, ge, lt, le, in and like, combined with and, or, not and parentheses.
+package filterql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxLength is the maximum length of a filter expression
+const MaxLength = 1024
+
+// Type represent the type of a filterable field
+type Type int
+
+const (
+	// String is a text field
+	String Type = iota
+	// Number is a numeric field
+	Number
+	// Time is a date or date time field
+	Time
+	// Bool is a boolean field
+	Bool
+)
+
+// Fields is a whitelist of filterable field name with its type
+type Fields map[string]Type
+
+// Operator represent a comparison operator
+type Operator string
+
+// List of supported comparison operators
+const (
+	Eq   Operator = "eq"
+	Ne   Operator = "ne"
+	Gt   Operator = "gt"
+	Ge   Operator = "ge"
+	Lt   Operator = "lt"
+	Le   Operator = "le"
+	In   Operator = "in"
+	Like Operator = "like"
+)
+
+var operators = map[string]Operator{
+	string(Eq):   Eq,
+	string(Ne):   Ne,
+	string(Gt):   Gt,
+	string(Ge):   Ge,
+	string(Lt):   Lt,
+	string(Le):   Le,
+	string(In):   In,
+	string(Like): Like,
+}
+
+// Expr represent a node of filter expression tree
+type Expr interface {
+	String() string
+}
+
+// And represent a conjunction of two expressions
+type And struct {
+	Left  Expr
+	Right Expr
+}
+
+func (e And) String() string {
+	return fmt.Sprintf("(%s and %s)", e.Left, e.Right)
+}
+
+// Or represent a disjunction of two expressions
+type Or struct {
+	Left  Expr
+	Right Expr
+}
+
+func (e Or) String() string {
+	return fmt.Sprintf("(%s or %s)", e.Left, e.Right)
+}
+
+// Not represent a negation of an expression
+type Not struct {
+	Expr Expr
+}
+
+func (e Not) String() string {
+	return fmt.Sprintf("not %s", e.Expr)
+}
+
+// Comparison represent a comparison between a field and values.
+// Values are already converted to the field type: string, float64, time.Time or bool
+type Comparison struct {
+	Field    string
+	Operator Operator
+	Values   []interface{}
+}
+
+func (e Comparison) String() string {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = fmt.Sprintf("%v", v)
+	}
+
+	if e.Operator == In {
+		return fmt.Sprintf("%s in (%s)", e.Field, strings.Join(values, ","))
+	}
+
+	return fmt.Sprintf("%s %s %s", e.Field, e.Operator, strings.Join(values, ","))
+}
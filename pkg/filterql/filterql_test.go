@@ -0,0 +1,147 @@
+package filterql_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/filterql"
+)
+
+var fields = filterql.Fields{
+	"title":        filterql.String,
+	"dept_id":      filterql.String,
+	"created_time": filterql.Time,
+	"age":          filterql.Number,
+	"active":       filterql.Bool,
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input       string
+		expected    string
+		expectedErr string
+	}{
+		"success with comparison": {
+			input:    `title eq "Engineer"`,
+			expected: "title eq Engineer",
+		},
+		"success with and, or and in": {
+			input:    `title eq "Engineer" and dept_id in ("a","b") or age ge 30`,
+			expected: "((title eq Engineer and dept_id in (a,b)) or age ge 30)",
+		},
+		"success with not and parentheses": {
+			input:    `not (title like "%Dev%" or active eq true)`,
+			expected: "not (title like %Dev% or active eq true)",
+		},
+		"success with time value": {
+			input:    `created_time gt 2025-01-01`,
+			expected: "created_time gt 2025-01-01 00:00:00 +0000 UTC",
+		},
+		"unknown field": {
+			input:       `password eq "secret"`,
+			expectedErr: `invalid filter at position 0: field "password" is not filterable`,
+		},
+		"unknown operator": {
+			input:       `title is "Engineer"`,
+			expectedErr: `invalid filter at position 6: unknown operator "is"`,
+		},
+		"operator not allowed": {
+			input:       `active gt true`,
+			expectedErr: `invalid filter at position 7: operator "gt" is not allowed on field "active"`,
+		},
+		"invalid time value": {
+			input:       `created_time gt yesterday`,
+			expectedErr: `invalid filter at position 16: invalid value "yesterday"`,
+		},
+		"unterminated string": {
+			input:       `title eq "Engineer`,
+			expectedErr: `invalid filter at position 9: unterminated string`,
+		},
+		"missing closing parenthesis": {
+			input:       `(title eq "Engineer"`,
+			expectedErr: `invalid filter at position 20: expected )`,
+		},
+		"trailing token": {
+			input:       `title eq "Engineer" "Manager"`,
+			expectedErr: `invalid filter at position 20: unexpected "Manager"`,
+		},
+		"too long expression": {
+			input:       strings.Repeat("a", filterql.MaxLength+1),
+			expectedErr: `invalid filter at position 1024: expression is longer than 1024 characters`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, err := filterql.Parse(tc.input, fields)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, expr.String())
+		})
+	}
+}
+
+func TestToSqlizer(t *testing.T) {
+	expr, err := filterql.Parse(`title eq "Engineer" and (dept_id in ("a","b") or not created_time lt 2025-01-01)`, fields)
+	require.NoError(t, err)
+
+	pred, err := filterql.ToSqlizer(expr, map[string]string{"dept_id": "employees.dept_id"})
+	require.NoError(t, err)
+
+	query, args, err := sq.Select("id").From("employees").Where(pred).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM employees WHERE (title = ? AND (employees.dept_id IN (?,?) OR NOT (created_time < ?)))", query)
+	require.Equal(t, []interface{}{"Engineer", "a", "b", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}, args)
+}
+
+func TestMatch(t *testing.T) {
+	record := filterql.Record{
+		"title":        "Senior Engineer",
+		"dept_id":      "a",
+		"created_time": time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		"age":          31,
+		"active":       true,
+	}
+
+	tests := map[string]struct {
+		input    string
+		expected bool
+	}{
+		"match eq":        {input: `dept_id eq "a"`, expected: true},
+		"not match ne":    {input: `dept_id ne "a"`, expected: false},
+		"match in":        {input: `dept_id in ("b","a")`, expected: true},
+		"match like":      {input: `title like "%engineer"`, expected: true},
+		"match time":      {input: `created_time gt 2025-01-01`, expected: true},
+		"match number":    {input: `age le 31 and active eq true`, expected: true},
+		"not match or":    {input: `age lt 30 or dept_id eq "b"`, expected: false},
+		"match with not":  {input: `not (age lt 30)`, expected: true},
+		"not match range": {input: `created_time ge 2025-01-01 and created_time lt 2025-02-01`, expected: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, err := filterql.Parse(tc.input, fields)
+			require.NoError(t, err)
+
+			matched, err := filterql.Match(expr, record)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, matched)
+		})
+	}
+
+	t.Run("missing field in record", func(t *testing.T) {
+		expr, err := filterql.Parse(`title eq "Engineer"`, fields)
+		require.NoError(t, err)
+
+		_, err = filterql.Match(expr, filterql.Record{})
+		require.EqualError(t, err, "filterql: field title is not found in record")
+	})
+}
@@ -0,0 +1,301 @@
+package filterql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// SyntaxError is returned when a filter expression is not valid
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses the filter expression and validates it against the given fields
+func Parse(input string, fields Fields) (expr Expr, err error) {
+	if len(input) > MaxLength {
+		err = SyntaxError{Pos: MaxLength, Msg: fmt.Sprintf("expression is longer than %d characters", MaxLength)}
+		return
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	expr, err = p.parseOr()
+	if err != nil {
+		return
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		err = SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.value)}
+		return
+	}
+
+	return
+}
+
+func tokenize(input string) (tokens []token, err error) {
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ",", pos: i})
+			i++
+		case r == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				err = SyntaxError{Pos: start, Msg: "unterminated string"}
+				return
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: start})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`(),"`, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:i]), pos: start})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	fields Fields
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && strings.EqualFold(t.value, keyword)
+}
+
+func (p *parser) parseOr() (expr Expr, err error) {
+	expr, err = p.parseAnd()
+	if err != nil {
+		return
+	}
+
+	for p.isKeyword("or") {
+		p.next()
+		right, er := p.parseAnd()
+		if er != nil {
+			err = er
+			return
+		}
+		expr = Or{Left: expr, Right: right}
+	}
+
+	return
+}
+
+func (p *parser) parseAnd() (expr Expr, err error) {
+	expr, err = p.parseUnary()
+	if err != nil {
+		return
+	}
+
+	for p.isKeyword("and") {
+		p.next()
+		right, er := p.parseUnary()
+		if er != nil {
+			err = er
+			return
+		}
+		expr = And{Left: expr, Right: right}
+	}
+
+	return
+}
+
+func (p *parser) parseUnary() (expr Expr, err error) {
+	if p.isKeyword("not") {
+		p.next()
+		inner, er := p.parseUnary()
+		if er != nil {
+			err = er
+			return
+		}
+		expr = Not{Expr: inner}
+		return
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		expr, err = p.parseOr()
+		if err != nil {
+			return
+		}
+
+		if t := p.next(); t.kind != tokenRParen {
+			err = SyntaxError{Pos: t.pos, Msg: "expected )"}
+			return
+		}
+		return
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr Expr, err error) {
+	fieldToken := p.next()
+	if fieldToken.kind != tokenIdent {
+		err = SyntaxError{Pos: fieldToken.pos, Msg: "expected field name"}
+		return
+	}
+
+	fieldType, ok := p.fields[fieldToken.value]
+	if !ok {
+		err = SyntaxError{Pos: fieldToken.pos, Msg: fmt.Sprintf("field %q is not filterable", fieldToken.value)}
+		return
+	}
+
+	opToken := p.next()
+	op, ok := operators[strings.ToLower(opToken.value)]
+	if opToken.kind != tokenIdent || !ok {
+		err = SyntaxError{Pos: opToken.pos, Msg: fmt.Sprintf("unknown operator %q", opToken.value)}
+		return
+	}
+
+	if !operatorAllowed(op, fieldType) {
+		err = SyntaxError{Pos: opToken.pos, Msg: fmt.Sprintf("operator %q is not allowed on field %q", op, fieldToken.value)}
+		return
+	}
+
+	comparison := Comparison{Field: fieldToken.value, Operator: op}
+
+	if op != In {
+		value, er := p.parseValue(fieldType)
+		if er != nil {
+			err = er
+			return
+		}
+		comparison.Values = []interface{}{value}
+		expr = comparison
+		return
+	}
+
+	if t := p.next(); t.kind != tokenLParen {
+		err = SyntaxError{Pos: t.pos, Msg: "expected ( after in"}
+		return
+	}
+
+	for {
+		value, er := p.parseValue(fieldType)
+		if er != nil {
+			err = er
+			return
+		}
+		comparison.Values = append(comparison.Values, value)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			break
+		}
+
+		if t.kind != tokenComma {
+			err = SyntaxError{Pos: t.pos, Msg: "expected , or )"}
+			return
+		}
+	}
+
+	expr = comparison
+	return
+}
+
+func (p *parser) parseValue(fieldType Type) (value interface{}, err error) {
+	t := p.next()
+	if t.kind != tokenString && t.kind != tokenIdent {
+		err = SyntaxError{Pos: t.pos, Msg: "expected value"}
+		return
+	}
+
+	switch fieldType {
+	case Number:
+		value, err = strconv.ParseFloat(t.value, 64)
+	case Time:
+		value, err = ntime.ParseDateTime(t.value)
+	case Bool:
+		value, err = strconv.ParseBool(t.value)
+	default:
+		value = t.value
+	}
+
+	if err != nil {
+		err = SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid value %q", t.value)}
+		return
+	}
+
+	return
+}
+
+func operatorAllowed(op Operator, fieldType Type) bool {
+	switch op {
+	case Like:
+		return fieldType == String
+	case Gt, Ge, Lt, Le:
+		return fieldType != Bool
+	}
+
+	return true
+}
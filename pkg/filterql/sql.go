@@ -0,0 +1,101 @@
+package filterql
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type not struct {
+	pred sq.Sqlizer
+}
+
+func (n not) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = n.pred.ToSql()
+	if err != nil {
+		return
+	}
+
+	sql = fmt.Sprintf("NOT (%s)", sql)
+	return
+}
+
+// ToSqlizer compiles the expression to squirrel condition.
+// columns maps a field name to its column name, a field without mapping uses the field name as column
+func ToSqlizer(expr Expr, columns map[string]string) (pred sq.Sqlizer, err error) {
+	switch e := expr.(type) {
+	case And:
+		left, right, er := toSqlizers(e.Left, e.Right, columns)
+		if er != nil {
+			err = er
+			return
+		}
+		pred = sq.And{left, right}
+	case Or:
+		left, right, er := toSqlizers(e.Left, e.Right, columns)
+		if er != nil {
+			err = er
+			return
+		}
+		pred = sq.Or{left, right}
+	case Not:
+		inner, er := ToSqlizer(e.Expr, columns)
+		if er != nil {
+			err = er
+			return
+		}
+		pred = not{pred: inner}
+	case Comparison:
+		pred, err = comparisonToSqlizer(e, columns)
+	default:
+		err = fmt.Errorf("filterql: unsupported expression %T", expr)
+	}
+
+	return
+}
+
+func toSqlizers(left, right Expr, columns map[string]string) (l, r sq.Sqlizer, err error) {
+	l, err = ToSqlizer(left, columns)
+	if err != nil {
+		return
+	}
+
+	r, err = ToSqlizer(right, columns)
+	return
+}
+
+func comparisonToSqlizer(c Comparison, columns map[string]string) (pred sq.Sqlizer, err error) {
+	column := c.Field
+	if col, ok := columns[c.Field]; ok {
+		column = col
+	}
+
+	if len(c.Values) == 0 {
+		err = fmt.Errorf("filterql: missing value of field %s", c.Field)
+		return
+	}
+
+	value := c.Values[0]
+	switch c.Operator {
+	case Eq:
+		pred = sq.Eq{column: value}
+	case Ne:
+		pred = sq.NotEq{column: value}
+	case Gt:
+		pred = sq.Gt{column: value}
+	case Ge:
+		pred = sq.GtOrEq{column: value}
+	case Lt:
+		pred = sq.Lt{column: value}
+	case Le:
+		pred = sq.LtOrEq{column: value}
+	case In:
+		pred = sq.Eq{column: c.Values}
+	case Like:
+		pred = sq.Like{column: value}
+	default:
+		err = fmt.Errorf("filterql: unsupported operator %s", c.Operator)
+	}
+
+	return
+}
@@ -0,0 +1,158 @@
+// Package health reports the health of the application and its dependencies for the liveness and readiness probes
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Status of a check and of the report
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// DefaultTimeout is the timeout of a check when the timeout isn't configured
+const DefaultTimeout = 2 * time.Second
+
+// Checker checks a dependency, it returns an error when the dependency isn't usable
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is a function implementing Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result is the result of a check
+type Result struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the result of every check, its status fails when any check fails
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Health runs the registered checks, it is safe for concurrent use
+type Health struct {
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	checkers map[string]Checker
+}
+
+// New creates a new health with the timeout of each check
+func New(timeout time.Duration) *Health {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Health{
+		timeout:  timeout,
+		checkers: map[string]Checker{},
+	}
+}
+
+// Register registers the checker of a component, a checker registered with the same name is replaced
+func (h *Health) Register(name string, c Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkers[name] = c
+}
+
+// Check runs every check concurrently, a check which doesn't return within the timeout fails
+func (h *Health) Check(ctx context.Context) (report Report) {
+	h.mu.RLock()
+	checkers := make(map[string]Checker, len(h.checkers))
+	for name, c := range h.checkers {
+		checkers[name] = c
+	}
+	h.mu.RUnlock()
+
+	report = Report{Status: StatusOK, Checks: make(map[string]Result, len(checkers))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, c := range checkers {
+		wg.Add(1)
+		go func(name string, c Checker) {
+			defer wg.Done()
+			res := h.run(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = res
+			if res.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(name, c)
+	}
+	wg.Wait()
+
+	return
+}
+
+// run runs a check with the timeout, the result is returned on timeout even when the checker ignores the context
+func (h *Health) run(ctx context.Context, c Checker) (res Result) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res = Result{Status: StatusOK, DurationMS: int64(time.Since(start) / time.Millisecond)}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+
+	return
+}
+
+// LiveHandler returns the handler of the liveness probe, it doesn't check the dependencies
+// so the process isn't restarted when a dependency is down
+func LiveHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, Report{Status: StatusOK, Checks: map[string]Result{}})
+	}
+}
+
+// Handler returns the handler of the readiness probe, it responds 503 Service Unavailable when any check fails
+func (h *Health) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := h.Check(c.Request().Context())
+
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		c.Response().Header().Set("Cache-Control", "no-store")
+
+		return c.JSON(status, report)
+	}
+}
@@ -0,0 +1,141 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/health"
+)
+
+func ok(ctx context.Context) error {
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	tests := map[string]struct {
+		checkers       map[string]health.Checker
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		"no checks": {
+			checkers:       map[string]health.Checker{},
+			expectedStatus: health.StatusOK,
+			expectedChecks: map[string]string{},
+		},
+		"ok": {
+			checkers:       map[string]health.Checker{"mariadb": health.CheckerFunc(ok)},
+			expectedStatus: health.StatusOK,
+			expectedChecks: map[string]string{"mariadb": health.StatusOK},
+		},
+		"failed check": {
+			checkers: map[string]health.Checker{
+				"mariadb": health.CheckerFunc(ok),
+				"migrations": health.CheckerFunc(func(ctx context.Context) error {
+					return errors.New("database is not migrated")
+				}),
+			},
+			expectedStatus: health.StatusFail,
+			expectedChecks: map[string]string{"mariadb": health.StatusOK, "migrations": health.StatusFail},
+		},
+		"check ignoring timeout": {
+			checkers: map[string]health.Checker{
+				"mariadb": health.CheckerFunc(func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}),
+			},
+			expectedStatus: health.StatusFail,
+			expectedChecks: map[string]string{"mariadb": health.StatusFail},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := health.New(50 * time.Millisecond)
+			for name, c := range tc.checkers {
+				h.Register(name, c)
+			}
+
+			start := time.Now()
+			report := h.Check(context.Background())
+			require.True(t, time.Since(start) < 500*time.Millisecond)
+			require.Equal(t, tc.expectedStatus, report.Status)
+			require.Len(t, report.Checks, len(tc.expectedChecks))
+			for name, status := range tc.expectedChecks {
+				require.Equal(t, status, report.Checks[name].Status)
+				if status == health.StatusFail {
+					require.NotEmpty(t, report.Checks[name].Error)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := map[string]struct {
+		err            error
+		expectedCode   int
+		expectedStatus string
+	}{
+		"ok":           {expectedCode: http.StatusOK, expectedStatus: health.StatusOK},
+		"failed check": {err: errors.New("connection refused"), expectedCode: http.StatusServiceUnavailable, expectedStatus: health.StatusFail},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := health.New(time.Second)
+			h.Register("mariadb", health.CheckerFunc(func(ctx context.Context) error {
+				return tc.err
+			}))
+
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/readyz", nil), rec)
+
+			require.NoError(t, h.Handler()(c))
+			require.Equal(t, tc.expectedCode, rec.Code)
+			require.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+			var report health.Report
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+			require.Equal(t, tc.expectedStatus, report.Status)
+		})
+	}
+}
+
+func TestLiveHandler(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/livez", nil), rec)
+
+	require.NoError(t, health.LiveHandler()(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"status":"ok","checks":{}}`, rec.Body.String())
+}
+
+func TestLatestMigrationVersion(t *testing.T) {
+	version, err := health.LatestMigrationVersion(filepath.Join("..", "..", "driver", "mariadb", "migrations"))
+	require.NoError(t, err)
+	require.Equal(t, uint(1792391300), version)
+
+	dir, err := ioutil.TempDir("", "migrations")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = health.LatestMigrationVersion(dir)
+	require.Error(t, err)
+
+	_, err = health.LatestMigrationVersion(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
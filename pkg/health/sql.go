@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+
+	"github.com/friendsofgo/errors"
+)
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+// LatestMigrationVersion returns the version of the last up migration of golang-migrate in the migrations directory
+func LatestMigrationVersion(migrationPath string) (version uint, err error) {
+	files, err := ioutil.ReadDir(migrationPath)
+	if err != nil {
+		return
+	}
+
+	for _, f := range files {
+		m := migrationFilePattern.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+
+		v, er := strconv.ParseUint(m[1], 10, 64)
+		if er != nil {
+			return 0, er
+		}
+
+		if uint(v) > version {
+			version = uint(v)
+		}
+	}
+
+	if version == 0 {
+		err = fmt.Errorf("no migration is found in %s", migrationPath)
+	}
+
+	return
+}
+
+// DBChecker checks the connection to the database
+func DBChecker(db *sql.DB) CheckerFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// MigrationChecker checks the database is migrated to the version by golang-migrate and its last migration didn't fail
+func MigrationChecker(db *sql.DB, version uint) CheckerFunc {
+	return func(ctx context.Context) (err error) {
+		var (
+			current uint
+			dirty   bool
+		)
+		err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&current, &dirty)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err = errors.New("database is not migrated")
+			}
+			return
+		}
+
+		switch {
+		case dirty:
+			return fmt.Errorf("migration %d failed and must be fixed", current)
+		case current != version:
+			return fmt.Errorf("database is migrated to %d but %d is expected", current, version)
+		}
+
+		return
+	}
+}
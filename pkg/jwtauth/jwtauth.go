@@ -0,0 +1,250 @@
+// Package jwtauth verifies HS256 and RS256 bearer tokens.
+// Keys are loaded from a shared secret, a PEM encoded RSA public key or a local JWKS file.
+package jwtauth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Config is the configuration of a verifier, at least one key source must be set
+type Config struct {
+	HMACSecret       string
+	RSAPublicKeyFile string
+	JWKSFile         string
+	Issuer           string
+	Audience         string
+}
+
+// Verifier verifies bearer tokens
+type Verifier struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+	parser   *jwt.Parser
+}
+
+// ErrInvalidToken is returned when a token is malformed, expired or signed with an unknown key
+var ErrInvalidToken = errors.New("invalid token")
+
+// ErrNoKey is returned by New when no key source is configured
+var ErrNoKey = errors.New("jwtauth: no verification key is configured")
+
+// defaultKeyID is the key id of keys which are not loaded from JWKS
+const defaultKeyID = ""
+
+// New creates a new verifier from the config
+func New(cfg Config) (v *Verifier, err error) {
+	v = &Verifier{
+		hmacKeys: map[string][]byte{},
+		rsaKeys:  map[string]*rsa.PublicKey{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		parser:   jwt.NewParser(jwt.WithValidMethods([]string{"HS256", "RS256"}), jwt.WithJSONNumber()),
+	}
+
+	if cfg.HMACSecret != "" {
+		v.hmacKeys[defaultKeyID] = []byte(cfg.HMACSecret)
+	}
+
+	if cfg.RSAPublicKeyFile != "" {
+		var raw []byte
+		if raw, err = ioutil.ReadFile(cfg.RSAPublicKeyFile); err != nil {
+			err = errors.Wrap(err, "can't read rsa public key file")
+			return
+		}
+
+		if v.rsaKeys[defaultKeyID], err = jwt.ParseRSAPublicKeyFromPEM(raw); err != nil {
+			err = errors.Wrap(err, "can't parse rsa public key")
+			return
+		}
+	}
+
+	if cfg.JWKSFile != "" {
+		if err = v.loadJWKS(cfg.JWKSFile); err != nil {
+			return
+		}
+	}
+
+	if len(v.hmacKeys) == 0 && len(v.rsaKeys) == 0 {
+		err = ErrNoKey
+	}
+
+	return
+}
+
+// ErrUnsupportedKey is returned for a JSON web key which type is not supported
+var ErrUnsupportedKey = errors.New("jwtauth: unsupported key type")
+
+// JWK is a JSON web key, RSA and oct keys are supported
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// JWKS is a JSON web key set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Key returns the *rsa.PublicKey of a RSA key or the secret of an oct key
+func (k JWK) Key() (key interface{}, err error) {
+	switch k.Kty {
+	case "RSA":
+		n, er := base64.RawURLEncoding.DecodeString(k.N)
+		if er != nil {
+			return nil, errors.Wrapf(er, "invalid modulus of key %s", k.Kid)
+		}
+
+		e, er := base64.RawURLEncoding.DecodeString(k.E)
+		if er != nil {
+			return nil, errors.Wrapf(er, "invalid exponent of key %s", k.Kid)
+		}
+
+		key = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	case "oct":
+		secret, er := base64.RawURLEncoding.DecodeString(k.K)
+		if er != nil {
+			return nil, errors.Wrapf(er, "invalid secret of key %s", k.Kid)
+		}
+
+		key = secret
+	default:
+		err = errors.Wrapf(ErrUnsupportedKey, "%s of key %s", k.Kty, k.Kid)
+	}
+
+	return
+}
+
+func (v *Verifier) loadJWKS(path string) (err error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "can't read jwks file")
+	}
+
+	var set JWKS
+	if err = json.Unmarshal(raw, &set); err != nil {
+		return errors.Wrap(err, "can't parse jwks file")
+	}
+
+	for _, jwk := range set.Keys {
+		key, er := jwk.Key()
+		if er != nil {
+			return er
+		}
+
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			v.rsaKeys[jwk.Kid] = k
+		case []byte:
+			v.hmacKeys[jwk.Kid] = k
+		}
+	}
+
+	return
+}
+
+// Verify verifies the token and returns its subject, roles, department, employee, tenant and claims.
+// Roles are read from roles claim, the department from dept_id claim, the employee from employee_id claim
+// and the tenant from tenant_id claim.
+// Any verification failure is returned as ErrInvalidToken with the reason wrapped in the message
+func (v *Verifier) Verify(token string) (p domain.Principal, err error) {
+	claims := jwt.MapClaims{}
+	_, err = v.parser.ParseWithClaims(token, claims, v.keyFunc)
+	if err != nil {
+		err = errors.Wrap(ErrInvalidToken, err.Error())
+		return
+	}
+
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		err = errors.Wrap(ErrInvalidToken, "unexpected issuer")
+		return
+	}
+
+	if v.audience != "" && !claims.VerifyAudience(v.audience, true) {
+		err = errors.Wrap(ErrInvalidToken, "unexpected audience")
+		return
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		err = errors.Wrap(ErrInvalidToken, "missing expiration")
+		return
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		err = errors.Wrap(ErrInvalidToken, "missing subject")
+		return
+	}
+
+	deptID, _ := claims["dept_id"].(string)
+	employeeID, _ := claims["employee_id"].(string)
+	tenantID, _ := claims["tenant_id"].(string)
+	p = domain.Principal{
+		Subject:      subject,
+		Roles:        RolesFromClaim(claims["roles"]),
+		DepartmentID: deptID,
+		EmployeeID:   employeeID,
+		TenantID:     tenantID,
+		Claims:       claims,
+	}
+
+	return
+}
+
+// RolesFromClaim reads a roles claim which is either an array or a space separated string
+func RolesFromClaim(claim interface{}) (roles []domain.Role) {
+	roles = make([]domain.Role, 0)
+	switch v := claim.(type) {
+	case string:
+		for _, r := range strings.Fields(v) {
+			roles = append(roles, domain.Role(r))
+		}
+	case []interface{}:
+		for _, r := range v {
+			if s, ok := r.(string); ok {
+				roles = append(roles, domain.Role(s))
+			}
+		}
+	}
+
+	return
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (key interface{}, err error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case "HS256":
+		if k, ok := v.hmacKeys[kid]; ok {
+			return k, nil
+		}
+	case "RS256":
+		if k, ok := v.rsaKeys[kid]; ok {
+			return k, nil
+		}
+	}
+
+	err = fmt.Errorf("unknown %s key %q", token.Method.Alg(), kid)
+	return
+}
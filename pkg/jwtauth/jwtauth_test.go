@@ -0,0 +1,167 @@
+package jwtauth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+)
+
+const secret = "s3cr3t"
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func writeFile(t *testing.T, dir, name string, content []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, content, 0600))
+
+	return path
+}
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwtauth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	pemFile := writeFile(t, dir, "public.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kid": "rsa-1",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(jwksRSAKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(jwksRSAKey.E)).Bytes()),
+			},
+			{
+				"kid": "oct-1",
+				"kty": "oct",
+				"k":   base64.RawURLEncoding.EncodeToString([]byte("jwks-secret")),
+			},
+		},
+	})
+	require.NoError(t, err)
+	jwksFile := writeFile(t, dir, "jwks.json", jwks)
+
+	verifier, err := jwtauth.New(jwtauth.Config{
+		HMACSecret:       secret,
+		RSAPublicKeyFile: pemFile,
+		JWKSFile:         jwksFile,
+		Issuer:           "https://auth.example.com",
+		Audience:         "employee",
+	})
+	require.NoError(t, err)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":     "user-1",
+			"iss":     "https://auth.example.com",
+			"aud":     "employee",
+			"exp":     time.Now().Add(time.Hour).Unix(),
+			"roles":   []string{"viewer", "department-head"},
+			"dept_id": "dept-1",
+		}
+	}
+
+	expiredClaims := validClaims()
+	expiredClaims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	otherIssuerClaims := validClaims()
+	otherIssuerClaims["iss"] = "https://evil.example.com"
+
+	otherAudienceClaims := validClaims()
+	otherAudienceClaims["aud"] = "payroll"
+
+	noSubjectClaims := validClaims()
+	delete(noSubjectClaims, "sub")
+
+	noExpirationClaims := validClaims()
+	delete(noExpirationClaims, "exp")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		token       string
+		expectedErr bool
+	}{
+		"success with hs256":      {token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", validClaims())},
+		"success with rs256":      {token: sign(t, jwt.SigningMethodRS256, rsaKey, "", validClaims())},
+		"success with jwks rs256": {token: sign(t, jwt.SigningMethodRS256, jwksRSAKey, "rsa-1", validClaims())},
+		"success with jwks hs256": {token: sign(t, jwt.SigningMethodHS256, []byte("jwks-secret"), "oct-1", validClaims())},
+		"wrong secret":            {token: sign(t, jwt.SigningMethodHS256, []byte("wrong"), "", validClaims()), expectedErr: true},
+		"unknown rsa key":         {token: sign(t, jwt.SigningMethodRS256, otherKey, "", validClaims()), expectedErr: true},
+		"unknown key id":          {token: sign(t, jwt.SigningMethodRS256, jwksRSAKey, "rsa-2", validClaims()), expectedErr: true},
+		"unsupported algorithm":   {token: sign(t, jwt.SigningMethodHS512, []byte(secret), "", validClaims()), expectedErr: true},
+		"expired token":           {token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", expiredClaims), expectedErr: true},
+		"unexpected issuer":       {token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", otherIssuerClaims), expectedErr: true},
+		"unexpected audience":     {token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", otherAudienceClaims), expectedErr: true},
+		"missing subject":         {token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", noSubjectClaims), expectedErr: true},
+		"missing expiration":      {token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", noExpirationClaims), expectedErr: true},
+		"malformed token":         {token: "not-a-token", expectedErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := verifier.Verify(tc.token)
+			if tc.expectedErr {
+				require.Error(t, err)
+				require.Equal(t, jwtauth.ErrInvalidToken, errors.Cause(err))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "user-1", p.Subject)
+			require.Equal(t, []domain.Role{domain.RoleViewer, domain.RoleDepartmentHead}, p.Roles)
+			require.Equal(t, "dept-1", p.DepartmentID)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("without key", func(t *testing.T) {
+		_, err := jwtauth.New(jwtauth.Config{})
+		require.EqualError(t, err, "jwtauth: no verification key is configured")
+	})
+
+	t.Run("with missing jwks file", func(t *testing.T) {
+		_, err := jwtauth.New(jwtauth.Config{JWKSFile: "/nonexistent/jwks.json"})
+		require.Error(t, err)
+	})
+}
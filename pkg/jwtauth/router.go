@@ -0,0 +1,57 @@
+package jwtauth
+
+import (
+	"github.com/friendsofgo/errors"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// TokenVerifier verifies a token and returns the authenticated caller
+type TokenVerifier interface {
+	Verify(token string) (p domain.Principal, err error)
+}
+
+// Router verifies a token with the verifier of its iss claim.
+// Tokens of other issuers are verified by the fallback verifier, they are rejected when there is no fallback
+type Router struct {
+	issuers  map[string]TokenVerifier
+	fallback TokenVerifier
+	parser   *jwt.Parser
+}
+
+// NewRouter creates a router with an optional fallback verifier
+func NewRouter(fallback TokenVerifier) *Router {
+	return &Router{
+		issuers:  map[string]TokenVerifier{},
+		fallback: fallback,
+		parser:   jwt.NewParser(),
+	}
+}
+
+// Handle registers the verifier of an issuer
+func (r *Router) Handle(issuer string, v TokenVerifier) {
+	r.issuers[issuer] = v
+}
+
+// Verify verifies the token with the verifier of its issuer.
+// The iss claim is read before the token is verified, it is verified again by the chosen verifier
+func (r *Router) Verify(token string) (p domain.Principal, err error) {
+	claims := jwt.MapClaims{}
+	if _, _, err = r.parser.ParseUnverified(token, claims); err != nil {
+		err = errors.Wrap(ErrInvalidToken, err.Error())
+		return
+	}
+
+	issuer, _ := claims["iss"].(string)
+	if v, ok := r.issuers[issuer]; ok {
+		return v.Verify(token)
+	}
+
+	if r.fallback == nil {
+		err = errors.Wrap(ErrInvalidToken, "unexpected issuer")
+		return
+	}
+
+	return r.fallback.Verify(token)
+}
@@ -0,0 +1,48 @@
+package jwtauth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+)
+
+type verifierFunc func(token string) (domain.Principal, error)
+
+func (f verifierFunc) Verify(token string) (domain.Principal, error) {
+	return f(token)
+}
+
+func TestRouter(t *testing.T) {
+	named := func(name string) jwtauth.TokenVerifier {
+		return verifierFunc(func(token string) (domain.Principal, error) {
+			return domain.Principal{Subject: name}, nil
+		})
+	}
+
+	token := func(iss string) string {
+		return sign(t, jwt.SigningMethodHS256, []byte(secret), "", jwt.MapClaims{"iss": iss, "exp": time.Now().Add(time.Hour).Unix()})
+	}
+
+	router := jwtauth.NewRouter(named("fallback"))
+	router.Handle("https://sso.example.com", named("sso"))
+
+	p, err := router.Verify(token("https://sso.example.com"))
+	require.NoError(t, err)
+	require.Equal(t, "sso", p.Subject)
+
+	p, err = router.Verify(token("employee"))
+	require.NoError(t, err)
+	require.Equal(t, "fallback", p.Subject)
+
+	_, err = router.Verify("not-a-token")
+	require.Equal(t, jwtauth.ErrInvalidToken, errors.Cause(err))
+
+	_, err = jwtauth.NewRouter(nil).Verify(token("employee"))
+	require.Equal(t, jwtauth.ErrInvalidToken, errors.Cause(err))
+}
@@ -0,0 +1,72 @@
+package jwtauth
+
+import (
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Signer issues HS256 tokens which are verified by a Verifier configured with the same secret
+type Signer struct {
+	secret   []byte
+	issuer   string
+	audience string
+}
+
+// NewSigner creates a new signer from HMACSecret, Issuer and Audience of the config
+func NewSigner(cfg Config) (s *Signer, err error) {
+	if cfg.HMACSecret == "" {
+		err = errors.New("jwtauth: hmac secret is required to sign tokens")
+		return
+	}
+
+	s = &Signer{
+		secret:   []byte(cfg.HMACSecret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	return
+}
+
+// Sign issues a token for the principal identified by id in jti claim.
+// The roles, department, employee and tenant are written to roles, dept_id, employee_id and tenant_id claims
+func (s *Signer) Sign(p domain.Principal, id string, expiresTime time.Time) (token string, err error) {
+	roles := make([]string, 0, len(p.Roles))
+	for _, r := range p.Roles {
+		roles = append(roles, string(r))
+	}
+
+	claims := jwt.MapClaims{
+		"sub":   p.Subject,
+		"jti":   id,
+		"iat":   time.Now().Unix(),
+		"exp":   expiresTime.Unix(),
+		"roles": roles,
+	}
+
+	if p.DepartmentID != "" {
+		claims["dept_id"] = p.DepartmentID
+	}
+
+	if p.EmployeeID != "" {
+		claims["employee_id"] = p.EmployeeID
+	}
+
+	if p.TenantID != "" {
+		claims["tenant_id"] = p.TenantID
+	}
+
+	if s.issuer != "" {
+		claims["iss"] = s.issuer
+	}
+
+	if s.audience != "" {
+		claims["aud"] = s.audience
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
@@ -0,0 +1,50 @@
+package jwtauth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+)
+
+func TestSign(t *testing.T) {
+	cfg := jwtauth.Config{HMACSecret: secret, Issuer: "employee", Audience: "employee-api"}
+
+	signer, err := jwtauth.NewSigner(cfg)
+	require.NoError(t, err)
+	verifier, err := jwtauth.New(cfg)
+	require.NoError(t, err)
+
+	p := domain.Principal{Subject: "user-1", Roles: []domain.Role{domain.RoleDepartmentHead}, DepartmentID: "dept-1", EmployeeID: "employee-1", TenantID: "acme"}
+
+	t.Run("success", func(t *testing.T) {
+		token, err := signer.Sign(p, "token-1", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		res, err := verifier.Verify(token)
+		require.NoError(t, err)
+		require.Equal(t, p.Subject, res.Subject)
+		require.Equal(t, p.Roles, res.Roles)
+		require.Equal(t, p.DepartmentID, res.DepartmentID)
+		require.Equal(t, p.EmployeeID, res.EmployeeID)
+		require.Equal(t, p.TenantID, res.TenantID)
+		require.Equal(t, "token-1", res.Claims["jti"])
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := signer.Sign(p, "token-1", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(token)
+		require.Equal(t, jwtauth.ErrInvalidToken, errors.Cause(err))
+	})
+
+	t.Run("without secret", func(t *testing.T) {
+		_, err := jwtauth.NewSigner(jwtauth.Config{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,157 @@
+// Package lifecycle starts and stops the components of the application, e.g. servers, workers and connections,
+// and stops them gracefully on SIGINT or SIGTERM
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+// DefaultTimeout is the timeout of stopping the components when the timeout isn't configured
+const DefaultTimeout = 10 * time.Second
+
+// Hook is the start and stop hooks of a component, both are optional.
+// OnStart must not block, a long running component is started in a goroutine and reports its failure with Fail
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle starts the hooks in order of their registration and stops them in reverse order
+type Lifecycle struct {
+	timeout time.Duration
+
+	mu      sync.Mutex
+	hooks   []Hook
+	stopped bool
+
+	failOnce sync.Once
+	failed   chan error
+}
+
+// New creates a new lifecycle which stops the components within the timeout
+func New(timeout time.Duration) *Lifecycle {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Lifecycle{
+		timeout: timeout,
+		failed:  make(chan error, 1),
+	}
+}
+
+// Append registers the hooks of a component
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, h)
+}
+
+// Start calls the start hooks in order of their registration.
+// When a hook fails the components are stopped and the error is returned
+func (l *Lifecycle) Start(ctx context.Context) (err error) {
+	l.mu.Lock()
+	hooks := make([]Hook, len(l.hooks))
+	copy(hooks, l.hooks)
+	l.mu.Unlock()
+
+	for _, h := range hooks {
+		if h.OnStart == nil {
+			continue
+		}
+
+		logger.FromContext(ctx).Debugf("starting %s", h.Name)
+		if err = h.OnStart(ctx); err != nil {
+			err = errors.Wrapf(err, "can't start %s", h.Name)
+			if er := l.stop(); er != nil {
+				logger.FromContext(ctx).Errorf("failed to stop after a failed start: %v", er)
+			}
+			return
+		}
+	}
+
+	return
+}
+
+// Stop calls the stop hooks in reverse order of their registration within the timeout,
+// every hook is called even when a previous one fails. Only the first call stops the components
+func (l *Lifecycle) Stop(ctx context.Context) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	l.mu.Lock()
+	if l.stopped {
+		l.mu.Unlock()
+		return
+	}
+	l.stopped = true
+	hooks := make([]Hook, len(l.hooks))
+	copy(hooks, l.hooks)
+	l.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+
+		logger.FromContext(ctx).Debugf("stopping %s", h.Name)
+		if er := h.OnStop(ctx); er != nil {
+			logger.FromContext(ctx).Errorf("failed to stop %s: %v", h.Name, er)
+			if err == nil {
+				err = errors.Wrapf(er, "can't stop %s", h.Name)
+			}
+		}
+	}
+
+	return
+}
+
+func (l *Lifecycle) stop() error {
+	return l.Stop(context.Background())
+}
+
+// Fail reports the failure of a running component, Run stops the components and returns the first failure
+func (l *Lifecycle) Fail(err error) {
+	l.failOnce.Do(func() {
+		l.failed <- err
+	})
+}
+
+// Run starts the components and blocks until SIGINT, SIGTERM or a failure of a component, then stops the components.
+// The failure is returned, otherwise the error of stopping
+func (l *Lifecycle) Run(ctx context.Context) (err error) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err = l.Start(ctx); err != nil {
+		return
+	}
+
+	select {
+	case sig := <-signals:
+		logger.FromContext(ctx).Infof("received %s, shutting down", sig)
+	case err = <-l.failed:
+		logger.FromContext(ctx).Errorf("shutting down after a failure: %v", err)
+	case <-ctx.Done():
+	}
+
+	// the components are stopped with a fresh context as ctx may be the reason of stopping
+	if er := l.stop(); er != nil && err == nil {
+		err = er
+	}
+
+	return
+}
@@ -0,0 +1,150 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/lifecycle"
+)
+
+// recorder records the calls of the hooks
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) hook(name string, startErr error) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			r.calls = append(r.calls, "start "+name)
+			return startErr
+		},
+		OnStop: func(ctx context.Context) error {
+			r.calls = append(r.calls, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	r := &recorder{}
+	l := lifecycle.New(time.Second)
+	l.Append(r.hook("db", nil))
+	l.Append(r.hook("server", nil))
+	l.Append(lifecycle.Hook{Name: "no hooks"})
+
+	require.NoError(t, l.Start(context.Background()))
+	require.NoError(t, l.Stop(context.Background()))
+	require.NoError(t, l.Stop(context.Background()))
+	require.Equal(t, []string{"start db", "start server", "stop server", "stop db"}, r.calls)
+}
+
+func TestStartFailure(t *testing.T) {
+	r := &recorder{}
+	l := lifecycle.New(time.Second)
+	l.Append(r.hook("db", nil))
+	l.Append(r.hook("server", errors.New("address already in use")))
+	l.Append(r.hook("worker", nil))
+
+	err := l.Start(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't start server")
+	require.Equal(t, []string{"start db", "start server", "stop worker", "stop server", "stop db"}, r.calls)
+}
+
+func TestStopErrorAndTimeout(t *testing.T) {
+	var stopped []string
+	l := lifecycle.New(50 * time.Millisecond)
+	l.Append(lifecycle.Hook{
+		Name: "db",
+		OnStop: func(ctx context.Context) error {
+			stopped = append(stopped, "db")
+			return nil
+		},
+	})
+	l.Append(lifecycle.Hook{
+		Name: "server",
+		OnStop: func(ctx context.Context) error {
+			stopped = append(stopped, "server")
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	err := l.Stop(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't stop server")
+	require.Equal(t, []string{"server", "db"}, stopped)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("failure", func(t *testing.T) {
+		r := &recorder{}
+		l := lifecycle.New(time.Second)
+		l.Append(r.hook("server", nil))
+		l.Append(lifecycle.Hook{
+			Name: "worker",
+			OnStart: func(ctx context.Context) error {
+				go l.Fail(errors.New("worker crashed"))
+				return nil
+			},
+		})
+
+		err := l.Run(context.Background())
+		require.EqualError(t, err, "worker crashed")
+		require.Equal(t, []string{"start server", "stop server"}, r.calls)
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		r := &recorder{}
+		l := lifecycle.New(time.Second)
+		l.Append(r.hook("server", nil))
+		l.Append(lifecycle.Hook{
+			Name: "signal",
+			OnStart: func(ctx context.Context) error {
+				return syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			},
+		})
+
+		require.NoError(t, l.Run(context.Background()))
+		require.Equal(t, []string{"start server", "stop server"}, r.calls)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		r := &recorder{}
+		l := lifecycle.New(time.Second)
+		l.Append(r.hook("server", nil))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.NoError(t, l.Run(ctx))
+		require.Equal(t, []string{"start server", "stop server"}, r.calls)
+	})
+}
+
+func TestEvery(t *testing.T) {
+	calls := make(chan struct{}, 10)
+	l := lifecycle.New(time.Second)
+	l.Append(lifecycle.Every("sweeper", 5*time.Millisecond, func(ctx context.Context) {
+		calls <- struct{}{}
+		<-ctx.Done()
+	}))
+
+	require.NoError(t, l.Start(context.Background()))
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		require.FailNow(t, "worker isn't called")
+	}
+
+	// the running call is cancelled and awaited
+	require.NoError(t, l.Stop(context.Background()))
+	require.Len(t, calls, 0)
+}
@@ -0,0 +1,56 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// Every returns the hooks of a background worker calling fn every interval, the worker is stopped
+// by cancelling the context of fn and waiting for the running call
+func Every(name string, interval time.Duration, fn func(ctx context.Context)) Hook {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+
+	return Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			var workerCtx context.Context
+			workerCtx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						fn(workerCtx)
+					case <-workerCtx.Done():
+						return
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			// the worker is stopped before being started when another component failed to start
+			if cancel == nil {
+				return nil
+			}
+
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
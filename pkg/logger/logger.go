@@ -0,0 +1,111 @@
+// Package logger is the structured logger of the application.
+// A request scoped logger is carried in the context, so every line logged through FromContext while serving a request
+// is correlated by its request_id field.
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	loggerContextKey    struct{}
+	requestIDContextKey struct{}
+)
+
+// Fields are the structured fields of a log line
+type Fields map[string]interface{}
+
+// Logger writes structured log lines
+type Logger interface {
+	WithField(key string, value interface{}) Logger
+	WithFields(fields Fields) Logger
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Config is the configuration of the logger, the level defaults to info and the format to text
+type Config struct {
+	// Level is one of debug, info, warn, error or fatal
+	Level string
+	// Format is either json or text
+	Format string
+	// Output defaults to stderr
+	Output io.Writer
+}
+
+// Configure configures the logger of the application, it must be called before any line is logged
+func Configure(cfg Config) (err error) {
+	level := logrus.InfoLevel
+	if cfg.Level != "" {
+		if level, err = logrus.ParseLevel(cfg.Level); err != nil {
+			return
+		}
+	}
+
+	var formatter logrus.Formatter = &logrus.TextFormatter{FullTimestamp: true}
+	if cfg.Format == "json" {
+		formatter = &logrus.JSONFormatter{}
+	}
+
+	output := cfg.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	std := logrus.StandardLogger()
+	std.SetLevel(level)
+	std.SetFormatter(formatter)
+	std.SetOutput(output)
+
+	return
+}
+
+// L returns the logger of the application, FromContext must be used instead when a context is available
+func L() Logger {
+	return entry{logrus.NewEntry(logrus.StandardLogger())}
+}
+
+// NewContext returns a new context carrying the logger
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// FromContext returns the logger of the context, it is the logger of the application when the context carries none
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerContextKey{}).(Logger); ok {
+		return l
+	}
+
+	return L()
+}
+
+// NewContextWithRequestID returns a new context carrying the request ID and a logger with request_id field
+func NewContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDContextKey{}, requestID)
+	return NewContext(ctx, FromContext(ctx).WithField("request_id", requestID))
+}
+
+// RequestIDFromContext returns the request ID of the context, it is empty when the context carries none
+func RequestIDFromContext(ctx context.Context) (requestID string) {
+	requestID, _ = ctx.Value(requestIDContextKey{}).(string)
+	return
+}
+
+type entry struct {
+	*logrus.Entry
+}
+
+func (e entry) WithField(key string, value interface{}) Logger {
+	return entry{e.Entry.WithField(key, value)}
+}
+
+func (e entry) WithFields(fields Fields) Logger {
+	return entry{e.Entry.WithFields(logrus.Fields(fields))}
+}
@@ -0,0 +1,59 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, logger.Configure(logger.Config{Level: "debug", Format: "json", Output: &buf}))
+	defer logger.Configure(logger.Config{})
+
+	t.Run("with request id", func(t *testing.T) {
+		buf.Reset()
+		ctx := logger.NewContextWithRequestID(context.Background(), "request-1")
+		require.Equal(t, "request-1", logger.RequestIDFromContext(ctx))
+
+		logger.FromContext(ctx).WithField("user_id", "user-1").Infof("hello %s", "world")
+
+		line := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		require.Equal(t, "hello world", line["msg"])
+		require.Equal(t, "info", line["level"])
+		require.Equal(t, "request-1", line["request_id"])
+		require.Equal(t, "user-1", line["user_id"])
+	})
+
+	t.Run("without logger", func(t *testing.T) {
+		buf.Reset()
+		require.Empty(t, logger.RequestIDFromContext(context.Background()))
+
+		logger.FromContext(context.Background()).Debugf("hello")
+
+		line := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		require.Equal(t, "hello", line["msg"])
+		require.NotContains(t, line, "request_id")
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, logger.Configure(logger.Config{Level: "warn", Output: &buf}))
+	defer logger.Configure(logger.Config{})
+
+	logger.L().Infof("hidden")
+	require.Empty(t, buf.String())
+
+	logger.L().Warnf("shown")
+	require.Contains(t, buf.String(), "shown")
+
+	require.Error(t, logger.Configure(logger.Config{Level: "verbose"}))
+}
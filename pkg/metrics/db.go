@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStats collects the connection pool stats of a database
+type DBStats struct {
+	db *sql.DB
+
+	maxOpen           *prometheus.Desc
+	open              *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+// NewDBStats creates a collector of the pool stats of db, the stats are labeled by the name of the database
+func NewDBStats(db *sql.DB, name string) *DBStats {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(Namespace, "db", metric), help, nil, prometheus.Labels{"db_name": name})
+	}
+
+	return &DBStats{
+		db:                db,
+		maxOpen:           desc("max_open_connections", "Maximum number of open connections to the database."),
+		open:              desc("open_connections", "Number of established connections both in use and idle."),
+		inUse:             desc("in_use_connections", "Number of connections currently in use."),
+		idle:              desc("idle_connections", "Number of idle connections."),
+		waitCount:         desc("wait_count_total", "Total number of connections waited for."),
+		waitDuration:      desc("wait_duration_seconds_total", "Total time blocked waiting for a new connection."),
+		maxIdleClosed:     desc("max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns."),
+		maxLifetimeClosed: desc("max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime."),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (s *DBStats) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.maxOpen
+	ch <- s.open
+	ch <- s.inUse
+	ch <- s.idle
+	ch <- s.waitCount
+	ch <- s.waitDuration
+	ch <- s.maxIdleClosed
+	ch <- s.maxLifetimeClosed
+}
+
+// Collect implements prometheus.Collector
+func (s *DBStats) Collect(ch chan<- prometheus.Metric) {
+	stats := s.db.Stats()
+	ch <- prometheus.MustNewConstMetric(s.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(s.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(s.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(s.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(s.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(s.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(s.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(s.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
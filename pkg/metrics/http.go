@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels the requests which match no route, so unknown paths don't create new series
+const unmatchedRoute = "unmatched"
+
+// HTTP measures the rate, errors and duration of the requests per route
+type HTTP struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// NewHTTP creates the HTTP metrics and registers them
+func NewHTTP(reg prometheus.Registerer) (m *HTTP, err error) {
+	m = &HTTP{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of served requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of served requests by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of requests being served.",
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{m.requests, m.duration, m.inFlight} {
+		if err = reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// Middleware returns a middleware measuring the requests, it must be used before the middlewares returning errors
+// so the status of the error response is measured
+func (m *HTTP) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" || err == echo.ErrNotFound || err == echo.ErrMethodNotAllowed {
+				route = unmatchedRoute
+			}
+
+			method := c.Request().Method
+			m.requests.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+			m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/metrics"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+)
+
+func gather(t *testing.T, reg *prometheus.Registry) map[string][]float64 {
+	t.Helper()
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	values := map[string][]float64{}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			name := f.GetName()
+			for _, l := range m.GetLabel() {
+				name += "," + l.GetName() + "=" + l.GetValue()
+			}
+
+			switch {
+			case m.GetCounter() != nil:
+				values[name] = append(values[name], m.GetCounter().GetValue())
+			case m.GetGauge() != nil:
+				values[name] = append(values[name], m.GetGauge().GetValue())
+			case m.GetHistogram() != nil:
+				values[name] = append(values[name], float64(m.GetHistogram().GetSampleCount()))
+			}
+		}
+	}
+
+	return values
+}
+
+func TestHTTP(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewHTTP(reg)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(m.Middleware())
+	e.Use(middleware.ErrorMiddleware())
+	e.GET("/employees/:id", func(c echo.Context) error {
+		if c.Param("id") == "unknown" {
+			return domain.ErrNotFound
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, target := range []string{"/employees/1", "/employees/2", "/employees/unknown", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	values := gather(t, reg)
+	require.Equal(t, []float64{2}, values["employee_http_requests_total,method=GET,route=/employees/:id,status=200"])
+	require.Equal(t, []float64{1}, values["employee_http_requests_total,method=GET,route=/employees/:id,status=404"])
+	require.Equal(t, []float64{1}, values["employee_http_requests_total,method=GET,route=unmatched,status=404"])
+	require.Equal(t, []float64{3}, values["employee_http_request_duration_seconds,method=GET,route=/employees/:id"])
+	require.Equal(t, []float64{0}, values["employee_http_requests_in_flight"])
+
+	t.Run("already registered", func(t *testing.T) {
+		_, err := metrics.NewHTTP(reg)
+		require.Error(t, err)
+	})
+}
+
+func TestRepository(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewRepository(reg)
+	require.NoError(t, err)
+
+	m.Observe("department", "Get", time.Now(), nil)
+	m.Observe("department", "Get", time.Now(), errors.Wrap(domain.ErrNotFound, "department 1"))
+	m.Observe("department", "Get", time.Now(), errors.New("connection refused"))
+
+	values := gather(t, reg)
+	require.Equal(t, []float64{3}, values["employee_repository_call_duration_seconds,method=Get,repository=department"])
+	require.Equal(t, []float64{1}, values["employee_repository_call_errors_total,method=Get,repository=department"])
+}
+
+func TestDBStats(t *testing.T) {
+	db, err := sql.Open("mysql", "employee:employee-pass@tcp(localhost:3306)/employee")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+
+	c := metrics.NewDBStats(db, "employee")
+	require.Equal(t, 8, testutil.CollectAndCount(c))
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(c))
+	require.Equal(t, []float64{7}, gather(t, reg)["employee_db_max_open_connections,db_name=employee"])
+}
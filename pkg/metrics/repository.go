@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Repository measures the duration and the errors of the repository calls per method
+type Repository struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewRepository creates the repository metrics and registers them
+func NewRepository(reg prometheus.Registerer) (m *Repository, err error) {
+	m = &Repository{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "repository",
+			Name:      "call_duration_seconds",
+			Help:      "Duration of repository calls by repository and method.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "repository",
+			Name:      "call_errors_total",
+			Help:      "Number of failed repository calls by repository and method, a resource which is not found isn't a failure.",
+		}, []string{"repository", "method"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.duration, m.errors} {
+		if err = reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// Observe records a call of the repository method which started at start and returned err
+func (m *Repository) Observe(repository, method string, start time.Time, err error) {
+	m.duration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	if err != nil && errors.Cause(err) != domain.ErrNotFound {
+		m.errors.WithLabelValues(repository, method).Inc()
+	}
+}
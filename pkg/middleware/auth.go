@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Realm is the protection space announced in WWW-Authenticate header
+const Realm = "employee"
+
+// TokenVerifier verifies a bearer token and returns the authenticated caller
+type TokenVerifier interface {
+	Verify(token string) (p domain.Principal, err error)
+}
+
+// APIKeyAuthenticator verifies an API key and returns the authenticated caller
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (p domain.Principal, err error)
+}
+
+// Skipper decides whether a request skips the middleware
+type Skipper func(c echo.Context) bool
+
+// Authentication returns a middleware authenticating the Authorization header,
+// either `Bearer <jwt>` or `ApiKey <key>` when apiKeys is not nil.
+// The principal is put into the request context, see domain.PrincipalFromContext
+func Authentication(verifier TokenVerifier, apiKeys APIKeyAuthenticator, skipper Skipper) echo.MiddlewareFunc {
+	// challenge announces every accepted scheme, params describe the error of the given scheme
+	challenge := func(c echo.Context, scheme, params string) error {
+		h := c.Response().Header()
+		h.Del(echo.HeaderWWWAuthenticate)
+		for _, s := range []string{"Bearer", "ApiKey"} {
+			if s == "ApiKey" && apiKeys == nil {
+				continue
+			}
+
+			value := fmt.Sprintf(`%s realm="%s"`, s, Realm)
+			if s == scheme {
+				value += params
+			}
+			h.Add(echo.HeaderWWWAuthenticate, value)
+		}
+		return domain.ErrUnauthorized
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return challenge(c, "", "")
+			}
+
+			parts := strings.SplitN(header, " ", 2)
+			credentials := ""
+			if len(parts) == 2 {
+				credentials = strings.TrimSpace(parts[1])
+			}
+
+			var (
+				p   domain.Principal
+				err error
+			)
+			switch {
+			case credentials == "":
+				return challenge(c, "Bearer", `, error="invalid_request", error_description="authorization header must contain credentials"`)
+			case strings.EqualFold(parts[0], "Bearer"):
+				if p, err = verifier.Verify(credentials); err != nil {
+					return challenge(c, "Bearer", fmt.Sprintf(`, error="invalid_token", error_description=%q`, err.Error()))
+				}
+			case apiKeys != nil && strings.EqualFold(parts[0], "ApiKey"):
+				if p, err = apiKeys.Authenticate(c.Request().Context(), credentials); err != nil {
+					if errors.Cause(err) != domain.ErrUnauthorized {
+						return errors.Wrap(err, "failed to authenticate api key")
+					}
+					return challenge(c, "ApiKey", `, error="invalid_token", error_description="api key is not valid"`)
+				}
+			default:
+				return challenge(c, "Bearer", `, error="invalid_request", error_description="unsupported authorization scheme"`)
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(domain.NewContextWithPrincipal(req.Context(), p)))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+)
+
+type verifierFunc func(token string) (domain.Principal, error)
+
+func (f verifierFunc) Verify(token string) (domain.Principal, error) {
+	return f(token)
+}
+
+type apiKeyAuthenticatorFunc func(ctx context.Context, key string) (domain.Principal, error)
+
+func (f apiKeyAuthenticatorFunc) Authenticate(ctx context.Context, key string) (domain.Principal, error) {
+	return f(ctx, key)
+}
+
+func TestAuthentication(t *testing.T) {
+	verifier := verifierFunc(func(token string) (p domain.Principal, err error) {
+		if token != "valid-token" {
+			err = errors.New("token is expired")
+			return
+		}
+
+		p = domain.Principal{Subject: "user-1"}
+		return
+	})
+
+	apiKeys := apiKeyAuthenticatorFunc(func(ctx context.Context, key string) (p domain.Principal, err error) {
+		switch key {
+		case "key-1.secret":
+			p = domain.Principal{Subject: "apikey:key-1"}
+		case "key-1.broken":
+			err = errors.New("connection refused")
+		default:
+			err = domain.ErrUnauthorized
+		}
+		return
+	})
+
+	e := echo.New()
+	e.Use(middleware.ErrorMiddleware())
+	e.Use(middleware.Authentication(verifier, apiKeys, func(c echo.Context) bool {
+		return c.Request().URL.Path == "/ping"
+	}))
+
+	e.GET("/ping", func(c echo.Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+	e.GET("/employees", func(c echo.Context) error {
+		p, ok := domain.PrincipalFromContext(c.Request().Context())
+		if !ok {
+			return errors.New("missing principal")
+		}
+		return c.String(http.StatusOK, p.Subject)
+	})
+
+	tests := map[string]struct {
+		target                  string
+		authorization           string
+		expectedStatus          int
+		expectedBody            string
+		expectedWWWAuthenticate []string
+	}{
+		"success": {
+			target:         "/employees",
+			authorization:  "Bearer valid-token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user-1",
+		},
+		"skipped path": {
+			target:         "/ping",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "pong",
+		},
+		"missing authorization": {
+			target:                  "/employees",
+			expectedStatus:          http.StatusUnauthorized,
+			expectedWWWAuthenticate: []string{`Bearer realm="employee"`, `ApiKey realm="employee"`},
+		},
+		"unsupported scheme": {
+			target:         "/employees",
+			authorization:  "Basic dXNlcjpwYXNz",
+			expectedStatus: http.StatusUnauthorized,
+			expectedWWWAuthenticate: []string{
+				`Bearer realm="employee", error="invalid_request", error_description="unsupported authorization scheme"`,
+				`ApiKey realm="employee"`,
+			},
+		},
+		"missing credentials": {
+			target:         "/employees",
+			authorization:  "Bearer ",
+			expectedStatus: http.StatusUnauthorized,
+			expectedWWWAuthenticate: []string{
+				`Bearer realm="employee", error="invalid_request", error_description="authorization header must contain credentials"`,
+				`ApiKey realm="employee"`,
+			},
+		},
+		"invalid token": {
+			target:         "/employees",
+			authorization:  "Bearer expired-token",
+			expectedStatus: http.StatusUnauthorized,
+			expectedWWWAuthenticate: []string{
+				`Bearer realm="employee", error="invalid_token", error_description="token is expired"`,
+				`ApiKey realm="employee"`,
+			},
+		},
+		"success with api key": {
+			target:         "/employees",
+			authorization:  "ApiKey key-1.secret",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "apikey:key-1",
+		},
+		"invalid api key": {
+			target:         "/employees",
+			authorization:  "ApiKey key-1.wrong",
+			expectedStatus: http.StatusUnauthorized,
+			expectedWWWAuthenticate: []string{
+				`Bearer realm="employee"`,
+				`ApiKey realm="employee", error="invalid_token", error_description="api key is not valid"`,
+			},
+		},
+		"api key lookup error": {
+			target:         "/employees",
+			authorization:  "ApiKey key-1.broken",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Equal(t, tc.expectedWWWAuthenticate, rec.Header()[http.CanonicalHeaderKey(echo.HeaderWWWAuthenticate)])
+			if tc.expectedBody != "" {
+				require.Equal(t, tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
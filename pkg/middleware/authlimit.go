@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+)
+
+// AuthFailureLimitConfig is the configuration of the failed authentication limit middleware
+type AuthFailureLimitConfig struct {
+	// Store keeps the buckets
+	Store ratelimit.Store
+	// Limit is the limit of the failed authentications of each client IP
+	Limit ratelimit.Limit
+	// Skipper skips the limit of a request
+	Skipper Skipper
+}
+
+// AuthFailureLimit returns a middleware limiting the failed authentications of each client IP, a request answered
+// with domain.ErrUnauthorized takes a token, e.g. an unknown API key, a bad token or a wrong password on login.
+// A client which exceeded the limit is rejected with domain.ErrTooManyRequests before its credentials are verified,
+// so the middleware must be used before Authentication.
+// The requests are allowed when the store fails, a broken store shouldn't take the API down
+func AuthFailureLimit(cfg AuthFailureLimitConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper != nil && cfg.Skipper(c) {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := "auth-failure|ip:" + RealIP(c)
+
+			res, err := cfg.Store.Peek(ctx, key, cfg.Limit)
+			if err != nil {
+				logger.FromContext(ctx).Errorf("failed to read failed authentications of %s: %v", key, err)
+				return next(c)
+			}
+
+			if !res.Allowed {
+				c.Response().Header().Set(HeaderRetryAfter, ceilSeconds(res.RetryAfter))
+				return domain.ErrTooManyRequests
+			}
+
+			err = next(c)
+			if errors.Cause(err) == domain.ErrUnauthorized {
+				if _, er := cfg.Store.Take(ctx, key, cfg.Limit); er != nil {
+					logger.FromContext(ctx).Errorf("failed to count failed authentication of %s: %v", key, er)
+				}
+			}
+
+			return err
+		}
+	}
+}
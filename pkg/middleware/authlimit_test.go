@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+)
+
+func TestAuthFailureLimit(t *testing.T) {
+	newServer := func(store ratelimit.Store) (e *echo.Echo, verified *int) {
+		verified = new(int)
+
+		e = echo.New()
+		e.Use(middleware.ErrorMiddleware())
+		e.Use(middleware.AuthFailureLimit(middleware.AuthFailureLimitConfig{
+			Store: store,
+			Limit: ratelimit.Limit{Rate: 2, Period: time.Minute, Burst: 2},
+		}))
+		e.GET("/departments", func(c echo.Context) error {
+			*verified++
+			if c.Request().Header.Get(echo.HeaderAuthorization) != "ApiKey valid" {
+				return errors.Wrap(domain.ErrUnauthorized, "failed to authenticate")
+			}
+			return c.NoContent(http.StatusOK)
+		})
+
+		return
+	}
+
+	do := func(e *echo.Echo, remoteAddr, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/departments", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderAuthorization, "ApiKey "+key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("client is rejected before its credentials are verified", func(t *testing.T) {
+		e, verified := newServer(ratelimit.NewMemoryStore())
+
+		require.Equal(t, http.StatusOK, do(e, "10.0.0.1:1234", "valid").Code)
+		require.Equal(t, http.StatusUnauthorized, do(e, "10.0.0.1:1234", "guess-1").Code)
+		require.Equal(t, http.StatusUnauthorized, do(e, "10.0.0.1:1234", "guess-2").Code)
+
+		rec := do(e, "10.0.0.1:1234", "valid")
+		require.Equal(t, http.StatusTooManyRequests, rec.Code)
+		require.Equal(t, "30", rec.Header().Get(middleware.HeaderRetryAfter))
+		require.Equal(t, 3, *verified)
+
+		// other clients have their own bucket
+		require.Equal(t, http.StatusUnauthorized, do(e, "10.0.0.2:1234", "guess-1").Code)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		e, _ := newServer(storeFunc(func(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+			return ratelimit.Result{}, errors.New("connection refused")
+		}))
+
+		require.Equal(t, http.StatusUnauthorized, do(e, "10.0.0.1:1234", "guess-1").Code)
+		require.Equal(t, http.StatusOK, do(e, "10.0.0.1:1234", "valid").Code)
+	})
+}
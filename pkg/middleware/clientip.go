@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// clientIPKey is the key of the client IP in the echo context
+const clientIPKey = "client_ip"
+
+// IPExtractor returns the IP of the client of a request
+type IPExtractor func(req *http.Request) string
+
+// ExtractIPDirect returns the IP of the peer, for a server which isn't behind a proxy
+func ExtractIPDirect() IPExtractor {
+	return func(req *http.Request) string {
+		return peerIP(req)
+	}
+}
+
+// ExtractIPFromXFFHeader returns the client IP forwarded by the trusted proxies. X-Forwarded-For is read from
+// the peer towards the client and the first IP which isn't a trusted proxy is the client, so an IP prepended by
+// the client itself is never used. The peer is the client when it isn't a trusted proxy
+func ExtractIPFromXFFHeader(trusted ...*net.IPNet) IPExtractor {
+	isTrusted := func(ip net.IP) bool {
+		for _, n := range trusted {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(req *http.Request) string {
+		client := peerIP(req)
+		if ip := net.ParseIP(client); ip == nil || !isTrusted(ip) {
+			return client
+		}
+
+		forwarded := strings.Split(strings.Join(req.Header[echo.HeaderXForwardedFor], ","), ",")
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+			if ip == nil {
+				break
+			}
+
+			client = ip.String()
+			if !isTrusted(ip) {
+				break
+			}
+		}
+
+		return client
+	}
+}
+
+// ClientIP returns a middleware resolving the IP of the client with the extractor, see RealIP.
+// It must be used before the middlewares reading the client IP
+func ClientIP(extract IPExtractor) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(clientIPKey, extract(c.Request()))
+			return next(c)
+		}
+	}
+}
+
+// RealIP returns the client IP resolved by ClientIP, or the peer IP without it.
+// Unlike echo.Context.RealIP, X-Forwarded-For and X-Real-IP sent by the client aren't trusted
+func RealIP(c echo.Context) string {
+	if ip, ok := c.Get(clientIPKey).(string); ok {
+		return ip
+	}
+
+	return peerIP(c.Request())
+}
+
+func peerIP(req *http.Request) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return ip
+}
@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+)
+
+func TestClientIP(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		extractor    middleware.IPExtractor
+		remoteAddr   string
+		forwardedFor string
+		expectedIP   string
+	}{
+		"without extractor": {
+			remoteAddr:   "203.0.113.7:1234",
+			forwardedFor: "198.51.100.1",
+			expectedIP:   "203.0.113.7",
+		},
+		"direct ignores forwarded for": {
+			extractor:    middleware.ExtractIPDirect(),
+			remoteAddr:   "203.0.113.7:1234",
+			forwardedFor: "198.51.100.1",
+			expectedIP:   "203.0.113.7",
+		},
+		"forwarded by trusted proxy": {
+			extractor:    middleware.ExtractIPFromXFFHeader(proxies),
+			remoteAddr:   "10.0.0.2:1234",
+			forwardedFor: "198.51.100.1, 10.0.0.3",
+			expectedIP:   "198.51.100.1",
+		},
+		"ip prepended by the client is ignored": {
+			extractor:    middleware.ExtractIPFromXFFHeader(proxies),
+			remoteAddr:   "10.0.0.2:1234",
+			forwardedFor: "192.0.2.9, 198.51.100.1",
+			expectedIP:   "198.51.100.1",
+		},
+		"forwarded for of untrusted peer is ignored": {
+			extractor:    middleware.ExtractIPFromXFFHeader(proxies),
+			remoteAddr:   "203.0.113.7:1234",
+			forwardedFor: "198.51.100.1",
+			expectedIP:   "203.0.113.7",
+		},
+		"trusted proxy without forwarded for": {
+			extractor:  middleware.ExtractIPFromXFFHeader(proxies),
+			remoteAddr: "10.0.0.2:1234",
+			expectedIP: "10.0.0.2",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+			if tc.extractor != nil {
+				e.Use(middleware.ClientIP(tc.extractor))
+			}
+			e.GET("/ip", func(c echo.Context) error {
+				return c.String(http.StatusOK, middleware.RealIP(c))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.forwardedFor != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tc.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedIP, rec.Body.String())
+		})
+	}
+}
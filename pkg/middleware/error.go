@@ -2,19 +2,23 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/friendsofgo/errors"
 	"github.com/labstack/echo/v4"
 
 	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/problem"
 )
 
 type stackTracer interface {
 	StackTrace() errors.StackTrace
 }
 
-// ErrorMiddleware returns an error with response http status code
+// ErrorMiddleware returns an error with response http status code, its message is the problem of the error
+// and the internal error is kept for logging
 func ErrorMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -23,22 +27,99 @@ func ErrorMiddleware() echo.MiddlewareFunc {
 				return nil
 			}
 
-			err = errors.Cause(err)
+			cause := errors.Cause(err)
 
-			if _, ok := err.(domain.ConstraintError); ok {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			// errors of echo, e.g. an unknown route, already have their status
+			if he, ok := cause.(*echo.HTTPError); ok {
+				return he
 			}
 
-			switch err {
+			switch e := cause.(type) {
+			case domain.ValidationError:
+				p := problem.New(http.StatusBadRequest, problem.CodeValidationFailed, "request is not valid")
+				p.Violations = e.Violations
+				return newHTTPError(p, err)
+			case domain.ConstraintError:
+				return newHTTPError(problem.New(http.StatusBadRequest, "", e.Error()), err)
+			}
+
+			switch cause {
 			case context.DeadlineExceeded, context.Canceled:
-				return echo.NewHTTPError(http.StatusRequestTimeout, err.Error())
+				return newHTTPError(problem.New(http.StatusRequestTimeout, "", cause.Error()), err)
+			case domain.ErrUnauthorized:
+				return newHTTPError(problem.New(http.StatusUnauthorized, "", cause.Error()), err)
+			case domain.ErrForbidden:
+				return newHTTPError(problem.New(http.StatusForbidden, "", cause.Error()), err)
 			case domain.ErrNotFound:
-				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+				return newHTTPError(problem.New(http.StatusNotFound, "", cause.Error()), err)
+			case domain.ErrNotAcceptable:
+				return newHTTPError(problem.New(http.StatusNotAcceptable, "", cause.Error()), err)
+			case domain.ErrTooManyRequests:
+				return newHTTPError(problem.New(http.StatusTooManyRequests, "", cause.Error()), err)
+			case domain.ErrIdempotencyKeyInUse:
+				return newHTTPError(problem.New(http.StatusConflict, problem.CodeIdempotencyKeyInUse, cause.Error()), err)
+			case domain.ErrIdempotencyKeyReused:
+				return newHTTPError(problem.New(http.StatusUnprocessableEntity, problem.CodeIdempotencyKeyReused, cause.Error()), err)
 			case domain.ErrNotModified:
 				return c.NoContent(http.StatusNotModified)
 			}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			return newHTTPError(problem.New(http.StatusInternalServerError, "", err.Error()), err)
+		}
+	}
+}
+
+func newHTTPError(p problem.Problem, internal error) *echo.HTTPError {
+	return &echo.HTTPError{
+		Code:     p.Status,
+		Message:  p,
+		Internal: internal,
+	}
+}
+
+// ProblemHandler is the echo.HTTPErrorHandler responding application/problem+json.
+// The detail of a server error is replaced by problem.InternalDetail and the error is logged with the request id
+func ProblemHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	he, ok := err.(*echo.HTTPError)
+	if !ok {
+		he = &echo.HTTPError{Code: http.StatusInternalServerError, Message: err.Error(), Internal: err}
+	}
+
+	p, ok := he.Message.(problem.Problem)
+	if !ok {
+		p = problem.New(he.Code, "", fmt.Sprint(he.Message))
+	}
+
+	req := c.Request()
+	p.Instance = req.URL.Path
+	p.RequestID = logger.RequestIDFromContext(req.Context())
+
+	if p.Status >= http.StatusInternalServerError {
+		internal := he.Internal
+		if internal == nil {
+			internal = he
 		}
+
+		log := logger.FromContext(req.Context())
+		if st, ok := internal.(stackTracer); ok {
+			log = log.WithField("stack", fmt.Sprintf("%+v", st.StackTrace()))
+		}
+		log.Errorf("%s %s failed: %v", req.Method, req.URL.Path, internal)
+
+		p.Detail = problem.InternalDetail
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, problem.ContentType)
+	if req.Method == http.MethodHead {
+		err = c.NoContent(p.Status)
+	} else {
+		err = c.JSON(p.Status, p)
+	}
+	if err != nil {
+		logger.FromContext(req.Context()).Errorf("failed to write problem: %v", err)
 	}
 }
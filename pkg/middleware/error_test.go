@@ -0,0 +1,107 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/problem"
+)
+
+func TestProblemHandler(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = middleware.ProblemHandler
+	e.Use(middleware.RequestID())
+	e.Use(middleware.ErrorMiddleware())
+
+	e.POST("/users", func(c echo.Context) error {
+		return domain.ValidationError{Violations: []domain.FieldViolation{
+			{Field: "email", Rule: "required", Message: "email is required"},
+			{Field: "roles", Rule: "required", Message: "roles is required"},
+		}}
+	})
+	e.GET("/departments/:id", func(c echo.Context) error {
+		return errors.Wrap(domain.ErrNotFound, "failed get a department")
+	})
+	e.GET("/employees", func(c echo.Context) error {
+		return errors.New("dial tcp 10.0.0.1:3306: connection refused")
+	})
+
+	tests := map[string]struct {
+		method          string
+		target          string
+		expectedStatus  int
+		expectedCode    string
+		expectedDetail  string
+		expectedInvalid []string
+	}{
+		"validation failed": {
+			method:          http.MethodPost,
+			target:          "/users",
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    problem.CodeValidationFailed,
+			expectedDetail:  "request is not valid",
+			expectedInvalid: []string{"email", "roles"},
+		},
+		"domain error": {
+			method:         http.MethodGet,
+			target:         "/departments/1",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   problem.CodeNotFound,
+			expectedDetail: domain.ErrNotFound.Error(),
+		},
+		"internal error": {
+			method:         http.MethodGet,
+			target:         "/employees",
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   problem.CodeInternal,
+			expectedDetail: problem.InternalDetail,
+		},
+		"unknown route": {
+			method:         http.MethodGet,
+			target:         "/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   problem.CodeNotFound,
+			expectedDetail: "Not Found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, nil)
+			req.Header.Set(echo.HeaderXRequestID, "req-1")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Equal(t, problem.ContentType, rec.Header().Get(echo.HeaderContentType))
+			require.NotContains(t, rec.Body.String(), "connection refused")
+
+			var p problem.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+			require.Equal(t, problem.TypePrefix+tc.expectedCode, p.Type)
+			require.Equal(t, http.StatusText(tc.expectedStatus), p.Title)
+			require.Equal(t, tc.expectedStatus, p.Status)
+			require.Equal(t, tc.expectedCode, p.Code)
+			require.Equal(t, tc.expectedDetail, p.Detail)
+			require.Equal(t, tc.target, p.Instance)
+			require.Equal(t, "req-1", p.RequestID)
+
+			fields := make([]string, 0)
+			for _, v := range p.Violations {
+				fields = append(fields, v.Field)
+			}
+			if tc.expectedInvalid == nil {
+				tc.expectedInvalid = []string{}
+			}
+			require.Equal(t, tc.expectedInvalid, fields)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+// Idempotency headers, see https://datatracker.ietf.org/doc/draft-ietf-httpapi-idempotency-key-header
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	// idempotencyStorageTimeout bounds storing a response after the request context is done
+	idempotencyStorageTimeout = 5 * time.Second
+)
+
+// replayedHeaders are the response headers stored with the response body
+var replayedHeaders = []string{echo.HeaderContentType, echo.HeaderLocation, "ETag"}
+
+// IdempotencyConfig is the configuration of the idempotency middleware
+type IdempotencyConfig struct {
+	// Repository stores the keys and the responses
+	Repository domain.IdempotencyRepository
+	// TTL is the window a key is replayed in
+	TTL time.Duration
+	// PendingTimeout is the time after which a request which never completed, e.g. of a crashed instance,
+	// is abandoned so its key can be retried
+	PendingTimeout time.Duration
+	// MaxBodySize is the max size in bytes of a request body, the body is read in memory to hash it.
+	// A larger body is rejected with 413 Request Entity Too Large, zero disables the limit
+	MaxBodySize int64
+	// Skipper skips the idempotency of a request
+	Skipper Skipper
+}
+
+// Idempotency returns a middleware making the POST requests with an Idempotency-Key header idempotent.
+// The first response of a key is stored and replayed with Idempotent-Replayed header on retry,
+// a key retried with another payload is rejected with domain.ErrIdempotencyKeyReused
+// and a key retried while its request is in progress with domain.ErrIdempotencyKeyInUse.
+// The keys are scoped to the tenant, the caller and the path so the middleware must be used after Authentication.
+// Failed requests, i.e. errors and 5xx responses, aren't stored so they can be retried
+func Idempotency(cfg IdempotencyConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			key := req.Header.Get(HeaderIdempotencyKey)
+			if req.Method != http.MethodPost || key == "" || (cfg.Skipper != nil && cfg.Skipper(c)) {
+				return next(c)
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				return domain.ConstraintErrorf("%s header must be at most %d characters", HeaderIdempotencyKey, maxIdempotencyKeyLength)
+			}
+
+			if cfg.MaxBodySize > 0 {
+				req.Body = http.MaxBytesReader(c.Response(), req.Body, cfg.MaxBodySize)
+			}
+
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				if strings.Contains(err.Error(), "request body too large") {
+					return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must be at most %d bytes", cfg.MaxBodySize))
+				}
+				return domain.ConstraintErrorf("request body is not valid")
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			ctx := req.Context()
+			rec := domain.IdempotencyRecord{
+				Scope:       idempotencyScope(c),
+				Key:         key,
+				RequestHash: requestHash(req, body),
+				CreatedTime: time.Now(),
+				ExpiresTime: time.Now().Add(cfg.TTL),
+			}
+
+			stored, err := cfg.Repository.Get(ctx, rec.Scope, rec.Key)
+			switch {
+			case err == domain.ErrNotFound:
+			case err != nil:
+				return errors.Wrap(err, "failed get an idempotency record")
+			case stored.ExpiresTime.Before(rec.CreatedTime) ||
+				(!stored.Completed() && cfg.PendingTimeout > 0 && stored.CreatedTime.Add(cfg.PendingTimeout).Before(rec.CreatedTime)):
+				// an expired or abandoned key is used again
+				if err = cfg.Repository.Delete(ctx, rec.Scope, rec.Key); err != nil {
+					return errors.Wrap(err, "failed delete an idempotency record")
+				}
+			case stored.RequestHash != rec.RequestHash:
+				return domain.ErrIdempotencyKeyReused
+			case !stored.Completed():
+				return domain.ErrIdempotencyKeyInUse
+			default:
+				return replay(c, stored)
+			}
+
+			if err = cfg.Repository.Reserve(ctx, &rec); err != nil {
+				return err
+			}
+
+			res := c.Response()
+			recorder := &bodyRecorder{ResponseWriter: res.Writer}
+			res.Writer = recorder
+			err = next(c)
+			res.Writer = recorder.ResponseWriter
+
+			// the response is stored even when the request context is done, the client will retry it
+			storeCtx, cancel := context.WithTimeout(logger.NewContext(context.Background(), logger.FromContext(ctx)), idempotencyStorageTimeout)
+			defer cancel()
+
+			if err != nil || res.Status >= http.StatusInternalServerError || !res.Committed {
+				if er := cfg.Repository.Delete(storeCtx, rec.Scope, rec.Key); er != nil {
+					logger.FromContext(ctx).Errorf("failed to release idempotency key %s: %v", rec.Key, er)
+				}
+				return err
+			}
+
+			rec.StatusCode = res.Status
+			rec.Header = http.Header{}
+			for _, h := range replayedHeaders {
+				if v := res.Header().Get(h); v != "" {
+					rec.Header.Set(h, v)
+				}
+			}
+			rec.Body = recorder.body.Bytes()
+
+			if er := cfg.Repository.Complete(storeCtx, rec); er != nil {
+				logger.FromContext(ctx).Errorf("failed to store the response of idempotency key %s: %v", rec.Key, er)
+			}
+
+			return nil
+		}
+	}
+}
+
+// idempotencyScope isolates the keys of the tenants, the callers and the paths
+func idempotencyScope(c echo.Context) string {
+	req := c.Request()
+	caller := "ip:" + RealIP(c)
+	if p, ok := domain.PrincipalFromContext(req.Context()); ok {
+		caller = "sub:" + p.Subject
+	}
+
+	return domain.TenantFromContext(req.Context()) + "|" + caller + "|" + req.Method + " " + req.URL.Path
+}
+
+// requestHash hashes the query and the body of the request
+func requestHash(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.URL.RawQuery))
+	h.Write([]byte{0})
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay writes the stored response
+func replay(c echo.Context, rec domain.IdempotencyRecord) error {
+	res := c.Response()
+	for h, values := range rec.Header {
+		for _, v := range values {
+			res.Header().Add(h, v)
+		}
+	}
+	res.Header().Set(HeaderIdempotentReplayed, strconv.FormatBool(true))
+
+	res.WriteHeader(rec.StatusCode)
+	_, err := res.Write(rec.Body)
+	return err
+}
+
+// bodyRecorder copies the response body
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
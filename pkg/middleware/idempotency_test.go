@@ -0,0 +1,238 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/problem"
+)
+
+// memoryIdempotencyRepository keeps the idempotency records in memory
+type memoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyRepository() *memoryIdempotencyRepository {
+	return &memoryIdempotencyRepository{records: map[string]domain.IdempotencyRecord{}}
+}
+
+func (m *memoryIdempotencyRepository) Reserve(ctx context.Context, r *domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.records[r.Scope+r.Key]; ok {
+		return domain.ErrIdempotencyKeyInUse
+	}
+	m.records[r.Scope+r.Key] = *r
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) Get(ctx context.Context, scope, key string) (domain.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.records[scope+key]
+	if !ok {
+		return r, domain.ErrNotFound
+	}
+	return r, nil
+}
+
+func (m *memoryIdempotencyRepository) Complete(ctx context.Context, r domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[r.Scope+r.Key] = r
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) Delete(ctx context.Context, scope, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, scope+key)
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotency(t *testing.T) {
+	newServer := func(repo domain.IdempotencyRepository) (*echo.Echo, *int) {
+		created := 0
+
+		e := echo.New()
+		e.HTTPErrorHandler = middleware.ProblemHandler
+		e.Use(middleware.ErrorMiddleware())
+		e.Use(middleware.Idempotency(middleware.IdempotencyConfig{
+			Repository:     repo,
+			TTL:            time.Hour,
+			PendingTimeout: time.Minute,
+			MaxBodySize:    64,
+			Skipper: func(c echo.Context) bool {
+				return strings.HasPrefix(c.Request().URL.Path, "/auth/")
+			},
+		}))
+		e.POST("/auth/login", func(c echo.Context) error {
+			created++
+			return c.JSON(http.StatusOK, map[string]int{"token": created})
+		})
+		e.POST("/departments", func(c echo.Context) error {
+			created++
+			if strings.Contains(c.QueryParam("fail"), "true") {
+				return c.NoContent(http.StatusServiceUnavailable)
+			}
+			c.Response().Header().Set(echo.HeaderLocation, "/departments/1")
+			return c.JSON(http.StatusCreated, map[string]int{"created": created})
+		})
+
+		return e, &created
+	}
+
+	do := func(e *echo.Echo, target, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(middleware.HeaderIdempotencyKey, key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+
+		first := do(e, "/departments", "key-1", `{"name":"Engineering"}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+		require.Empty(t, first.Header().Get(middleware.HeaderIdempotentReplayed))
+
+		retry := do(e, "/departments", "key-1", `{"name":"Engineering"}`)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.Equal(t, "true", retry.Header().Get(middleware.HeaderIdempotentReplayed))
+		require.Equal(t, "/departments/1", retry.Header().Get(echo.HeaderLocation))
+		require.Equal(t, first.Body.String(), retry.Body.String())
+		require.Equal(t, 1, *created)
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+
+		do(e, "/departments", "", `{"name":"Engineering"}`)
+		do(e, "/departments", "", `{"name":"Engineering"}`)
+		require.Equal(t, 2, *created)
+	})
+
+	t.Run("reused with another payload", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+
+		require.Equal(t, http.StatusCreated, do(e, "/departments", "key-1", `{"name":"Engineering"}`).Code)
+
+		rec := do(e, "/departments", "key-1", `{"name":"Finance"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		require.Contains(t, rec.Body.String(), problem.CodeIdempotencyKeyReused)
+		require.Equal(t, 1, *created)
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		repo := newMemoryIdempotencyRepository()
+		e, created := newServer(repo)
+
+		first := do(e, "/departments", "key-1", `{"name":"Engineering"}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+
+		// the record is pending again as if the first request didn't complete yet
+		for k, r := range repo.records {
+			r.StatusCode = 0
+			repo.records[k] = r
+		}
+
+		rec := do(e, "/departments", "key-1", `{"name":"Engineering"}`)
+		require.Equal(t, http.StatusConflict, rec.Code)
+		require.Contains(t, rec.Body.String(), problem.CodeIdempotencyKeyInUse)
+		require.Equal(t, 1, *created)
+	})
+
+	t.Run("abandoned and expired keys", func(t *testing.T) {
+		repo := newMemoryIdempotencyRepository()
+		e, created := newServer(repo)
+
+		require.Equal(t, http.StatusCreated, do(e, "/departments", "key-1", `{"name":"Engineering"}`).Code)
+		for k, r := range repo.records {
+			r.StatusCode = 0
+			r.CreatedTime = time.Now().Add(-2 * time.Minute)
+			repo.records[k] = r
+		}
+		require.Equal(t, http.StatusCreated, do(e, "/departments", "key-1", `{"name":"Engineering"}`).Code)
+
+		for k, r := range repo.records {
+			r.ExpiresTime = time.Now().Add(-time.Second)
+			repo.records[k] = r
+		}
+		require.Equal(t, http.StatusCreated, do(e, "/departments", "key-1", `{"name":"Finance"}`).Code)
+		require.Equal(t, 3, *created)
+	})
+
+	t.Run("failed request is retried", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+
+		require.Equal(t, http.StatusServiceUnavailable, do(e, "/departments?fail=true", "key-1", `{}`).Code)
+		require.Equal(t, http.StatusServiceUnavailable, do(e, "/departments?fail=true", "key-1", `{}`).Code)
+		require.Equal(t, 2, *created)
+	})
+
+	t.Run("scoped to the path", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+		e.POST("/employees", func(c echo.Context) error {
+			*created++
+			return c.NoContent(http.StatusCreated)
+		})
+
+		do(e, "/departments", "key-1", `{}`)
+		rec := do(e, "/employees", "key-1", `{}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		require.Empty(t, rec.Header().Get(middleware.HeaderIdempotentReplayed))
+		require.Equal(t, 2, *created)
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+
+		rec := do(e, "/departments", strings.Repeat("k", 256), `{}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Equal(t, 0, *created)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+
+		rec := do(e, "/departments", "key-1", `{"name":"`+strings.Repeat("a", 64)+`"}`)
+		require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		require.Equal(t, 0, *created)
+
+		require.Equal(t, http.StatusCreated, do(e, "/departments", "key-1", `{"name":"Engineering"}`).Code)
+	})
+
+	t.Run("skipped path isn't replayed", func(t *testing.T) {
+		e, created := newServer(newMemoryIdempotencyRepository())
+
+		do(e, "/auth/login", "key-1", `{}`)
+		rec := do(e, "/auth/login", "key-1", `{}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Empty(t, rec.Header().Get(middleware.HeaderIdempotentReplayed))
+		require.JSONEq(t, `{"token":2}`, rec.Body.String())
+		require.Equal(t, 2, *created)
+	})
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+)
+
+// Rate limit headers, see https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// RateLimitConfig is the configuration of the rate limit middleware
+type RateLimitConfig struct {
+	// Store keeps the buckets
+	Store ratelimit.Store
+	// Default is the limit of the routes without override
+	Default ratelimit.Limit
+	// Routes overrides the limit of a route, it is keyed by the method and the route path e.g. "POST /departments".
+	// An overridden route has its own bucket
+	Routes map[string]ratelimit.Limit
+	// Skipper skips the limit of a request
+	Skipper Skipper
+}
+
+// RateLimit returns a middleware limiting the requests of each caller with a token bucket.
+// The caller is the authenticated principal, e.g. an API key or a user, or the client IP when it is not authenticated,
+// so the middleware must be used after Authentication.
+// Exceeded requests are rejected with domain.ErrTooManyRequests and Retry-After header.
+// The requests are allowed when the store fails, a broken store shouldn't take the API down
+func RateLimit(cfg RateLimitConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper != nil && cfg.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			route := req.Method + " " + c.Path()
+			limit, ok := cfg.Routes[route]
+			if !ok {
+				route = "*"
+				limit = cfg.Default
+			}
+
+			caller := "ip:" + RealIP(c)
+			if p, ok := domain.PrincipalFromContext(req.Context()); ok {
+				caller = "sub:" + p.Subject
+			}
+
+			res, err := cfg.Store.Take(req.Context(), route+"|"+caller, limit)
+			if err != nil {
+				logger.FromContext(req.Context()).Errorf("failed to take rate limit token of %s: %v", caller, err)
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+			h.Set(HeaderRateLimitReset, ceilSeconds(res.ResetAfter))
+
+			if !res.Allowed {
+				h.Set(HeaderRetryAfter, ceilSeconds(res.RetryAfter))
+				return domain.ErrTooManyRequests
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
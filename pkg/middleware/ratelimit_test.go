@@ -0,0 +1,145 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+)
+
+type storeFunc func(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error)
+
+func (f storeFunc) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return f(ctx, key, limit)
+}
+
+func (f storeFunc) Peek(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return f(ctx, key, limit)
+}
+
+func TestRateLimit(t *testing.T) {
+	newServer := func(store ratelimit.Store) *echo.Echo {
+		e := echo.New()
+		e.Use(middleware.ErrorMiddleware())
+		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				req := c.Request()
+				if sub := req.Header.Get("X-Subject"); sub != "" {
+					c.SetRequest(req.WithContext(domain.NewContextWithPrincipal(req.Context(), domain.Principal{Subject: sub})))
+				}
+				return next(c)
+			}
+		})
+		e.Use(middleware.RateLimit(middleware.RateLimitConfig{
+			Store:   store,
+			Default: ratelimit.Limit{Rate: 2, Period: time.Minute, Burst: 2},
+			Routes: map[string]ratelimit.Limit{
+				"POST /departments": {Rate: 1, Period: time.Minute, Burst: 1},
+			},
+			Skipper: func(c echo.Context) bool {
+				return c.Path() == "/ping"
+			},
+		}))
+
+		ok := func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		}
+		e.GET("/ping", ok)
+		e.GET("/departments", ok)
+		e.POST("/departments", ok)
+
+		return e
+	}
+
+	do := func(e *echo.Echo, method, target, subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if subject != "" {
+			req.Header.Set("X-Subject", subject)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+		e := newServer(ratelimit.NewMemoryStore())
+
+		rec := do(e, http.MethodGet, "/departments", "user-1")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "2", rec.Header().Get(middleware.HeaderRateLimitLimit))
+		require.Equal(t, "1", rec.Header().Get(middleware.HeaderRateLimitRemaining))
+		require.Equal(t, "30", rec.Header().Get(middleware.HeaderRateLimitReset))
+
+		require.Equal(t, http.StatusOK, do(e, http.MethodGet, "/departments", "user-1").Code)
+
+		rec = do(e, http.MethodGet, "/departments", "user-1")
+		require.Equal(t, http.StatusTooManyRequests, rec.Code)
+		require.Equal(t, "0", rec.Header().Get(middleware.HeaderRateLimitRemaining))
+		require.Equal(t, "30", rec.Header().Get(middleware.HeaderRetryAfter))
+
+		// other callers have their own bucket
+		require.Equal(t, http.StatusOK, do(e, http.MethodGet, "/departments", "user-2").Code)
+		require.Equal(t, http.StatusOK, do(e, http.MethodGet, "/departments", "").Code)
+	})
+
+	t.Run("route override", func(t *testing.T) {
+		e := newServer(ratelimit.NewMemoryStore())
+
+		rec := do(e, http.MethodPost, "/departments", "user-1")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "1", rec.Header().Get(middleware.HeaderRateLimitLimit))
+
+		require.Equal(t, http.StatusTooManyRequests, do(e, http.MethodPost, "/departments", "user-1").Code)
+		require.Equal(t, http.StatusOK, do(e, http.MethodGet, "/departments", "user-1").Code)
+	})
+
+	t.Run("client ip without principal", func(t *testing.T) {
+		e := newServer(ratelimit.NewMemoryStore())
+
+		require.Equal(t, http.StatusOK, do(e, http.MethodPost, "/departments", "").Code)
+		require.Equal(t, http.StatusTooManyRequests, do(e, http.MethodPost, "/departments", "").Code)
+	})
+
+	t.Run("spoofed forwarded headers share the bucket of the client ip", func(t *testing.T) {
+		e := newServer(ratelimit.NewMemoryStore())
+
+		for i, status := range []int{http.StatusOK, http.StatusTooManyRequests} {
+			req := httptest.NewRequest(http.MethodPost, "/departments", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set(echo.HeaderXForwardedFor, fmt.Sprintf("203.0.113.%d", i))
+			req.Header.Set(echo.HeaderXRealIP, fmt.Sprintf("198.51.100.%d", i))
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, status, rec.Code)
+		}
+	})
+
+	t.Run("skipped route", func(t *testing.T) {
+		e := newServer(ratelimit.NewMemoryStore())
+
+		for i := 0; i < 3; i++ {
+			rec := do(e, http.MethodGet, "/ping", "user-1")
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Empty(t, rec.Header().Get(middleware.HeaderRateLimitLimit))
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		e := newServer(storeFunc(func(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+			return ratelimit.Result{}, errors.New("connection refused")
+		}))
+
+		require.Equal(t, http.StatusOK, do(e, http.MethodGet, "/departments", "user-1").Code)
+	})
+}
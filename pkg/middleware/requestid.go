@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/segmentio/ksuid"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID returns a middleware correlating the log lines of a request.
+// The request ID is propagated from X-Request-ID header or generated when the header is missing or malformed,
+// it is returned in X-Request-ID header and carried in the request context with a logger, see logger.FromContext.
+// A line is logged when the request is served, the middleware must be the first one so the line has the final status
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID = ksuid.New().String()
+			}
+
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+			ctx := logger.NewContextWithRequestID(req.Context(), requestID)
+			c.SetRequest(req.WithContext(ctx))
+
+			start := time.Now()
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			res := c.Response()
+			logger.FromContext(ctx).WithFields(logger.Fields{
+				"method":     req.Method,
+				"route":      c.Path(),
+				"uri":        req.RequestURI,
+				"status":     res.Status,
+				"bytes_out":  res.Size,
+				"latency_ms": int64(time.Since(start) / time.Millisecond),
+				"remote_ip":  RealIP(c),
+			}).Infof("%s %s %d", req.Method, req.RequestURI, res.Status)
+
+			return nil
+		}
+	}
+}
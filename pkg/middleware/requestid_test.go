@@ -0,0 +1,102 @@
+package middleware_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+)
+
+func TestRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, logger.Configure(logger.Config{Format: "json", Output: &buf}))
+	defer logger.Configure(logger.Config{})
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(middleware.ErrorMiddleware())
+	e.GET("/employees/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger.FromContext(ctx).Infof("getting employee")
+		if c.Param("id") == "unknown" {
+			return domain.ErrNotFound
+		}
+		return c.String(http.StatusOK, logger.RequestIDFromContext(ctx))
+	})
+
+	tests := map[string]struct {
+		target            string
+		requestID         string
+		expectedStatus    int
+		expectedRequestID string
+	}{
+		"propagated request id": {
+			target:            "/employees/1",
+			requestID:         "request-1",
+			expectedStatus:    http.StatusOK,
+			expectedRequestID: "request-1",
+		},
+		"generated request id": {
+			target:         "/employees/1",
+			expectedStatus: http.StatusOK,
+		},
+		"malformed request id": {
+			target:         "/employees/1",
+			requestID:      "request 1\n",
+			expectedStatus: http.StatusOK,
+		},
+		"error response": {
+			target:            "/employees/unknown",
+			requestID:         "request-2",
+			expectedStatus:    http.StatusNotFound,
+			expectedRequestID: "request-2",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.requestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, tc.requestID)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			requestID := rec.Header().Get(echo.HeaderXRequestID)
+			require.NotEmpty(t, requestID)
+			if tc.expectedRequestID != "" {
+				require.Equal(t, tc.expectedRequestID, requestID)
+			} else {
+				require.NotEqual(t, tc.requestID, requestID)
+			}
+
+			lines := make([]map[string]interface{}, 0)
+			scanner := bufio.NewScanner(&buf)
+			for scanner.Scan() {
+				line := map[string]interface{}{}
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+				lines = append(lines, line)
+			}
+
+			require.Len(t, lines, 2)
+			for _, line := range lines {
+				require.Equal(t, requestID, line["request_id"])
+			}
+			require.Equal(t, "getting employee", lines[0]["msg"])
+			require.Equal(t, float64(tc.expectedStatus), lines[1]["status"])
+			require.Equal(t, "/employees/:id", lines[1]["route"])
+		})
+	}
+}
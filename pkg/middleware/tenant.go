@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// HeaderTenantID is the header selecting the tenant of a request
+const HeaderTenantID = "X-Tenant-ID"
+
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`)
+
+// TenantConfig is the configuration of the tenant middleware
+type TenantConfig struct {
+	// Default is the tenant of the callers which aren't bound to a tenant, e.g. the tokens of an
+	// identity provider without tenant claim. They are forbidden when it's empty
+	Default string
+}
+
+// Tenant returns a middleware putting the tenant of the caller into the request context, see domain.TenantFromContext.
+// A caller bound to a tenant by its token or API key always uses that tenant and is forbidden to select another one
+// with the header. Only a super-admin selects the tenant with the header, other unbound callers use the default tenant.
+// The header of an unauthenticated request is ignored, e.g. login binds the caller to the tenant of the user
+func Tenant(cfg TenantConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			p, ok := domain.PrincipalFromContext(req.Context())
+			if !ok {
+				return next(c)
+			}
+
+			header := req.Header.Get(HeaderTenantID)
+			if header != "" && !tenantIDPattern.MatchString(header) {
+				return domain.ConstraintErrorf("%s header must be 1 to 50 letters, digits, '-' or '_'", HeaderTenantID)
+			}
+
+			var tenantID string
+			switch {
+			case p.TenantID != "":
+				tenantID = p.TenantID
+			case p.HasRole(domain.RoleSuperAdmin):
+				if tenantID = header; tenantID == "" {
+					tenantID = cfg.Default
+				}
+				if tenantID == "" {
+					return domain.ConstraintErrorf("%s header is required", HeaderTenantID)
+				}
+			case cfg.Default != "":
+				tenantID = cfg.Default
+			default:
+				return domain.ErrForbidden
+			}
+
+			if header != "" && header != tenantID {
+				return domain.ErrForbidden
+			}
+
+			c.SetRequest(req.WithContext(domain.NewContextWithTenant(req.Context(), tenantID)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+)
+
+func TestTenant(t *testing.T) {
+	superAdmin := domain.Principal{Subject: "operator", Roles: []domain.Role{domain.RoleSuperAdmin}}
+
+	tests := map[string]struct {
+		principal      *domain.Principal
+		defaultTenant  string
+		tenantHeader   string
+		expectedStatus int
+		expectedTenant string
+	}{
+		"unauthenticated caller": {
+			expectedStatus: http.StatusOK,
+			expectedTenant: domain.DefaultTenantID,
+		},
+		"header of unauthenticated caller is ignored": {
+			tenantHeader:   "acme",
+			expectedStatus: http.StatusOK,
+			expectedTenant: domain.DefaultTenantID,
+		},
+		"tenant from token": {
+			principal:      &domain.Principal{Subject: "user-1", TenantID: "acme"},
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		"same tenant in token and header": {
+			principal:      &domain.Principal{Subject: "user-1", TenantID: "acme"},
+			tenantHeader:   "acme",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		"other tenant in header": {
+			principal:      &domain.Principal{Subject: "user-1", TenantID: "acme"},
+			tenantHeader:   "globex",
+			expectedStatus: http.StatusForbidden,
+		},
+		"tenant from api key": {
+			principal:      &domain.Principal{Subject: "apikey:key-1", TenantID: "acme"},
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		"api key selecting other tenant": {
+			principal:      &domain.Principal{Subject: "apikey:key-1", TenantID: "acme"},
+			tenantHeader:   "globex",
+			expectedStatus: http.StatusForbidden,
+		},
+		"unbound caller without default tenant": {
+			principal:      &domain.Principal{Subject: "oidc-user"},
+			expectedStatus: http.StatusForbidden,
+		},
+		"unbound caller selecting a tenant without default tenant": {
+			principal:      &domain.Principal{Subject: "oidc-user"},
+			tenantHeader:   "acme",
+			expectedStatus: http.StatusForbidden,
+		},
+		"unbound caller with default tenant": {
+			principal:      &domain.Principal{Subject: "oidc-user"},
+			defaultTenant:  domain.DefaultTenantID,
+			expectedStatus: http.StatusOK,
+			expectedTenant: domain.DefaultTenantID,
+		},
+		"unbound caller selecting other tenant than default tenant": {
+			principal:      &domain.Principal{Subject: "oidc-user"},
+			defaultTenant:  domain.DefaultTenantID,
+			tenantHeader:   "acme",
+			expectedStatus: http.StatusForbidden,
+		},
+		"super-admin selecting a tenant": {
+			principal:      &superAdmin,
+			tenantHeader:   "acme",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		"super-admin without header uses default tenant": {
+			principal:      &superAdmin,
+			defaultTenant:  domain.DefaultTenantID,
+			expectedStatus: http.StatusOK,
+			expectedTenant: domain.DefaultTenantID,
+		},
+		"super-admin without header and default tenant": {
+			principal:      &superAdmin,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid tenant header": {
+			principal:      &superAdmin,
+			tenantHeader:   "acme corp",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(middleware.ErrorMiddleware())
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if tc.principal != nil {
+						req := c.Request()
+						c.SetRequest(req.WithContext(domain.NewContextWithPrincipal(req.Context(), *tc.principal)))
+					}
+					return next(c)
+				}
+			})
+			e.Use(middleware.Tenant(middleware.TenantConfig{Default: tc.defaultTenant}))
+			e.GET("/employees", func(c echo.Context) error {
+				return c.String(http.StatusOK, domain.TenantFromContext(c.Request().Context()))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+			if tc.tenantHeader != "" {
+				req.Header.Set(middleware.HeaderTenantID, tc.tenantHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedTenant != "" {
+				require.Equal(t, tc.expectedTenant, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TimeoutConfig is the configuration of the timeout middleware
+type TimeoutConfig struct {
+	// Default is the deadline of the routes without override, zero disables it
+	Default time.Duration
+	// Routes overrides the deadline of a route, it is keyed by the method and the route path e.g. "GET /employees"
+	Routes map[string]time.Duration
+	// Skipper skips the deadline of a request, e.g. of a stream
+	Skipper Skipper
+}
+
+// Timeout returns a middleware setting the deadline of the request context.
+// The repositories give up when the deadline is exceeded and ErrorMiddleware responds 408 Request Timeout,
+// a handler which ignores the deadline and succeeds after it is also answered with context.DeadlineExceeded
+func Timeout(cfg TimeoutConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper != nil && cfg.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			timeout, ok := cfg.Routes[req.Method+" "+c.Path()]
+			if !ok {
+				timeout = cfg.Default
+			}
+			if timeout <= 0 {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err == nil && !c.Response().Committed && ctx.Err() == context.DeadlineExceeded {
+				err = ctx.Err()
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+)
+
+func TestTimeout(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.ErrorMiddleware())
+	e.Use(middleware.Timeout(middleware.TimeoutConfig{
+		Default: 20 * time.Millisecond,
+		Routes: map[string]time.Duration{
+			"GET /reports": time.Second,
+		},
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/events"
+		},
+	}))
+
+	// query waits like a repository call for the deadline of the request
+	query := func(c echo.Context) error {
+		select {
+		case <-c.Request().Context().Done():
+			return c.Request().Context().Err()
+		case <-time.After(50 * time.Millisecond):
+			return c.NoContent(http.StatusOK)
+		}
+	}
+	e.GET("/employees", query)
+	e.GET("/reports", query)
+	e.GET("/events", func(c echo.Context) error {
+		if _, ok := c.Request().Context().Deadline(); ok {
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+	// ignoring ignores the deadline and succeeds after it
+	e.GET("/ignoring", func(c echo.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+
+	tests := map[string]struct {
+		target       string
+		expectedCode int
+	}{
+		"default deadline exceeded": {target: "/employees", expectedCode: http.StatusRequestTimeout},
+		"route deadline":            {target: "/reports", expectedCode: http.StatusOK},
+		"skipped":                   {target: "/events", expectedCode: http.StatusOK},
+		"deadline ignored":          {target: "/ignoring", expectedCode: http.StatusRequestTimeout},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			require.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestTimeoutDisabled(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.Timeout(middleware.TimeoutConfig{}))
+	e.GET("/employees", func(c echo.Context) error {
+		if _, ok := c.Request().Context().Deadline(); ok {
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/employees", nil).WithContext(context.Background()))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
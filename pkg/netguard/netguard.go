@@ -0,0 +1,105 @@
+// Package netguard keeps the requests sent to the URLs given by the users, e.g. the webhooks,
+// away from the loopback, link-local and private networks of the deployment.
+package netguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/friendsofgo/errors"
+)
+
+// ErrForbiddenAddress is returned for an address which isn't public
+var ErrForbiddenAddress = errors.New("netguard: address is not public")
+
+// nonPublic are the networks which aren't reachable on the internet besides loopback, link-local and multicast
+var nonPublic = parseCIDRs(
+	"0.0.0.0/8",      // this network
+	"10.0.0.0/8",     // private
+	"100.64.0.0/10",  // carrier-grade NAT
+	"172.16.0.0/12",  // private
+	"192.0.0.0/24",   // IETF protocol assignments
+	"192.168.0.0/16", // private
+	"198.18.0.0/15",  // benchmarking
+	"240.0.0.0/4",    // reserved
+	"fc00::/7",       // unique local
+	"64:ff9b:1::/48", // local-use NAT64
+	"2001:db8::/32",  // documentation
+)
+
+// lookupIPAddr resolves a host, it is replaced by the tests
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// IsPublic reports whether the IP is a public unicast address
+func IsPublic(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, n := range nonPublic {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckHost returns ErrForbiddenAddress when the host is or resolves to an address which isn't public.
+// A host which can't be resolved yet is allowed, the address is checked again when it is dialed, see Control
+func CheckHost(ctx context.Context, host string) (err error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return check(ip)
+	}
+
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		if err = check(addr.IP); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Control is a net.Dialer Control rejecting the connections to the addresses which aren't public.
+// The address is checked once it is resolved, right before connecting, so a host rebound to
+// an internal address after it was checked is rejected too
+func Control(network, address string, c syscall.RawConn) (err error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return
+	}
+
+	return check(net.ParseIP(host))
+}
+
+func check(ip net.IP) error {
+	if !IsPublic(ip) {
+		return errors.Wrapf(ErrForbiddenAddress, "%s", ip)
+	}
+
+	return nil
+}
+
+func parseCIDRs(cidrs ...string) (nets []*net.IPNet) {
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("netguard: invalid cidr %s", cidr))
+		}
+		nets = append(nets, n)
+	}
+
+	return
+}
@@ -0,0 +1,80 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":        true,
+		"2606:4700::1111":      true,
+		"0.0.0.0":              false,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"100.64.0.1":           false,
+		"169.254.169.254":      false,
+		"172.16.0.1":           false,
+		"172.32.0.1":           true,
+		"192.168.1.1":          false,
+		"224.0.0.1":            false,
+		"255.255.255.255":      false,
+		"::":                   false,
+		"::1":                  false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:10.0.0.1":      false,
+		"fd00::1":              false,
+		"fe80::1":              false,
+		"ff02::1":              false,
+		"::ffff:93.184.216.34": true,
+	}
+
+	for ip, expected := range tests {
+		t.Run(ip, func(t *testing.T) {
+			require.Equal(t, expected, IsPublic(net.ParseIP(ip)))
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	defer func(lookup func(context.Context, string) ([]net.IPAddr, error)) { lookupIPAddr = lookup }(lookupIPAddr)
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "partner.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		case "internal.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.1")}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	tests := map[string]error{
+		"93.184.216.34":        nil,
+		"127.0.0.1":            ErrForbiddenAddress,
+		"partner.example.com":  nil,
+		"internal.example.com": ErrForbiddenAddress,
+		"unknown.example.com":  nil,
+	}
+
+	for host, expected := range tests {
+		t.Run(host, func(t *testing.T) {
+			err := CheckHost(context.Background(), host)
+			if expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, expected, errors.Cause(err))
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	require.NoError(t, Control("tcp4", "93.184.216.34:443", nil))
+	require.Error(t, Control("tcp4", "127.0.0.1:80", nil))
+	require.Error(t, Control("tcp6", "[fe80::1]:80", nil))
+}
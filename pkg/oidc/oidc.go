@@ -0,0 +1,328 @@
+// Package oidc verifies ID and access tokens issued by an OpenID Connect provider.
+// The discovery document and JWKS are loaded from http(s) URLs, or from file:// URLs in development and tests.
+// Keys are cached and reloaded when they are stale or a token is signed by an unknown key, so key rotation is picked up.
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+// Default values of the config
+const (
+	DefaultRolesClaim      = "roles"
+	DefaultEmployeeIDClaim = "employee_id"
+	DefaultTenantIDClaim   = "tenant_id"
+	DefaultCacheTTL        = time.Hour
+	DefaultRefreshInterval = time.Minute
+)
+
+// Config is the configuration of a verifier, Issuer is required
+type Config struct {
+	// Issuer is the expected iss claim and the base of the default discovery URL
+	Issuer string
+	// DiscoveryURL defaults to Issuer + /.well-known/openid-configuration
+	DiscoveryURL string
+	// JWKSURL overrides jwks_uri of the discovery document, discovery is skipped when it is set
+	JWKSURL string
+	// Audience is the expected aud claim, it is not checked when empty
+	Audience string
+	// RolesClaim is the claim holding the roles, a dotted path reads a nested claim e.g. realm_access.roles
+	RolesClaim string
+	// RoleMapping maps the values of RolesClaim to roles, the values are used as they are when it is empty
+	RoleMapping map[string]domain.Role
+	// EmployeeIDClaim is the claim holding the employee id, a dotted path reads a nested claim
+	EmployeeIDClaim string
+	// TenantIDClaim is the claim holding the tenant, a dotted path reads a nested claim
+	TenantIDClaim string
+	// CacheTTL is how long the keys are used before they are reloaded
+	CacheTTL time.Duration
+	// RefreshInterval is the minimum interval between two reloads, it limits reloads caused by unknown keys
+	RefreshInterval time.Duration
+	// HTTPClient fetches the discovery document and JWKS
+	HTTPClient *http.Client
+}
+
+type discovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// Verifier verifies tokens of an OpenID Connect provider
+type Verifier struct {
+	cfg     Config
+	jwksURL string
+	parser  *jwt.Parser
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	fetchedTime time.Time
+	fetchMu     sync.Mutex
+}
+
+// New creates a new verifier, the discovery document and the keys are loaded immediately
+func New(cfg Config) (v *Verifier, err error) {
+	if cfg.Issuer == "" {
+		err = errors.New("oidc: issuer is required")
+		return
+	}
+
+	if cfg.DiscoveryURL == "" {
+		cfg.DiscoveryURL = strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = DefaultRolesClaim
+	}
+	if cfg.EmployeeIDClaim == "" {
+		cfg.EmployeeIDClaim = DefaultEmployeeIDClaim
+	}
+	if cfg.TenantIDClaim == "" {
+		cfg.TenantIDClaim = DefaultTenantIDClaim
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = DefaultRefreshInterval
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	v = &Verifier{
+		cfg:     cfg,
+		jwksURL: cfg.JWKSURL,
+		parser:  jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}), jwt.WithJSONNumber()),
+		keys:    map[string]*rsa.PublicKey{},
+	}
+
+	if v.jwksURL == "" {
+		var doc discovery
+		if err = v.fetch(cfg.DiscoveryURL, &doc); err != nil {
+			err = errors.Wrap(err, "can't load discovery document")
+			return
+		}
+
+		if doc.Issuer != cfg.Issuer {
+			err = fmt.Errorf("oidc: discovery issuer %q doesn't match %q", doc.Issuer, cfg.Issuer)
+			return
+		}
+
+		if doc.JWKSURI == "" {
+			err = errors.New("oidc: discovery document has no jwks_uri")
+			return
+		}
+		v.jwksURL = doc.JWKSURI
+	}
+
+	if err = v.refresh(); err != nil {
+		err = errors.Wrap(err, "can't load jwks")
+	}
+
+	return
+}
+
+// Issuer returns the issuer of the tokens accepted by the verifier
+func (v *Verifier) Issuer() string {
+	return v.cfg.Issuer
+}
+
+// Verify verifies the token and returns its subject, roles, employee, tenant and claims.
+// Any verification failure is returned as jwtauth.ErrInvalidToken with the reason wrapped in the message
+func (v *Verifier) Verify(token string) (p domain.Principal, err error) {
+	claims := jwt.MapClaims{}
+	if _, err = v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		err = errors.Wrap(jwtauth.ErrInvalidToken, err.Error())
+		return
+	}
+
+	if !claims.VerifyIssuer(v.cfg.Issuer, true) {
+		err = errors.Wrap(jwtauth.ErrInvalidToken, "unexpected issuer")
+		return
+	}
+
+	if v.cfg.Audience != "" && !claims.VerifyAudience(v.cfg.Audience, true) {
+		err = errors.Wrap(jwtauth.ErrInvalidToken, "unexpected audience")
+		return
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		err = errors.Wrap(jwtauth.ErrInvalidToken, "missing expiration")
+		return
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		err = errors.Wrap(jwtauth.ErrInvalidToken, "missing subject")
+		return
+	}
+
+	employeeID, _ := claim(claims, v.cfg.EmployeeIDClaim).(string)
+	tenantID, _ := claim(claims, v.cfg.TenantIDClaim).(string)
+	p = domain.Principal{
+		Subject:    subject,
+		Roles:      v.roles(claim(claims, v.cfg.RolesClaim)),
+		EmployeeID: employeeID,
+		TenantID:   tenantID,
+		Claims:     claims,
+	}
+
+	return
+}
+
+// roles maps the roles claim, unmapped values are dropped when a mapping is configured
+func (v *Verifier) roles(value interface{}) (roles []domain.Role) {
+	roles = jwtauth.RolesFromClaim(value)
+	if len(v.cfg.RoleMapping) == 0 {
+		return
+	}
+
+	mapped := make([]domain.Role, 0, len(roles))
+	for _, r := range roles {
+		if role, ok := v.cfg.RoleMapping[string(r)]; ok {
+			mapped = append(mapped, role)
+		}
+	}
+
+	return mapped
+}
+
+// claim reads a claim by a dotted path
+func claim(claims map[string]interface{}, path string) (value interface{}) {
+	value = map[string]interface{}(claims)
+	for _, name := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[name]
+	}
+
+	return
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (key interface{}, err error) {
+	kid, _ := token.Header["kid"].(string)
+
+	v.mu.RLock()
+	k, ok := v.keys[kid]
+	stale := time.Since(v.fetchedTime) >= v.cfg.CacheTTL
+	v.mu.RUnlock()
+
+	if ok && !stale {
+		return k, nil
+	}
+
+	if er := v.refresh(); er != nil {
+		logger.L().Errorf("failed to reload oidc jwks: %v", er)
+	}
+
+	v.mu.RLock()
+	k, ok = v.keys[kid]
+	v.mu.RUnlock()
+
+	if !ok {
+		err = fmt.Errorf("unknown key %q", kid)
+		return
+	}
+
+	return k, nil
+}
+
+// refresh reloads the keys, it is skipped when the keys were loaded less than RefreshInterval ago.
+// The current keys are kept when reloading fails
+func (v *Verifier) refresh() (err error) {
+	v.fetchMu.Lock()
+	defer v.fetchMu.Unlock()
+
+	v.mu.RLock()
+	fetchedTime := v.fetchedTime
+	v.mu.RUnlock()
+
+	if !fetchedTime.IsZero() && time.Since(fetchedTime) < v.cfg.RefreshInterval {
+		return
+	}
+
+	var set jwtauth.JWKS
+	if err = v.fetch(v.jwksURL, &set); err != nil {
+		return
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use == "enc" {
+			continue
+		}
+
+		key, er := jwk.Key()
+		if er != nil {
+			if errors.Cause(er) == jwtauth.ErrUnsupportedKey {
+				continue
+			}
+			return er
+		}
+
+		if k, ok := key.(*rsa.PublicKey); ok {
+			keys[jwk.Kid] = k
+		}
+	}
+
+	if len(keys) == 0 {
+		return errors.New("oidc: jwks has no rsa signing key")
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedTime = time.Now()
+	v.mu.Unlock()
+
+	return
+}
+
+// fetch decodes the JSON document of an http(s) or file URL
+func (v *Verifier) fetch(rawURL string, dst interface{}) (err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+
+	var raw []byte
+	switch u.Scheme {
+	case "file":
+		if raw, err = ioutil.ReadFile(u.Path); err != nil {
+			return
+		}
+	case "http", "https":
+		res, er := v.cfg.HTTPClient.Get(rawURL)
+		if er != nil {
+			return er
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("oidc: unexpected status %d of %s", res.StatusCode, rawURL)
+		}
+
+		if raw, err = ioutil.ReadAll(res.Body); err != nil {
+			return
+		}
+	default:
+		return fmt.Errorf("oidc: unsupported url scheme %q", u.Scheme)
+	}
+
+	return json.Unmarshal(raw, dst)
+}
@@ -0,0 +1,220 @@
+package oidc_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/oidc"
+)
+
+const issuer = "https://sso.example.com"
+
+func jwk(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "RSA",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func marshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+
+	raw, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return raw
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oidc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rotatedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksFile := filepath.Join(dir, "jwks.json")
+	require.NoError(t, ioutil.WriteFile(jwksFile, marshal(t, map[string]interface{}{
+		"keys": []map[string]string{
+			jwk("key-1", key),
+			{"kid": "ec-1", "kty": "EC", "crv": "P-256"},
+		},
+	}), 0600))
+
+	discoveryFile := filepath.Join(dir, "openid-configuration")
+	require.NoError(t, ioutil.WriteFile(discoveryFile, marshal(t, map[string]string{
+		"issuer":   issuer,
+		"jwks_uri": "file://" + jwksFile,
+	}), 0600))
+
+	verifier, err := oidc.New(oidc.Config{
+		Issuer:          issuer,
+		DiscoveryURL:    "file://" + discoveryFile,
+		Audience:        "employee",
+		RolesClaim:      "realm_access.roles",
+		RoleMapping:     map[string]domain.Role{"hr": domain.RoleHRAdmin, "staff": domain.RoleViewer},
+		EmployeeIDClaim: "employee_id",
+		RefreshInterval: time.Nanosecond,
+	})
+	require.NoError(t, err)
+	require.Equal(t, issuer, verifier.Issuer())
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":          "sso|1",
+			"iss":          issuer,
+			"aud":          []string{"employee", "payroll"},
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"realm_access": map[string]interface{}{"roles": []string{"hr", "staff", "offline_access"}},
+			"employee_id":  "1S9XpJCvJbt1plvU36tAcJWS2ZW",
+			"tenant_id":    "acme",
+		}
+	}
+
+	otherIssuerClaims := validClaims()
+	otherIssuerClaims["iss"] = "https://evil.example.com"
+
+	otherAudienceClaims := validClaims()
+	otherAudienceClaims["aud"] = "payroll"
+
+	expiredClaims := validClaims()
+	expiredClaims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	noExpirationClaims := validClaims()
+	delete(noExpirationClaims, "exp")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	hs256, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		token       string
+		expectedErr bool
+	}{
+		"success":               {token: sign(t, key, "key-1", validClaims())},
+		"unexpected issuer":     {token: sign(t, key, "key-1", otherIssuerClaims), expectedErr: true},
+		"unexpected audience":   {token: sign(t, key, "key-1", otherAudienceClaims), expectedErr: true},
+		"expired token":         {token: sign(t, key, "key-1", expiredClaims), expectedErr: true},
+		"missing expiration":    {token: sign(t, key, "key-1", noExpirationClaims), expectedErr: true},
+		"wrong key":             {token: sign(t, otherKey, "key-1", validClaims()), expectedErr: true},
+		"unknown key id":        {token: sign(t, otherKey, "key-9", validClaims()), expectedErr: true},
+		"unsupported algorithm": {token: hs256, expectedErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := verifier.Verify(tc.token)
+			if tc.expectedErr {
+				require.Error(t, err)
+				require.Equal(t, jwtauth.ErrInvalidToken, errors.Cause(err))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "sso|1", p.Subject)
+			require.Equal(t, []domain.Role{domain.RoleHRAdmin, domain.RoleViewer}, p.Roles)
+			require.Equal(t, "1S9XpJCvJbt1plvU36tAcJWS2ZW", p.EmployeeID)
+			require.Equal(t, "acme", p.TenantID)
+		})
+	}
+
+	t.Run("key rotation", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(jwksFile, marshal(t, map[string]interface{}{
+			"keys": []map[string]string{jwk("key-2", rotatedKey)},
+		}), 0600))
+
+		_, err := verifier.Verify(sign(t, rotatedKey, "key-2", validClaims()))
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(sign(t, key, "key-1", validClaims()))
+		require.Equal(t, jwtauth.ErrInvalidToken, errors.Cause(err))
+	})
+}
+
+func TestNew(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Write(marshal(t, map[string]string{"issuer": serverURL, "jwks_uri": serverURL + "/keys"}))
+		case "/keys":
+			w.Write(marshal(t, map[string]interface{}{"keys": []map[string]string{jwk("key-1", key)}}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	t.Run("success with discovery over http", func(t *testing.T) {
+		verifier, err := oidc.New(oidc.Config{Issuer: serverURL})
+		require.NoError(t, err)
+
+		p, err := verifier.Verify(sign(t, key, "key-1", jwt.MapClaims{
+			"sub":   "sso|1",
+			"iss":   serverURL,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": []string{"viewer"},
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []domain.Role{domain.RoleViewer}, p.Roles)
+	})
+
+	t.Run("success with jwks url", func(t *testing.T) {
+		_, err := oidc.New(oidc.Config{Issuer: "https://sso.example.com", JWKSURL: serverURL + "/keys"})
+		require.NoError(t, err)
+	})
+
+	t.Run("discovery issuer mismatch", func(t *testing.T) {
+		_, err := oidc.New(oidc.Config{Issuer: "https://sso.example.com", DiscoveryURL: serverURL + "/.well-known/openid-configuration"})
+		require.Error(t, err)
+	})
+
+	t.Run("discovery is not found", func(t *testing.T) {
+		_, err := oidc.New(oidc.Config{Issuer: serverURL + "/realms/other"})
+		require.Error(t, err)
+	})
+
+	t.Run("without issuer", func(t *testing.T) {
+		_, err := oidc.New(oidc.Config{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+// Package problem describes the error responses as RFC 7807 problem details
+package problem
+
+import (
+	"net/http"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// ContentType is the media type of a problem
+const ContentType = "application/problem+json"
+
+// TypePrefix prefixes the code of a problem to build its type
+const TypePrefix = "urn:problem-type:employee:"
+
+// Stable codes of the problems, the clients may rely on them
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeRequestTimeout       = "request_timeout"
+	CodeRequestTooLarge      = "request_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeConflict             = "conflict"
+	CodeUnprocessableEntity  = "unprocessable_entity"
+	CodeIdempotencyKeyInUse  = "idempotency_key_in_use"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeInternal             = "internal_error"
+	CodeUnavailable          = "service_unavailable"
+)
+
+// InternalDetail replaces the detail of a server error so the internal error isn't leaked
+const InternalDetail = "an unexpected error occurred, report the request_id to investigate it"
+
+// Problem is a RFC 7807 problem detail extended with a stable code, the request id and the field violations
+type Problem struct {
+	Type       string                  `json:"type"`
+	Title      string                  `json:"title"`
+	Status     int                     `json:"status"`
+	Detail     string                  `json:"detail,omitempty"`
+	Instance   string                  `json:"instance,omitempty"`
+	Code       string                  `json:"code"`
+	RequestID  string                  `json:"request_id,omitempty"`
+	Violations []domain.FieldViolation `json:"violations,omitempty"`
+}
+
+// New creates a problem of the status, the code defaults to the one of the status
+func New(status int, code, detail string) Problem {
+	if code == "" {
+		code = CodeOf(status)
+	}
+
+	return Problem{
+		Type:   TypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// CodeOf returns the code of a status
+func CodeOf(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusNotAcceptable:
+		return CodeNotAcceptable
+	case http.StatusRequestTimeout:
+		return CodeRequestTimeout
+	case http.StatusRequestEntityTooLarge:
+		return CodeRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessableEntity
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
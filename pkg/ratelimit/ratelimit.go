@@ -0,0 +1,182 @@
+// Package ratelimit implements token bucket rate limits.
+// A bucket holds up to Burst tokens and is refilled with Rate tokens every Period, each request takes a token.
+// The buckets are kept by a Store so they can be shared between instances with another implementation than MemoryStore.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket refilled with Rate tokens every Period, up to Burst tokens
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// ParseLimit parses a limit written as <rate>/<period> or <rate>/<period>:<burst>, e.g. 100/1m or 10/1s:20.
+// The burst is the rate when it is omitted
+func ParseLimit(s string) (l Limit, err error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		err = fmt.Errorf("ratelimit: limit %q must be <rate>/<period>", s)
+		return
+	}
+
+	period := parts[1]
+	burst := ""
+	if i := strings.Index(period, ":"); i >= 0 {
+		period, burst = period[:i], period[i+1:]
+	}
+
+	if l.Rate, err = strconv.Atoi(strings.TrimSpace(parts[0])); err != nil {
+		err = fmt.Errorf("ratelimit: rate of limit %q is not a number", s)
+		return
+	}
+
+	if l.Period, err = time.ParseDuration(strings.TrimSpace(period)); err != nil {
+		err = fmt.Errorf("ratelimit: period of limit %q is not a duration", s)
+		return
+	}
+
+	l.Burst = l.Rate
+	if burst != "" {
+		if l.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil {
+			err = fmt.Errorf("ratelimit: burst of limit %q is not a number", s)
+			return
+		}
+	}
+
+	if l.Rate <= 0 || l.Period <= 0 || l.Burst <= 0 {
+		err = fmt.Errorf("ratelimit: rate, period and burst of limit %q must be positive", s)
+	}
+
+	return
+}
+
+// perSecond is the refill rate of the bucket
+func (l Limit) perSecond() float64 {
+	return float64(l.Rate) / l.Period.Seconds()
+}
+
+// Result is the state of a bucket after a request took a token
+type Result struct {
+	// Allowed is false when the bucket is empty
+	Allowed bool
+	// Limit is the size of the bucket
+	Limit int
+	// Remaining is the number of tokens left in the bucket
+	Remaining int
+	// ResetAfter is the time until the bucket is full again
+	ResetAfter time.Duration
+	// RetryAfter is the time until a token is available, it is zero when the request is allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets, Take and Peek must be safe for concurrent use
+type Store interface {
+	// Take takes a token from the bucket of the key, the bucket is created full when it doesn't exist
+	Take(ctx context.Context, key string, limit Limit) (res Result, err error)
+	// Peek returns the state of the bucket of the key without taking a token,
+	// Allowed reports whether a token is available
+	Peek(ctx context.Context, key string, limit Limit) (res Result, err error)
+}
+
+type bucket struct {
+	tokens      float64
+	updatedTime time.Time
+	limit       Limit
+}
+
+// MemoryStore keeps the buckets in memory, the limits are per instance.
+// Full buckets are removed every sweep interval so idle clients don't use memory
+type MemoryStore struct {
+	mu            sync.Mutex
+	buckets       map[string]*bucket
+	sweepInterval time.Duration
+	sweptTime     time.Time
+	now           func() time.Time
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:       map[string]*bucket{},
+		sweepInterval: time.Minute,
+		now:           time.Now,
+	}
+}
+
+// Take takes a token from the bucket of the key
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (res Result, err error) {
+	return s.use(key, limit, 1), nil
+}
+
+// Peek returns the state of the bucket of the key
+func (s *MemoryStore) Peek(ctx context.Context, key string, limit Limit) (res Result, err error) {
+	return s.use(key, limit, 0), nil
+}
+
+// use takes the tokens from the bucket of the key when it holds a token
+func (s *MemoryStore) use(key string, limit Limit, tokens float64) (res Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Burst), updatedTime: now, limit: limit}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	res.Limit = limit.Burst
+	if b.tokens >= 1 {
+		b.tokens -= tokens
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / limit.perSecond())
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	res.ResetAfter = seconds((float64(limit.Burst) - b.tokens) / limit.perSecond())
+
+	return
+}
+
+// sweep removes the buckets which are full again
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.sweptTime) < s.sweepInterval {
+		return
+	}
+	s.sweptTime = now
+
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedTime).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.perSecond())
+	b.updatedTime = now
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,94 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/ratelimit"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := map[string]struct {
+		limit       string
+		expected    ratelimit.Limit
+		expectedErr bool
+	}{
+		"success":            {limit: "100/1m", expected: ratelimit.Limit{Rate: 100, Period: time.Minute, Burst: 100}},
+		"success with burst": {limit: "10/1s:20", expected: ratelimit.Limit{Rate: 10, Period: time.Second, Burst: 20}},
+		"missing period":     {limit: "100", expectedErr: true},
+		"invalid rate":       {limit: "many/1m", expectedErr: true},
+		"invalid period":     {limit: "100/minute", expectedErr: true},
+		"invalid burst":      {limit: "100/1m:many", expectedErr: true},
+		"zero rate":          {limit: "0/1m", expectedErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			l, err := ratelimit.ParseLimit(tc.limit)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, l)
+		})
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Rate: 1, Period: 100 * time.Millisecond, Burst: 2}
+
+	for i := 1; i >= 0; i-- {
+		res, err := store.Take(context.Background(), "user-1", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, 2, res.Limit)
+		require.Equal(t, i, res.Remaining)
+	}
+
+	res, err := store.Take(context.Background(), "user-1", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.True(t, res.RetryAfter > 0 && res.RetryAfter <= 100*time.Millisecond)
+	require.True(t, res.ResetAfter > 100*time.Millisecond && res.ResetAfter <= 200*time.Millisecond)
+
+	t.Run("other key has its own bucket", func(t *testing.T) {
+		res, err := store.Take(context.Background(), "user-2", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	})
+
+	t.Run("bucket is refilled", func(t *testing.T) {
+		time.Sleep(res.RetryAfter + 10*time.Millisecond)
+
+		res, err := store.Take(context.Background(), "user-1", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	})
+}
+
+func TestMemoryStorePeek(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Rate: 1, Period: time.Minute, Burst: 1}
+
+	for i := 0; i < 2; i++ {
+		res, err := store.Peek(context.Background(), "user-1", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, 1, res.Remaining)
+	}
+
+	_, err := store.Take(context.Background(), "user-1", limit)
+	require.NoError(t, err)
+
+	res, err := store.Peek(context.Background(), "user-1", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+	require.True(t, res.RetryAfter > 0)
+}
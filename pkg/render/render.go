@@ -0,0 +1,189 @@
+// Package render writes handler responses in the representation negotiated from the Accept header.
+// Supported media types are application/json, text/csv, application/xml, application/x-ndjson and application/msgpack.
+package render
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// List of supported media types
+const (
+	MIMEApplicationJSON    = "application/json"
+	MIMETextCSV            = "text/csv"
+	MIMEApplicationXML     = "application/xml"
+	MIMEApplicationNDJSON  = "application/x-ndjson"
+	MIMEApplicationMsgpack = "application/msgpack"
+)
+
+// MediaTypes is the list of supported media types ordered by preference
+var MediaTypes = []string{
+	MIMEApplicationJSON,
+	MIMETextCSV,
+	MIMEApplicationXML,
+	MIMEApplicationNDJSON,
+	MIMEApplicationMsgpack,
+}
+
+type encoder func(w http.ResponseWriter, name string, value interface{}) error
+
+var encoders = map[string]encoder{
+	MIMEApplicationJSON:    encodeJSON,
+	MIMETextCSV:            encodeCSV,
+	MIMEApplicationXML:     encodeXML,
+	MIMEApplicationNDJSON:  encodeNDJSON,
+	MIMEApplicationMsgpack: encodeMsgpack,
+}
+
+// Render writes the value with the media type negotiated from the Accept header.
+// Name is the singular resource name used as XML element name, e.g. employee, a list is wrapped in its plural form.
+// It returns domain.ErrNotAcceptable when none of the accepted media types is supported
+func Render(c echo.Context, code int, name string, value interface{}) (err error) {
+	mediaType, err := Negotiate(c.Request().Header.Get(echo.HeaderAccept))
+	if err != nil {
+		return
+	}
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, mediaType+"; charset=UTF-8")
+	if mediaType == MIMEApplicationMsgpack {
+		w.Header().Set(echo.HeaderContentType, mediaType)
+	}
+	w.Header().Add("Vary", echo.HeaderAccept)
+	w.WriteHeader(code)
+
+	return encoders[mediaType](w, name, value)
+}
+
+// Negotiate returns the supported media type with the highest quality in the Accept header.
+// The quality of a media type is the one of its most specific range, so application/json;q=0, */*
+// excludes application/json. An empty Accept header negotiates to application/json
+func Negotiate(accept string) (mediaType string, err error) {
+	if strings.TrimSpace(accept) == "" {
+		mediaType = MIMEApplicationJSON
+		return
+	}
+
+	type candidate struct {
+		mediaType   string
+		quality     float64
+		specificity int
+		order       int
+	}
+
+	matches := map[string]candidate{}
+	for i, part := range strings.Split(accept, ",") {
+		accepted, params, er := mime.ParseMediaType(strings.TrimSpace(part))
+		if er != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, er = strconv.ParseFloat(q, 64); er != nil {
+				continue
+			}
+		}
+
+		for j, supported := range MediaTypes {
+			if !matchMediaType(accepted, supported) {
+				continue
+			}
+
+			c := candidate{supported, quality, specificity(accepted), i*len(MediaTypes) + j}
+			if match, ok := matches[supported]; !ok || c.specificity > match.specificity {
+				matches[supported] = c
+			}
+		}
+	}
+
+	candidates := make([]candidate, 0, len(matches))
+	for _, c := range matches {
+		if c.quality > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+
+	if len(candidates) == 0 {
+		err = domain.ErrNotAcceptable
+		return
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].quality != candidates[j].quality {
+			return candidates[i].quality > candidates[j].quality
+		}
+		return candidates[i].order < candidates[j].order
+	})
+
+	mediaType = candidates[0].mediaType
+	return
+}
+
+// specificity ranks a media range, a media type is more specific than type/* which is more specific than */*
+func specificity(accepted string) int {
+	switch {
+	case accepted == "*/*":
+		return 0
+	case strings.HasSuffix(accepted, "/*"):
+		return 1
+	}
+
+	return 2
+}
+
+func matchMediaType(accepted, supported string) bool {
+	if accepted == "*/*" || accepted == supported {
+		return true
+	}
+
+	if strings.HasSuffix(accepted, "/*") {
+		return strings.HasPrefix(supported, strings.TrimSuffix(accepted, "*"))
+	}
+
+	return false
+}
+
+func encodeJSON(w http.ResponseWriter, name string, value interface{}) error {
+	return json.NewEncoder(w).Encode(value)
+}
+
+func encodeNDJSON(w http.ResponseWriter, name string, value interface{}) (err error) {
+	items := []interface{}{value}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		items = make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	flusher, canFlush := w.(http.Flusher)
+	for _, item := range items {
+		if err = enc.Encode(item); err != nil {
+			return
+		}
+
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+
+	return
+}
+
+func encodeMsgpack(w http.ResponseWriter, name string, value interface{}) error {
+	enc := msgpack.NewEncoder(w)
+	enc.UseJSONTag(true)
+	return enc.Encode(value)
+}
@@ -0,0 +1,170 @@
+package render_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/render"
+)
+
+type department struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type employee struct {
+	ID          string     `json:"id"`
+	FirstName   string     `json:"first_name"`
+	Department  department `json:"department"`
+	CreatedTime time.Time  `json:"created_time"`
+}
+
+var employees = []employee{
+	{
+		ID:          "1",
+		FirstName:   "Emilia",
+		Department:  department{ID: "10", Name: "Marketing, Sales"},
+		CreatedTime: time.Date(2019, 10, 13, 8, 4, 5, 0, time.UTC),
+	},
+	{
+		ID:          "2",
+		FirstName:   "Casey <3",
+		Department:  department{ID: "20", Name: "Human Resources"},
+		CreatedTime: time.Date(2019, 10, 14, 8, 4, 5, 0, time.UTC),
+	},
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]struct {
+		accept      string
+		expected    string
+		expectedErr error
+	}{
+		"empty accept":           {accept: "", expected: render.MIMEApplicationJSON},
+		"any media type":         {accept: "*/*", expected: render.MIMEApplicationJSON},
+		"csv":                    {accept: "text/csv", expected: render.MIMETextCSV},
+		"text wildcard":          {accept: "text/*", expected: render.MIMETextCSV},
+		"with quality":           {accept: "application/xml;q=0.5, application/x-ndjson", expected: render.MIMEApplicationNDJSON},
+		"with unsupported type":  {accept: "text/html, application/msgpack;q=0.1", expected: render.MIMEApplicationMsgpack},
+		"not acceptable":         {accept: "text/html, application/json;q=0", expectedErr: domain.ErrNotAcceptable},
+		"excluded from any":      {accept: "application/json;q=0, */*", expected: render.MIMETextCSV},
+		"excluded from wildcard": {accept: "text/*, text/csv;q=0, application/xml;q=0.5", expected: render.MIMEApplicationXML},
+		"specific range wins":    {accept: "text/*;q=0, text/csv", expected: render.MIMETextCSV},
+		"every type excluded":    {accept: "*/*;q=0", expectedErr: domain.ErrNotAcceptable},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mediaType, err := render.Negotiate(tc.accept)
+			if tc.expectedErr != nil {
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, mediaType)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := map[string]struct {
+		accept              string
+		value               interface{}
+		expectedContentType string
+		expectedBody        string
+	}{
+		"json": {
+			accept:              render.MIMEApplicationJSON,
+			value:               employees[:1],
+			expectedContentType: "application/json; charset=UTF-8",
+			expectedBody:        `[{"id":"1","first_name":"Emilia","department":{"id":"10","name":"Marketing, Sales"},"created_time":"2019-10-13T08:04:05Z"}]` + "\n",
+		},
+		"csv": {
+			accept:              render.MIMETextCSV,
+			value:               employees,
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedBody: "id,first_name,department.id,department.name,created_time\n" +
+				"1,Emilia,10,\"Marketing, Sales\",2019-10-13T08:04:05Z\n" +
+				"2,Casey <3,20,Human Resources,2019-10-14T08:04:05Z\n",
+		},
+		"csv of empty list": {
+			accept:              render.MIMETextCSV,
+			value:               []employee{},
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedBody:        "id,first_name,department.id,department.name,created_time\n",
+		},
+		"csv with single resource": {
+			accept:              render.MIMETextCSV,
+			value:               employees[0].Department,
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedBody:        "id,name\n10,\"Marketing, Sales\"\n",
+		},
+		"xml": {
+			accept:              render.MIMEApplicationXML,
+			value:               employees[1:],
+			expectedContentType: "application/xml; charset=UTF-8",
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<employees><employee><id>2</id><first_name>Casey &lt;3</first_name>` +
+				`<department><id>20</id><name>Human Resources</name></department>` +
+				`<created_time>2019-10-14T08:04:05Z</created_time></employee></employees>`,
+		},
+		"ndjson": {
+			accept:              render.MIMEApplicationNDJSON,
+			value:               []department{employees[0].Department, employees[1].Department},
+			expectedContentType: "application/x-ndjson; charset=UTF-8",
+			expectedBody:        `{"id":"10","name":"Marketing, Sales"}` + "\n" + `{"id":"20","name":"Human Resources"}` + "\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+			req.Header.Set(echo.HeaderAccept, tc.accept)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			err := render.Render(c, http.StatusOK, "employee", tc.value)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tc.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+			require.Equal(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+
+	t.Run("msgpack", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+		req.Header.Set(echo.HeaderAccept, render.MIMEApplicationMsgpack)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+
+		err := render.Render(c, http.StatusOK, "employee", employees)
+		require.NoError(t, err)
+		require.Equal(t, render.MIMEApplicationMsgpack, rec.Header().Get(echo.HeaderContentType))
+
+		var res []map[string]interface{}
+		dec := msgpack.NewDecoder(bytes.NewReader(rec.Body.Bytes()))
+		require.NoError(t, dec.Decode(&res))
+		require.Len(t, res, 2)
+		require.Equal(t, "Emilia", res[0]["first_name"])
+		require.Equal(t, map[string]interface{}{"id": "20", "name": "Human Resources"}, res[1]["department"])
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+		req.Header.Set(echo.HeaderAccept, "text/html")
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+
+		err := render.Render(c, http.StatusOK, "employee", employees)
+		require.Equal(t, domain.ErrNotAcceptable, err)
+	})
+}
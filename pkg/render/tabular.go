@@ -0,0 +1,274 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// node is a json value which keeps the order of object keys, so CSV columns and XML elements
+// follow the order of struct fields instead of the sorted order of a decoded map
+type node struct {
+	keys   []string
+	fields map[string]*node
+	items  []*node
+	value  interface{}
+	object bool
+	array  bool
+}
+
+func toNode(value interface{}) (n *node, err error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	return decodeNode(dec)
+}
+
+func decodeNode(dec *json.Decoder) (n *node, err error) {
+	token, err := dec.Token()
+	if err != nil {
+		return
+	}
+
+	n = &node{}
+	switch token {
+	case json.Delim('{'):
+		n.object = true
+		n.fields = map[string]*node{}
+		for dec.More() {
+			var key json.Token
+			if key, err = dec.Token(); err != nil {
+				return
+			}
+
+			var child *node
+			if child, err = decodeNode(dec); err != nil {
+				return
+			}
+
+			n.keys = append(n.keys, key.(string))
+			n.fields[key.(string)] = child
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		n.array = true
+		n.items = make([]*node, 0)
+		for dec.More() {
+			var child *node
+			if child, err = decodeNode(dec); err != nil {
+				return
+			}
+			n.items = append(n.items, child)
+		}
+		_, err = dec.Token()
+	default:
+		n.value = token
+	}
+
+	return
+}
+
+// text returns the scalar value as text, object and array are written as compact json
+func (n *node) text() string {
+	if n.object || n.array {
+		var buf bytes.Buffer
+		n.writeJSON(&buf)
+		return buf.String()
+	}
+
+	if n.value == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", n.value)
+}
+
+func (n *node) writeJSON(buf *bytes.Buffer) {
+	switch {
+	case n.object:
+		buf.WriteByte('{')
+		for i, key := range n.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			raw, _ := json.Marshal(key)
+			buf.Write(raw)
+			buf.WriteByte(':')
+			n.fields[key].writeJSON(buf)
+		}
+		buf.WriteByte('}')
+	case n.array:
+		buf.WriteByte('[')
+		for i, item := range n.items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			item.writeJSON(buf)
+		}
+		buf.WriteByte(']')
+	default:
+		raw, _ := json.Marshal(n.value)
+		buf.Write(raw)
+	}
+}
+
+// flatten returns the leaf values of an object keyed by their dotted path, e.g. department.name
+func (n *node) flatten(prefix string, columns *[]string, row map[string]string) {
+	if !n.object {
+		*columns = append(*columns, prefix)
+		row[prefix] = n.text()
+		return
+	}
+
+	for _, key := range n.keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		n.fields[key].flatten(path, columns, row)
+	}
+}
+
+func encodeCSV(w http.ResponseWriter, name string, value interface{}) (err error) {
+	n, err := toNode(value)
+	if err != nil {
+		return
+	}
+
+	items := []*node{n}
+	if n.array {
+		items = n.items
+	}
+
+	columns, err := emptyColumns(value)
+	if err != nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	rows := make([]map[string]string, len(items))
+	for i, item := range items {
+		itemColumns := make([]string, 0)
+		rows[i] = map[string]string{}
+		item.flatten("", &itemColumns, rows[i])
+
+		for _, column := range itemColumns {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err = cw.Write(columns); err != nil {
+		return
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+
+		if err = cw.Write(record); err != nil {
+			return
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// emptyColumns returns the columns of the element of an empty list, so its CSV still has a header
+func emptyColumns(value interface{}) (columns []string, err error) {
+	columns = make([]string, 0)
+
+	v := reflect.ValueOf(value)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() > 0 {
+		return
+	}
+
+	n, err := toNode(reflect.Zero(v.Type().Elem()).Interface())
+	if err != nil || !n.object {
+		return
+	}
+
+	n.flatten("", &columns, map[string]string{})
+	return
+}
+
+func encodeXML(w http.ResponseWriter, name string, value interface{}) (err error) {
+	n, err := toNode(value)
+	if err != nil {
+		return
+	}
+
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return
+	}
+
+	enc := xml.NewEncoder(w)
+	if n.array {
+		root := xml.StartElement{Name: xml.Name{Local: name + "s"}}
+		if err = enc.EncodeToken(root); err != nil {
+			return
+		}
+
+		for _, item := range n.items {
+			if err = item.writeXML(enc, name); err != nil {
+				return
+			}
+		}
+
+		if err = enc.EncodeToken(root.End()); err != nil {
+			return
+		}
+
+		return enc.Flush()
+	}
+
+	if err = n.writeXML(enc, name); err != nil {
+		return
+	}
+
+	return enc.Flush()
+}
+
+func (n *node) writeXML(enc *xml.Encoder, name string) (err error) {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err = enc.EncodeToken(start); err != nil {
+		return
+	}
+
+	switch {
+	case n.object:
+		for _, key := range n.keys {
+			if err = n.fields[key].writeXML(enc, key); err != nil {
+				return
+			}
+		}
+	case n.array:
+		for _, item := range n.items {
+			if err = item.writeXML(enc, "item"); err != nil {
+				return
+			}
+		}
+	default:
+		if err = enc.EncodeToken(xml.CharData(n.text())); err != nil {
+			return
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
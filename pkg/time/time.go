@@ -75,3 +75,33 @@ func ConvertTimeWithTimeStamp(date time.Time) (finalDate time.Time, err error) {
 	}
 	return
 }
+
+// Location is the zone the times are stored in, a date only value is a day in it. It is UTC until it's configured
+var Location = time.UTC
+
+// ParseDateTime parses value either in RFC3339 or date only (2006-01-02) format, a date only value starts the day in Location
+func ParseDateTime(value string) (finalDate time.Time, err error) {
+	finalDate, err = time.Parse(time.RFC3339, value)
+	if err == nil {
+		return
+	}
+
+	finalDate, err = time.ParseInLocation("2006-01-02", value, Location)
+	return
+}
+
+// ParseDateTimeEnd parses the upper bound of an inclusive range like ParseDateTime,
+// a date only value is the last microsecond of the day, the precision of the stored times
+func ParseDateTimeEnd(value string) (finalDate time.Time, err error) {
+	finalDate, err = time.Parse(time.RFC3339, value)
+	if err == nil {
+		return
+	}
+
+	finalDate, err = time.ParseInLocation("2006-01-02", value, Location)
+	if err == nil {
+		finalDate = finalDate.AddDate(0, 0, 1).Add(-time.Microsecond)
+	}
+
+	return
+}
@@ -106,3 +106,75 @@ func TestConvertTimeWithTimeStamp(t *testing.T) {
 		require.EqualError(t, err, expectedErr.Error())
 	})
 }
+
+func TestParseDateTime(t *testing.T) {
+	t.Run("success with rfc3339", func(t *testing.T) {
+		expectedTime := time.Date(2019, 9, 21, 7, 0, 0, 0, time.UTC)
+
+		result, err := nTime.ParseDateTime("2019-09-21T07:00:00Z")
+		require.NoError(t, err)
+		require.True(t, expectedTime.Equal(result))
+	})
+
+	t.Run("success with date only", func(t *testing.T) {
+		expectedTime := time.Date(2019, 9, 21, 0, 0, 0, 0, time.UTC)
+
+		result, err := nTime.ParseDateTime("2019-09-21")
+		require.NoError(t, err)
+		require.Equal(t, expectedTime, result)
+	})
+
+	t.Run("success with date only in the configured location", func(t *testing.T) {
+		jakartaTimezone, err := time.LoadLocation("Asia/Jakarta")
+		require.NoError(t, err)
+
+		defer func(loc *time.Location) { nTime.Location = loc }(nTime.Location)
+		nTime.Location = jakartaTimezone
+
+		result, err := nTime.ParseDateTime("2019-09-21")
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2019, 9, 21, 0, 0, 0, 0, jakartaTimezone), result)
+		require.True(t, time.Date(2019, 9, 20, 17, 0, 0, 0, time.UTC).Equal(result))
+	})
+
+	t.Run("failed parsing time", func(t *testing.T) {
+		_, err := nTime.ParseDateTime("21-09-2019")
+		require.Error(t, err)
+	})
+}
+
+func TestParseDateTimeEnd(t *testing.T) {
+	t.Run("success with rfc3339", func(t *testing.T) {
+		expectedTime := time.Date(2019, 9, 21, 7, 0, 0, 0, time.UTC)
+
+		result, err := nTime.ParseDateTimeEnd("2019-09-21T07:00:00Z")
+		require.NoError(t, err)
+		require.True(t, expectedTime.Equal(result))
+	})
+
+	t.Run("success with date only", func(t *testing.T) {
+		expectedTime := time.Date(2019, 9, 21, 23, 59, 59, 999999000, time.UTC)
+
+		result, err := nTime.ParseDateTimeEnd("2019-09-21")
+		require.NoError(t, err)
+		require.Equal(t, expectedTime, result)
+	})
+
+	t.Run("success with date only in the configured location", func(t *testing.T) {
+		jakartaTimezone, err := time.LoadLocation("Asia/Jakarta")
+		require.NoError(t, err)
+
+		defer func(loc *time.Location) { nTime.Location = loc }(nTime.Location)
+		nTime.Location = jakartaTimezone
+
+		result, err := nTime.ParseDateTimeEnd("2019-09-21")
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2019, 9, 21, 23, 59, 59, 999999000, jakartaTimezone), result)
+		require.True(t, time.Date(2019, 9, 21, 16, 59, 59, 999999000, time.UTC).Equal(result))
+	})
+
+	t.Run("failed parsing time", func(t *testing.T) {
+		_, err := nTime.ParseDateTimeEnd("21-09-2019")
+		require.Error(t, err)
+	})
+}
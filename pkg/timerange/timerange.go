@@ -0,0 +1,29 @@
+// Package timerange reads the time range filters of the list endpoints from the query params
+package timerange
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// FromQuery parses the bounds of an inclusive time range from the query params, each one is either in RFC3339
+// or date only format and may be empty. A date only upper bound includes the whole day
+func FromQuery(c echo.Context, fromParam, toParam string) (timeRange domain.TimeRange, err error) {
+	if from := c.QueryParam(fromParam); from != "" {
+		if timeRange.From, err = ntime.ParseDateTime(from); err != nil {
+			err = domain.ConstraintErrorf("%s query-param is not valid. Got error when parsing value: %v", fromParam, err)
+			return
+		}
+	}
+
+	if to := c.QueryParam(toParam); to != "" {
+		if timeRange.To, err = ntime.ParseDateTimeEnd(to); err != nil {
+			err = domain.ConstraintErrorf("%s query-param is not valid. Got error when parsing value: %v", toParam, err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,60 @@
+package timerange_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/timerange"
+)
+
+func TestFromQuery(t *testing.T) {
+	tests := map[string]struct {
+		target        string
+		expected      domain.TimeRange
+		expectedError error
+	}{
+		"unbounded": {
+			target: "/employees",
+		},
+		"date only bounds include the last day": {
+			target: "/employees?createdFrom=2019-10-01&createdTo=2019-10-31",
+			expected: domain.TimeRange{
+				From: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2019, 10, 31, 23, 59, 59, 999999000, time.UTC),
+			},
+		},
+		"rfc3339 bounds": {
+			target: "/employees?createdFrom=2019-10-01T08:00:00Z&createdTo=2019-10-31T17:00:00Z",
+			expected: domain.TimeRange{
+				From: time.Date(2019, 10, 1, 8, 0, 0, 0, time.UTC),
+				To:   time.Date(2019, 10, 31, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		"invalid bound": {
+			target:        "/employees?createdTo=31-10-2019",
+			expectedError: domain.ConstraintErrorf(`createdTo query-param is not valid. Got error when parsing value: parsing time "31-10-2019" as "2006-01-02": cannot parse "31-10-2019" as "2006"`),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, tc.target, nil), httptest.NewRecorder())
+
+			res, err := timerange.FromQuery(c, "createdFrom", "createdTo")
+			if tc.expectedError != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, tc.expected.From.Equal(res.From))
+			require.True(t, tc.expected.To.Equal(res.To))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+// Middleware returns a middleware starting a server span of each request.
+// The trace context is extracted from traceparent header so the span continues the trace of the caller,
+// and trace_id is added to the request logger so the log lines are correlated to the trace.
+// It must be used after middleware.RequestID and middleware.ErrorMiddleware must be used after it,
+// the error is returned unchanged so the outer middlewares handle it
+func Middleware(serverName string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := c.Path()
+			ctx, span := otel.Tracer(InstrumentationName).Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(serverName, route, req)...),
+			)
+			defer span.End()
+
+			if sc := span.SpanContext(); sc.IsValid() {
+				ctx = logger.NewContext(ctx, logger.FromContext(ctx).WithField("trace_id", sc.TraceID().String()))
+			}
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			status := c.Response().Status
+			if err != nil {
+				span.RecordError(err)
+				status = http.StatusInternalServerError
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				}
+			}
+
+			// unknown paths are named alike so they don't create a span name each
+			if err == echo.ErrNotFound || err == echo.ErrMethodNotAllowed {
+				span.SetName(req.Method + " unmatched")
+			}
+
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+			code, msg := semconv.SpanStatusFromHTTPStatusCode(status)
+			if code == codes.Error {
+				span.SetStatus(code, msg)
+			}
+
+			return err
+		}
+	}
+}
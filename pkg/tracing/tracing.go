@@ -0,0 +1,120 @@
+// Package tracing sets up OpenTelemetry tracing and the helpers to start spans.
+// Spans are exported to stdout, to a file or to an OTLP collector, so tracing works offline.
+// The trace context is propagated with W3C traceparent and baggage headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/friendsofgo/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// InstrumentationName is the name of the tracer of the application
+const InstrumentationName = "github.com/milhamhidayat/golang-clean-code-v2"
+
+// Supported exporters
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Config is the configuration of tracing
+type Config struct {
+	// ServiceName is service.name resource attribute
+	ServiceName string
+	// Exporter is one of none, stdout, file or otlp, tracing is disabled when it is empty or none.
+	// The OTLP exporter is configured by the standard OTEL_EXPORTER_OTLP_* environment variables
+	Exporter string
+	// File is the path the spans are appended to by the file exporter
+	File string
+	// SampleRatio is the ratio of the sampled root spans, every root span is sampled when it is zero.
+	// The sampling decision of the parent is always respected
+	SampleRatio float64
+}
+
+// Init sets the global tracer provider and propagator.
+// The returned shutdown function flushes the pending spans and must be called before the application exits
+func Init(cfg Config) (shutdown func(ctx context.Context) error, err error) {
+	shutdown = func(ctx context.Context) error { return nil }
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		exporter, err = newFileExporter(cfg.File)
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(context.Background())
+	default:
+		err = fmt.Errorf("tracing: unsupported exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+func newFileExporter(path string) (exporter sdktrace.SpanExporter, err error) {
+	if path == "" {
+		err = fmt.Errorf("tracing: file is required by the file exporter")
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+
+	return stdouttrace.New(stdouttrace.WithWriter(f))
+}
+
+// Start starts a span of the application tracer as a child of the span of the context
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error of the span and ends it, a resource which is not found isn't an error of the span
+func End(span trace.Span, err error) {
+	if err != nil && errors.Cause(err) != domain.ErrNotFound {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Statement sets the SQL statement attributes of a database span
+func Statement(span trace.Span, query string) {
+	span.SetAttributes(semconv.DBSystemMySQL, semconv.DBStatementKey.String(query))
+}
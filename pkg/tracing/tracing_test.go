@@ -0,0 +1,142 @@
+package tracing_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/tracing"
+)
+
+// record sets a global tracer provider recording the ended spans
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	_, err := tracing.Init(tracing.Config{})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := record(t)
+
+	e := echo.New()
+	e.Use(tracing.Middleware("employee"))
+	e.Use(middleware.ErrorMiddleware())
+	e.GET("/employees/:id", func(c echo.Context) error {
+		ctx, span := tracing.Start(c.Request().Context(), "EmployeeService.Get")
+		tracing.End(span, nil)
+
+		if c.Param("id") == "broken" {
+			return errors.New("connection refused")
+		}
+
+		_, span = tracing.Start(ctx, "EmployeeRepository.Get")
+		tracing.Statement(span, "SELECT id FROM employees WHERE id = ?")
+		tracing.End(span, domain.ErrNotFound)
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("continues the trace of traceparent", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/employees/1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 3)
+
+		server := spans[2]
+		require.Equal(t, "GET /employees/:id", server.Name())
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+		require.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+		require.Equal(t, codes.Unset, server.Status().Code)
+
+		service, repository := spans[0], spans[1]
+		require.Equal(t, server.SpanContext().SpanID(), service.Parent().SpanID())
+		require.Equal(t, service.SpanContext().SpanID(), repository.Parent().SpanID())
+		require.Equal(t, codes.Unset, repository.Status().Code)
+
+		attrs := map[string]string{}
+		for _, kv := range repository.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		require.Equal(t, "mysql", attrs["db.system"])
+		require.Equal(t, "SELECT id FROM employees WHERE id = ?", attrs["db.statement"])
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		recorder = record(t)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/employees/broken", nil))
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		require.Equal(t, codes.Error, spans[1].Status().Code)
+	})
+
+	t.Run("unmatched route", func(t *testing.T) {
+		recorder = record(t)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		require.Equal(t, "GET unmatched", spans[0].Name())
+	})
+}
+
+func TestInit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("file exporter", func(t *testing.T) {
+		file := filepath.Join(dir, "spans.json")
+		shutdown, err := tracing.Init(tracing.Config{ServiceName: "employee", Exporter: tracing.ExporterFile, File: file})
+		require.NoError(t, err)
+
+		_, span := tracing.Start(context.Background(), "DepartmentRepository.Get")
+		tracing.End(span, nil)
+		require.NoError(t, shutdown(context.Background()))
+
+		raw, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		require.Contains(t, string(raw), "DepartmentRepository.Get")
+	})
+
+	t.Run("file exporter without file", func(t *testing.T) {
+		_, err := tracing.Init(tracing.Config{Exporter: tracing.ExporterFile})
+		require.Error(t, err)
+	})
+
+	t.Run("unsupported exporter", func(t *testing.T) {
+		_, err := tracing.Init(tracing.Config{Exporter: "zipkin"})
+		require.Error(t, err)
+	})
+}
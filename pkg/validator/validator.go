@@ -2,20 +2,93 @@ package validator
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
 )
 
-// Validate will validate struct based on tag
+// validate names the fields by their JSON names so the violations match the request body
+var validate = newValidate()
+
+func newValidate() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(sf reflect.StructField) string {
+		name := strings.SplitN(sf.Tag.Get("json"), ",", 2)[0]
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return sf.Name
+		}
+		return name
+	})
+
+	return v
+}
+
+// Validate will validate struct based on tag, every violation is returned at once as domain.ValidationError
 func Validate(data interface{}) error {
-	validate := validator.New()
+	err := validate.Struct(data)
+	if err == nil {
+		return nil
+	}
+
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	violations := make([]domain.FieldViolation, 0, len(errs))
+	for _, e := range errs {
+		// the namespace starts with the name of the validated struct
+		field := e.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+
+		rule := e.Tag()
+		if e.Param() != "" {
+			rule += "=" + e.Param()
+		}
+
+		violations = append(violations, domain.FieldViolation{
+			Field:   field,
+			Rule:    rule,
+			Message: message(field, e),
+		})
+	}
+
+	return domain.ValidationError{Violations: violations}
+}
+
+// message describes the violation of the common rules
+func message(field string, e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "url":
+		return fmt.Sprintf("%s must be a valid url", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", field, strings.Join(strings.Fields(e.Param()), ", "))
+	case "min", "max":
+		bound := "at least"
+		if e.Tag() == "max" {
+			bound = "at most"
+		}
 
-	if err := validate.Struct(data); err != nil {
-		for _, e := range err.(validator.ValidationErrors) {
-			newError := fmt.Errorf("error field validation for %s failed on the '%s' tag", e.Field(), e.Tag())
-			return newError
+		switch e.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters long", field, bound, e.Param())
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("%s must have %s %s items", field, bound, e.Param())
 		}
+		return fmt.Sprintf("%s must be %s %s", field, bound, e.Param())
 	}
 
-	return nil
+	return fmt.Sprintf("%s failed on the '%s' rule", field, e.Tag())
 }
@@ -0,0 +1,41 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, validator.Validate(domain.Department{Name: "Engineering"}))
+
+	err := validator.Validate(domain.User{
+		Email:    "john",
+		Password: "short",
+		Roles:    []domain.Role{domain.Role("viewer"), domain.Role("root")},
+	})
+	require.Error(t, err)
+
+	verr, ok := err.(domain.ValidationError)
+	require.True(t, ok)
+	require.Equal(t, []domain.FieldViolation{
+		{Field: "employee_id", Rule: "required", Message: "employee_id is required"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "password", Rule: "min=8", Message: "password must be at least 8 characters long"},
+		{Field: "roles[1]", Rule: "oneof=viewer hr-admin department-head admin", Message: "roles[1] must be one of viewer, hr-admin, department-head, admin"},
+	}, verr.Violations)
+}
+
+func TestValidateURL(t *testing.T) {
+	err := validator.Validate(domain.Webhook{URL: "partner", Events: []string{domain.EventEmployeeCreated}})
+	require.Error(t, err)
+
+	verr, ok := err.(domain.ValidationError)
+	require.True(t, ok)
+	require.Equal(t, []domain.FieldViolation{
+		{Field: "url", Rule: "url", Message: "url must be a valid url"},
+	}, verr.Violations)
+}
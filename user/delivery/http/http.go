@@ -0,0 +1,183 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
+)
+
+type userHandler struct {
+	service domain.UserService
+}
+
+type authHandler struct {
+	service domain.AuthService
+}
+
+type loginRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type passwordResetRequest struct {
+	Email string `json:"email" validate:"required"`
+}
+
+type passwordResetConfirmRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// AddUserHandler adds the user handler
+func AddUserHandler(e *echo.Echo, service domain.UserService) {
+	if service == nil {
+		panic("http: nil user service")
+	}
+
+	handler := &userHandler{service}
+
+	e.POST("/users", handler.Create)
+	e.GET("/users/:id", handler.Get)
+}
+
+// AddAuthHandler adds the login, token and password reset handler.
+// Every route except /auth/logout must skip the authentication middleware
+func AddAuthHandler(e *echo.Echo, service domain.AuthService) {
+	if service == nil {
+		panic("http: nil auth service")
+	}
+
+	handler := &authHandler{service}
+
+	e.POST("/auth/login", handler.Login)
+	e.POST("/auth/refresh", handler.Refresh)
+	e.POST("/auth/logout", handler.Logout)
+	e.POST("/auth/password-reset", handler.RequestPasswordReset)
+	e.POST("/auth/password-reset/confirm", handler.ResetPassword)
+}
+
+func (h userHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var u domain.User
+	if err := c.Bind(&u); err != nil {
+		return err
+	}
+
+	if err := validator.Validate(u); err != nil {
+		return err
+	}
+
+	if err := h.service.Create(ctx, &u); err != nil {
+		return errors.Wrap(err, "failed to create a user")
+	}
+
+	return c.JSON(http.StatusCreated, u)
+}
+
+func (h userHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	u, err := h.service.Get(ctx, c.Param("id"))
+	if err != nil {
+		return errors.Wrap(err, "failed get a user")
+	}
+
+	return c.JSON(http.StatusOK, u)
+}
+
+// bind binds and validates the request body
+func bind(c echo.Context, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		return domain.ConstraintErrorf("request body is not valid")
+	}
+
+	if err := validator.Validate(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h authHandler) Login(c echo.Context) error {
+	var req loginRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	tokens, err := h.service.Login(c.Request().Context(), req.Email, req.Password)
+	if err != nil {
+		return errors.Wrap(err, "failed to login")
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (h authHandler) Refresh(c echo.Context) error {
+	var req refreshRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	tokens, err := h.service.Refresh(c.Request().Context(), req.RefreshToken)
+	if err != nil {
+		return errors.Wrap(err, "failed to refresh token")
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (h authHandler) Logout(c echo.Context) error {
+	var req logoutRequest
+	if c.Request().ContentLength != 0 {
+		if err := bind(c, &req); err != nil {
+			return err
+		}
+	}
+
+	if err := h.service.Logout(c.Request().Context(), req.RefreshToken); err != nil {
+		return errors.Wrap(err, "failed to logout")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h authHandler) RequestPasswordReset(c echo.Context) error {
+	var req passwordResetRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.service.RequestPasswordReset(c.Request().Context(), req.Email); err != nil {
+		return errors.Wrap(err, "failed to request password reset")
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (h authHandler) ResetPassword(c echo.Context) error {
+	var req passwordResetConfirmRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.service.ResetPassword(c.Request().Context(), req.Token, req.Password); err != nil {
+		return errors.Wrap(err, "failed to reset password")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
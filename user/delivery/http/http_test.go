@@ -0,0 +1,111 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+	handler "github.com/milhamhidayat/golang-clean-code-v2/user/delivery/http"
+)
+
+func TestLogin(t *testing.T) {
+	tokens := domain.TokenPair{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh-1.secret"}
+
+	tests := map[string]struct {
+		reqBody        string
+		authService    testdata.FuncCall
+		expectedStatus int
+	}{
+		"success": {
+			reqBody: `{"email": "john@example.com", "password": "correct-horse"}`,
+			authService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "john@example.com", "correct-horse"},
+				Output: []interface{}{tokens, nil},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		"missing password": {
+			reqBody:        `{"email": "john@example.com"}`,
+			authService:    testdata.FuncCall{Called: false},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"wrong credentials": {
+			reqBody: `{"email": "john@example.com", "password": "wrong-password"}`,
+			authService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "john@example.com", "wrong-password"},
+				Output: []interface{}{domain.TokenPair{}, domain.ErrUnauthorized},
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := testdata.GetEchoServer()
+			e.Use(middleware.ErrorMiddleware())
+
+			mockAuthService := new(mocks.AuthService)
+			if tc.authService.Called {
+				mockAuthService.On("Login", tc.authService.Input...).Return(tc.authService.Output...).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(tc.reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			handler.AddAuthHandler(e, mockAuthService)
+
+			e.ServeHTTP(rec, req)
+
+			mockAuthService.AssertExpectations(t)
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var res domain.TokenPair
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				require.Equal(t, tokens, res)
+				require.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := map[string]struct {
+		reqBody      string
+		refreshToken string
+	}{
+		"with refresh token":   {reqBody: `{"refresh_token": "refresh-1.secret"}`, refreshToken: "refresh-1.secret"},
+		"without request body": {reqBody: ``},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := testdata.GetEchoServer()
+			e.Use(middleware.ErrorMiddleware())
+
+			mockAuthService := new(mocks.AuthService)
+			mockAuthService.On("Logout", mock.Anything, tc.refreshToken).Return(nil).Once()
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout", strings.NewReader(tc.reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			handler.AddAuthHandler(e, mockAuthService)
+
+			e.ServeHTTP(rec, req)
+
+			mockAuthService.AssertExpectations(t)
+			require.Equal(t, http.StatusNoContent, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/segmentio/ksuid"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// TokenRepository implement all auth token repository method from interface
+type TokenRepository struct {
+	DB *sql.DB
+}
+
+// NewTokenRepository return new auth token repository
+func NewTokenRepository(db *sql.DB) TokenRepository {
+	return TokenRepository{
+		DB: db,
+	}
+}
+
+// CreateRefreshToken is a repository to insert a refresh token
+func (r TokenRepository) CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if t.ID == "" {
+		t.ID = ksuid.New().String()
+	}
+	t.CreatedTime = localTime
+
+	query, args, err := sq.Insert("refresh_tokens").
+		Columns("id", "tenant_id", "user_id", "hashed_token", "expires_time", "created_time").
+		Values(t.ID, t.TenantID, t.UserID, t.HashedToken, t.ExpiresTime, t.CreatedTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed insert a refresh token")
+	}
+
+	return
+}
+
+// GetRefreshToken is a repository to get a refresh token
+func (r TokenRepository) GetRefreshToken(ctx context.Context, id string) (t domain.RefreshToken, err error) {
+	query, args, err := sq.Select("id", "tenant_id", "user_id", "hashed_token", "expires_time", "revoked_time", "created_time").
+		From("refresh_tokens").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	var revokedTime mysql.NullTime
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(&t.ID, &t.TenantID, &t.UserID, &t.HashedToken, &t.ExpiresTime, &revokedTime, &t.CreatedTime)
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	if revokedTime.Valid {
+		t.RevokedTime = &revokedTime.Time
+	}
+
+	return
+}
+
+// RevokeRefreshToken is a repository to revoke a refresh token which is not revoked yet.
+// ErrNotFound is returned when the token is already revoked, so a token is only exchanged once
+func (r TokenRepository) RevokeRefreshToken(ctx context.Context, id string, revokedTime time.Time) (err error) {
+	return r.updateOnce(ctx, sq.Update("refresh_tokens").
+		Set("revoked_time", revokedTime).
+		Where(sq.Eq{"id": id, "revoked_time": nil}))
+}
+
+// RevokeUserRefreshTokens is a repository to revoke every refresh token of a user
+func (r TokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, revokedTime time.Time) (err error) {
+	query, args, err := sq.Update("refresh_tokens").
+		Set("revoked_time", revokedTime).
+		Where(sq.Eq{"user_id": userID, "revoked_time": nil}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed revoke refresh tokens of a user")
+	}
+
+	return
+}
+
+// RevokeAccessToken is a repository to add an access token to the revocation list until it expires.
+// Expired entries are removed from the list on the way
+func (r TokenRepository) RevokeAccessToken(ctx context.Context, id string, expiresTime time.Time) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	query, args, err := sq.Delete("revoked_tokens").
+		Where(sq.Lt{"expires_time": localTime}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed delete expired revoked tokens")
+		return
+	}
+
+	query, args, err = sq.Insert("revoked_tokens").
+		Options("IGNORE").
+		Columns("id", "expires_time").
+		Values(id, expiresTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed insert a revoked token")
+	}
+
+	return
+}
+
+// IsAccessTokenRevoked is a repository to check whether an access token is in the revocation list
+func (r TokenRepository) IsAccessTokenRevoked(ctx context.Context, id string) (revoked bool, err error) {
+	query, args, err := sq.Select("COUNT(id)").
+		From("revoked_tokens").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	var count int
+	if err = r.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return
+	}
+
+	revoked = count > 0
+	return
+}
+
+// CreatePasswordResetToken is a repository to insert a password reset token
+func (r TokenRepository) CreatePasswordResetToken(ctx context.Context, t *domain.PasswordResetToken) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if t.ID == "" {
+		t.ID = ksuid.New().String()
+	}
+	t.CreatedTime = localTime
+
+	query, args, err := sq.Insert("password_reset_tokens").
+		Columns("id", "user_id", "hashed_token", "expires_time", "created_time").
+		Values(t.ID, t.UserID, t.HashedToken, t.ExpiresTime, t.CreatedTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed insert a password reset token")
+	}
+
+	return
+}
+
+// GetPasswordResetToken is a repository to get a password reset token
+func (r TokenRepository) GetPasswordResetToken(ctx context.Context, id string) (t domain.PasswordResetToken, err error) {
+	query, args, err := sq.Select("id", "user_id", "hashed_token", "expires_time", "used_time", "created_time").
+		From("password_reset_tokens").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	var usedTime mysql.NullTime
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(&t.ID, &t.UserID, &t.HashedToken, &t.ExpiresTime, &usedTime, &t.CreatedTime)
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	if usedTime.Valid {
+		t.UsedTime = &usedTime.Time
+	}
+
+	return
+}
+
+// UsePasswordResetToken is a repository to mark a password reset token as used.
+// ErrNotFound is returned when the token is already used
+func (r TokenRepository) UsePasswordResetToken(ctx context.Context, id string, usedTime time.Time) (err error) {
+	return r.updateOnce(ctx, sq.Update("password_reset_tokens").
+		Set("used_time", usedTime).
+		Where(sq.Eq{"id": id, "used_time": nil}))
+}
+
+func (r TokenRepository) updateOnce(ctx context.Context, qUpdate sq.UpdateBuilder) (err error) {
+	query, args, err := qUpdate.ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed update a token")
+		return
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if count == 0 {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
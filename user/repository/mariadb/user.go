@@ -0,0 +1,149 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+	"github.com/segmentio/ksuid"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// Repository implement all user repository method from interface
+type Repository struct {
+	DB *sql.DB
+}
+
+// New return new user repository
+func New(db *sql.DB) Repository {
+	return Repository{
+		DB: db,
+	}
+}
+
+var userColumns = []string{"id", "tenant_id", "employee_id", "email", "hashed_password", "roles", "created_time", "updated_time"}
+
+// Create is a repository to insert a user of the tenant
+func (r Repository) Create(ctx context.Context, u *domain.User) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if u.ID == "" {
+		u.ID = ksuid.New().String()
+	}
+
+	u.TenantID = domain.TenantFromContext(ctx)
+	u.CreatedTime = localTime
+	u.UpdatedTime = localTime
+
+	roles := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		roles = append(roles, string(role))
+	}
+
+	query, args, err := sq.Insert("users").
+		Columns("id", "tenant_id", "employee_id", "email", "hashed_password", "roles", "created_time", "updated_time").
+		Values(u.ID, u.TenantID, u.EmployeeID, u.Email, u.HashedPassword, strings.Join(roles, ","), u.CreatedTime, u.UpdatedTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	_, err = r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed insert a user")
+		return
+	}
+
+	return
+}
+
+// Get is a repository to get a user of the tenant
+func (r Repository) Get(ctx context.Context, id string) (u domain.User, err error) {
+	return r.get(ctx, sq.Eq{"id": id, "tenant_id": domain.TenantFromContext(ctx)})
+}
+
+// GetByEmail is a repository to get a user of any tenant by email
+func (r Repository) GetByEmail(ctx context.Context, email string) (u domain.User, err error) {
+	return r.get(ctx, sq.Eq{"email": email})
+}
+
+func (r Repository) get(ctx context.Context, where sq.Eq) (u domain.User, err error) {
+	query, args, err := sq.Select(userColumns...).
+		From("users").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	var roles string
+	err = r.DB.QueryRowContext(ctx, query, args...).Scan(
+		&u.ID,
+		&u.TenantID,
+		&u.EmployeeID,
+		&u.Email,
+		&u.HashedPassword,
+		&roles,
+		&u.CreatedTime,
+		&u.UpdatedTime,
+	)
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	u.Roles = make([]domain.Role, 0)
+	for _, role := range strings.Split(roles, ",") {
+		if role != "" {
+			u.Roles = append(u.Roles, domain.Role(role))
+		}
+	}
+
+	return
+}
+
+// UpdatePassword is a repository to replace the hashed password of a user
+func (r Repository) UpdatePassword(ctx context.Context, id, hashedPassword string) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	query, args, err := sq.Update("users").
+		SetMap(sq.Eq{
+			"hashed_password": hashedPassword,
+			"updated_time":    localTime,
+		}).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed update password of a user")
+		return
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if count == 0 {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
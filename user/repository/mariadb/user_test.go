@@ -0,0 +1,120 @@
+package mariadb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	mariadb "github.com/milhamhidayat/golang-clean-code-v2/driver/mariadb"
+	repo "github.com/milhamhidayat/golang-clean-code-v2/user/repository/mariadb"
+)
+
+type userSuite struct {
+	mariadb.DBSuite
+}
+
+func TestUserSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped for short testing")
+	}
+	suite.Run(t, new(userSuite))
+}
+
+func (u *userSuite) SetupTest() {
+	for _, table := range []string{"users", "refresh_tokens", "revoked_tokens", "password_reset_tokens"} {
+		_, err := u.DB.Exec("TRUNCATE " + table)
+		require.NoError(u.T(), err)
+	}
+}
+
+func (u *userSuite) TestCreate() {
+	userRepo := repo.New(u.DB)
+
+	user := domain.User{
+		EmployeeID:     "1S9XpJCvJbt1plvU36tAcJWS2ZW",
+		Email:          "john@example.com",
+		HashedPassword: "hashed",
+		Roles:          []domain.Role{domain.RoleViewer, domain.RoleDepartmentHead},
+	}
+	require.NoError(u.T(), userRepo.Create(context.Background(), &user))
+
+	res, err := userRepo.GetByEmail(context.Background(), user.Email)
+	require.NoError(u.T(), err)
+	require.Equal(u.T(), user.ID, res.ID)
+	require.Equal(u.T(), user.Roles, res.Roles)
+
+	_, err = userRepo.Get(context.Background(), "unknown")
+	require.Equal(u.T(), domain.ErrNotFound, err)
+}
+
+func (u *userSuite) TestTenant() {
+	userRepo := repo.New(u.DB)
+	acme := domain.NewContextWithTenant(context.Background(), "acme")
+
+	user := domain.User{EmployeeID: "1S9XpJCvJbt1plvU36tAcJWS2ZW", Email: "john@example.com", HashedPassword: "hashed", Roles: []domain.Role{domain.RoleViewer}}
+	require.NoError(u.T(), userRepo.Create(acme, &user))
+
+	_, err := userRepo.Get(domain.NewContextWithTenant(context.Background(), "globex"), user.ID)
+	require.Equal(u.T(), domain.ErrNotFound, err)
+
+	res, err := userRepo.GetByEmail(context.Background(), user.Email)
+	require.NoError(u.T(), err)
+	require.Equal(u.T(), "acme", res.TenantID)
+}
+
+func (u *userSuite) TestUpdatePassword() {
+	userRepo := repo.New(u.DB)
+
+	user := domain.User{EmployeeID: "1S9XpJCvJbt1plvU36tAcJWS2ZW", Email: "john@example.com", HashedPassword: "hashed", Roles: []domain.Role{domain.RoleViewer}}
+	require.NoError(u.T(), userRepo.Create(context.Background(), &user))
+
+	require.NoError(u.T(), userRepo.UpdatePassword(context.Background(), user.ID, "rehashed"))
+
+	res, err := userRepo.Get(context.Background(), user.ID)
+	require.NoError(u.T(), err)
+	require.Equal(u.T(), "rehashed", res.HashedPassword)
+}
+
+func (u *userSuite) TestRefreshToken() {
+	tokenRepo := repo.NewTokenRepository(u.DB)
+
+	token := domain.RefreshToken{UserID: "user-1", TenantID: "acme", HashedToken: "hashed", ExpiresTime: time.Now().Add(time.Hour)}
+	require.NoError(u.T(), tokenRepo.CreateRefreshToken(context.Background(), &token))
+
+	require.NoError(u.T(), tokenRepo.RevokeRefreshToken(context.Background(), token.ID, time.Now()))
+	require.Equal(u.T(), domain.ErrNotFound, tokenRepo.RevokeRefreshToken(context.Background(), token.ID, time.Now()))
+
+	res, err := tokenRepo.GetRefreshToken(context.Background(), token.ID)
+	require.NoError(u.T(), err)
+	require.NotNil(u.T(), res.RevokedTime)
+	require.Equal(u.T(), "acme", res.TenantID)
+}
+
+func (u *userSuite) TestRevokeAccessToken() {
+	tokenRepo := repo.NewTokenRepository(u.DB)
+
+	require.NoError(u.T(), tokenRepo.RevokeAccessToken(context.Background(), "access-1", time.Now().Add(time.Hour)))
+	require.NoError(u.T(), tokenRepo.RevokeAccessToken(context.Background(), "access-1", time.Now().Add(time.Hour)))
+
+	revoked, err := tokenRepo.IsAccessTokenRevoked(context.Background(), "access-1")
+	require.NoError(u.T(), err)
+	require.True(u.T(), revoked)
+
+	revoked, err = tokenRepo.IsAccessTokenRevoked(context.Background(), "access-2")
+	require.NoError(u.T(), err)
+	require.False(u.T(), revoked)
+}
+
+func (u *userSuite) TestPasswordResetToken() {
+	tokenRepo := repo.NewTokenRepository(u.DB)
+
+	token := domain.PasswordResetToken{UserID: "user-1", HashedToken: "hashed", ExpiresTime: time.Now().Add(time.Hour)}
+	require.NoError(u.T(), tokenRepo.CreatePasswordResetToken(context.Background(), &token))
+
+	require.NoError(u.T(), tokenRepo.UsePasswordResetToken(context.Background(), token.ID, time.Now()))
+	require.Equal(u.T(), domain.ErrNotFound, tokenRepo.UsePasswordResetToken(context.Background(), token.ID, time.Now()))
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/segmentio/ksuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// Lifetime of the issued tokens
+const (
+	AccessTokenTTL   = 15 * time.Minute
+	RefreshTokenTTL  = 30 * 24 * time.Hour
+	PasswordResetTTL = time.Hour
+)
+
+// minPasswordLength is the minimum length of a password, it is the same as User.Password validation
+const minPasswordLength = 8
+
+// errInvalidResetToken is returned for a password reset token which is unknown, used or expired
+var errInvalidResetToken = domain.ConstraintErrorf("password reset token is not valid")
+
+// TokenSigner issues access tokens
+type TokenSigner interface {
+	Sign(p domain.Principal, id string, expiresTime time.Time) (token string, err error)
+}
+
+// TokenVerifier verifies access tokens
+type TokenVerifier interface {
+	Verify(token string) (p domain.Principal, err error)
+}
+
+// Auth is an auth service of the local identity provider
+type Auth struct {
+	userRepo     domain.UserRepository
+	employeeRepo domain.EmployeeRepository
+	tokenRepo    domain.AuthTokenRepository
+	signer       TokenSigner
+	verifier     TokenVerifier
+	notifier     domain.PasswordResetNotifier
+}
+
+// NewAuth will create a new auth service.
+// The verifier must accept the tokens of the signer, every verified token is checked against the revocation list
+func NewAuth(userRepo domain.UserRepository, employeeRepo domain.EmployeeRepository, tokenRepo domain.AuthTokenRepository,
+	signer TokenSigner, verifier TokenVerifier, notifier domain.PasswordResetNotifier) Auth {
+	return Auth{
+		userRepo:     userRepo,
+		employeeRepo: employeeRepo,
+		tokenRepo:    tokenRepo,
+		signer:       signer,
+		verifier:     verifier,
+		notifier:     notifier,
+	}
+}
+
+var (
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+// compareDummyPassword spends the same time as comparing a password of an existing user
+func compareDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	})
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+// Login will verify the credentials and issue a token pair
+func (a Auth) Login(ctx context.Context, email, password string) (tokens domain.TokenPair, err error) {
+	u, err := a.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			compareDummyPassword(password)
+			err = domain.ErrUnauthorized
+		}
+		return
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(u.HashedPassword), []byte(password)) != nil {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	return a.issue(ctx, u)
+}
+
+// Refresh will exchange a refresh token for a new token pair, the refresh token is revoked afterward.
+// Presenting a revoked refresh token revokes every refresh token of the user since the token is likely stolen
+func (a Auth) Refresh(ctx context.Context, refreshToken string) (tokens domain.TokenPair, err error) {
+	id, secret, ok := splitToken(refreshToken)
+	if !ok {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	rt, err := a.tokenRepo.GetRefreshToken(ctx, id)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = domain.ErrUnauthorized
+		}
+		return
+	}
+
+	if !matchToken(secret, rt.HashedToken) {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	now, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if rt.RevokedTime != nil {
+		if err = a.tokenRepo.RevokeUserRefreshTokens(ctx, rt.UserID, now); err != nil {
+			return
+		}
+
+		logger.FromContext(ctx).Warnf("revoked refresh token %s of user %s is reused, every refresh token of the user is revoked", rt.ID, rt.UserID)
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	if now.After(rt.ExpiresTime) {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	u, err := a.userRepo.Get(domain.NewContextWithTenant(ctx, rt.TenantID), rt.UserID)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = domain.ErrUnauthorized
+		}
+		return
+	}
+
+	if tokens, err = a.issue(ctx, u); err != nil {
+		return
+	}
+
+	// the token is only revoked once the new tokens are issued so a failure doesn't burn it,
+	// the tokens issued by a concurrent exchange of the same token are never returned
+	if err = a.tokenRepo.RevokeRefreshToken(ctx, rt.ID, now); err != nil {
+		tokens = domain.TokenPair{}
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = domain.ErrUnauthorized
+		}
+		return
+	}
+
+	return
+}
+
+// Logout will revoke the access token of the caller and the refresh token when it is given
+func (a Auth) Logout(ctx context.Context, refreshToken string) (err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	jti, _ := p.Claims["jti"].(string)
+	if jti != "" {
+		if err = a.tokenRepo.RevokeAccessToken(ctx, jti, expiresTime(p.Claims["exp"])); err != nil {
+			return
+		}
+	}
+
+	if refreshToken == "" {
+		return
+	}
+
+	id, secret, ok := splitToken(refreshToken)
+	if !ok {
+		return domain.ConstraintErrorf("refresh token is not valid")
+	}
+
+	rt, err := a.tokenRepo.GetRefreshToken(ctx, id)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = domain.ConstraintErrorf("refresh token is not valid")
+		}
+		return
+	}
+
+	if !matchToken(secret, rt.HashedToken) {
+		return domain.ConstraintErrorf("refresh token is not valid")
+	}
+
+	if rt.UserID != p.Subject {
+		return domain.ErrForbidden
+	}
+
+	now, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if err = a.tokenRepo.RevokeRefreshToken(ctx, rt.ID, now); errors.Cause(err) == domain.ErrNotFound {
+		err = nil
+	}
+
+	return
+}
+
+// RequestPasswordReset will send a password reset token to the user.
+// No error is returned for an unknown email so the registered emails can't be enumerated
+func (a Auth) RequestPasswordReset(ctx context.Context, email string) (err error) {
+	u, err := a.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+
+	now, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	secret, hashed, err := generateToken()
+	if err != nil {
+		return
+	}
+
+	t := domain.PasswordResetToken{
+		UserID:      u.ID,
+		HashedToken: hashed,
+		ExpiresTime: now.Add(PasswordResetTTL),
+	}
+	if err = a.tokenRepo.CreatePasswordResetToken(ctx, &t); err != nil {
+		return
+	}
+
+	return a.notifier.NotifyPasswordReset(ctx, u, t.ID+"."+secret)
+}
+
+// ResetPassword will replace the password of the token owner, the token can only be used once.
+// Every refresh token of the user is revoked so other sessions have to login again
+func (a Auth) ResetPassword(ctx context.Context, token, password string) (err error) {
+	if len(password) < minPasswordLength {
+		return domain.ConstraintErrorf("password must be at least %d characters", minPasswordLength)
+	}
+
+	id, secret, ok := splitToken(token)
+	if !ok {
+		return errInvalidResetToken
+	}
+
+	t, err := a.tokenRepo.GetPasswordResetToken(ctx, id)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = errInvalidResetToken
+		}
+		return
+	}
+
+	now, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if !matchToken(secret, t.HashedToken) || t.UsedTime != nil || now.After(t.ExpiresTime) {
+		return errInvalidResetToken
+	}
+
+	if err = a.tokenRepo.UsePasswordResetToken(ctx, t.ID, now); err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = errInvalidResetToken
+		}
+		return
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return
+	}
+
+	if err = a.userRepo.UpdatePassword(ctx, t.UserID, string(hashed)); err != nil {
+		return
+	}
+
+	return a.tokenRepo.RevokeUserRefreshTokens(ctx, t.UserID, now)
+}
+
+// Verify will verify an access token and reject it when it is revoked.
+// The revocation list can't be checked without a request context, so it is checked with a background context
+func (a Auth) Verify(token string) (p domain.Principal, err error) {
+	if p, err = a.verifier.Verify(token); err != nil {
+		return
+	}
+
+	jti, _ := p.Claims["jti"].(string)
+	if jti == "" {
+		return
+	}
+
+	revoked, err := a.tokenRepo.IsAccessTokenRevoked(context.Background(), jti)
+	if err != nil {
+		logger.L().Errorf("failed to check revocation of token %s: %v", jti, err)
+		err = errors.Wrap(jwtauth.ErrInvalidToken, "can't check token revocation")
+		return
+	}
+
+	if revoked {
+		err = errors.Wrap(jwtauth.ErrInvalidToken, "token is revoked")
+	}
+
+	return
+}
+
+// issue signs an access token and creates a refresh token of the user, both are bound to the tenant of the user
+func (a Auth) issue(ctx context.Context, u domain.User) (tokens domain.TokenPair, err error) {
+	ctx = domain.NewContextWithTenant(ctx, u.TenantID)
+
+	employee, err := a.employeeRepo.Get(ctx, u.EmployeeID)
+	if errors.Cause(err) == domain.ErrNotFound {
+		err = domain.ErrUnauthorized
+		return
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get employee of user %s", u.ID)
+		return
+	}
+
+	now, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	p := domain.Principal{
+		Subject:      u.ID,
+		Roles:        u.Roles,
+		DepartmentID: employee.Department.ID,
+		EmployeeID:   u.EmployeeID,
+		TenantID:     u.TenantID,
+	}
+	accessToken, err := a.signer.Sign(p, ksuid.New().String(), now.Add(AccessTokenTTL))
+	if err != nil {
+		return
+	}
+
+	secret, hashed, err := generateToken()
+	if err != nil {
+		return
+	}
+
+	rt := domain.RefreshToken{
+		UserID:      u.ID,
+		TenantID:    u.TenantID,
+		HashedToken: hashed,
+		ExpiresTime: now.Add(RefreshTokenTTL),
+	}
+	if err = a.tokenRepo.CreateRefreshToken(ctx, &rt); err != nil {
+		return
+	}
+
+	tokens = domain.TokenPair{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(AccessTokenTTL / time.Second),
+		RefreshToken: rt.ID + "." + secret,
+	}
+
+	return
+}
+
+// expiresTime reads exp claim, the access token lifetime is assumed when it is missing
+func expiresTime(claim interface{}) time.Time {
+	switch v := claim.(type) {
+	case json.Number:
+		if exp, err := v.Int64(); err == nil {
+			return time.Unix(exp, 0)
+		}
+	case float64:
+		return time.Unix(int64(v), 0)
+	}
+
+	return time.Now().Add(AccessTokenTTL)
+}
+
+func splitToken(token string) (id, secret string, ok bool) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return
+	}
+
+	return parts[0], parts[1], true
+}
+
+func matchToken(secret, hashed string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(hashed)) == 1
+}
+
+func generateToken() (secret, hashed string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+
+	secret = base64.RawURLEncoding.EncodeToString(b)
+	hashed = hashToken(secret)
+	return
+}
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,568 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/jwtauth"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+	"github.com/milhamhidayat/golang-clean-code-v2/user/service"
+)
+
+const password = "correct-horse"
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+type authMocks struct {
+	userRepo     *mocks.UserRepository
+	employeeRepo *mocks.EmployeeRepository
+	tokenRepo    *mocks.AuthTokenRepository
+	notifier     *mocks.PasswordResetNotifier
+}
+
+func (m authMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.employeeRepo.AssertExpectations(t)
+	m.tokenRepo.AssertExpectations(t)
+	m.notifier.AssertExpectations(t)
+}
+
+func newAuth(t *testing.T) (service.Auth, *jwtauth.Verifier, authMocks) {
+	cfg := jwtauth.Config{HMACSecret: "s3cr3t"}
+	signer, err := jwtauth.NewSigner(cfg)
+	require.NoError(t, err)
+	verifier, err := jwtauth.New(cfg)
+	require.NoError(t, err)
+
+	m := authMocks{
+		userRepo:     new(mocks.UserRepository),
+		employeeRepo: new(mocks.EmployeeRepository),
+		tokenRepo:    new(mocks.AuthTokenRepository),
+		notifier:     new(mocks.PasswordResetNotifier),
+	}
+
+	return service.NewAuth(m.userRepo, m.employeeRepo, m.tokenRepo, signer, verifier, m.notifier), verifier, m
+}
+
+func newUser(t *testing.T) (u domain.User, employee domain.Employee) {
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	u = domain.User{
+		ID:             "user-1",
+		EmployeeID:     employee.ID,
+		Email:          "john@example.com",
+		HashedPassword: string(hashed),
+		Roles:          []domain.Role{domain.RoleDepartmentHead},
+		TenantID:       "acme",
+	}
+
+	return
+}
+
+// inTenant matches a context of the tenant
+func inTenant(tenantID string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.TenantFromContext(ctx) == tenantID
+	})
+}
+
+func TestLogin(t *testing.T) {
+	u, employee := newUser(t)
+
+	tests := map[string]struct {
+		email       string
+		password    string
+		userRepo    testdata.FuncCall
+		issued      bool
+		expectedErr error
+	}{
+		"success": {
+			email:    u.Email,
+			password: password,
+			userRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, u.Email},
+				Output: []interface{}{u, nil},
+			},
+			issued: true,
+		},
+		"wrong password": {
+			email:    u.Email,
+			password: "wrong-password",
+			userRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, u.Email},
+				Output: []interface{}{u, nil},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"unknown email": {
+			email:    "jane@example.com",
+			password: password,
+			userRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "jane@example.com"},
+				Output: []interface{}{domain.User{}, domain.ErrNotFound},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"with error get a user": {
+			email:    u.Email,
+			password: password,
+			userRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, u.Email},
+				Output: []interface{}{domain.User{}, errors.New("unexpected error")},
+			},
+			expectedErr: errors.New("unexpected error"),
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			auth, verifier, m := newAuth(t)
+			m.userRepo.On("GetByEmail", tc.userRepo.Input...).Return(tc.userRepo.Output...).Once()
+			if tc.issued {
+				m.employeeRepo.On("Get", inTenant("acme"), employee.ID).Return(employee, nil).Once()
+				m.tokenRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(rt *domain.RefreshToken) bool {
+					return rt.TenantID == "acme"
+				})).
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.RefreshToken).ID = "refresh-1"
+					}).
+					Return(nil).Once()
+			}
+
+			// the tenant of the request doesn't select the tenant of the user
+			ctx := domain.NewContextWithTenant(context.Background(), "globex")
+			tokens, err := auth.Login(ctx, tc.email, tc.password)
+
+			m.assertExpectations(t)
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr.Error(), errors.Cause(err).Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "Bearer", tokens.TokenType)
+			require.True(t, strings.HasPrefix(tokens.RefreshToken, "refresh-1."))
+
+			p, err := verifier.Verify(tokens.AccessToken)
+			require.NoError(t, err)
+			require.Equal(t, u.ID, p.Subject)
+			require.Equal(t, u.Roles, p.Roles)
+			require.Equal(t, employee.Department.ID, p.DepartmentID)
+			require.Equal(t, "acme", p.TenantID)
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	u, employee := newUser(t)
+	revokedTime := time.Now().Add(-time.Minute)
+
+	active := domain.RefreshToken{ID: "refresh-1", UserID: u.ID, TenantID: "acme", HashedToken: hash("secret"), ExpiresTime: time.Now().Add(time.Hour)}
+
+	revoked := active
+	revoked.RevokedTime = &revokedTime
+
+	expired := active
+	expired.ExpiresTime = time.Now().Add(-time.Minute)
+
+	tests := map[string]struct {
+		token       string
+		tokenRepo   map[string]testdata.FuncCall
+		issued      bool
+		userErr     error
+		employeeErr error
+		expectedErr error
+	}{
+		"success": {
+			token: "refresh-1.secret",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{active, nil},
+				},
+				"RevokeRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1", mock.Anything},
+					Output: []interface{}{nil},
+				},
+			},
+			issued: true,
+		},
+		"wrong secret": {
+			token: "refresh-1.wrong",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{active, nil},
+				},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"expired": {
+			token: "refresh-1.secret",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{expired, nil},
+				},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"reused revoked token revokes every token of the user": {
+			token: "refresh-1.secret",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{revoked, nil},
+				},
+				"RevokeUserRefreshTokens": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, u.ID, mock.Anything},
+					Output: []interface{}{nil},
+				},
+			},
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"concurrently exchanged": {
+			token: "refresh-1.secret",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{active, nil},
+				},
+				"RevokeRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1", mock.Anything},
+					Output: []interface{}{domain.ErrNotFound},
+				},
+			},
+			issued:      true,
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"deleted user keeps the token": {
+			token: "refresh-1.secret",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{active, nil},
+				},
+			},
+			issued:      true,
+			userErr:     domain.ErrNotFound,
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"deleted employee keeps the token": {
+			token: "refresh-1.secret",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{active, nil},
+				},
+			},
+			issued:      true,
+			employeeErr: domain.ErrNotFound,
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"failed issue keeps the token": {
+			token: "refresh-1.secret",
+			tokenRepo: map[string]testdata.FuncCall{
+				"GetRefreshToken": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "refresh-1"},
+					Output: []interface{}{active, nil},
+				},
+			},
+			issued:      true,
+			employeeErr: errors.New("connection refused"),
+			expectedErr: errors.New("connection refused"),
+		},
+		"malformed token": {
+			token:       "secret",
+			tokenRepo:   map[string]testdata.FuncCall{},
+			expectedErr: domain.ErrUnauthorized,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			auth, _, m := newAuth(t)
+			for name, fn := range tc.tokenRepo {
+				if fn.Called {
+					m.tokenRepo.On(name, fn.Input...).Return(fn.Output...).Once()
+				}
+			}
+			if tc.issued {
+				m.userRepo.On("Get", inTenant("acme"), u.ID).Return(u, tc.userErr).Once()
+				if tc.userErr == nil {
+					m.employeeRepo.On("Get", inTenant("acme"), employee.ID).Return(employee, tc.employeeErr).Once()
+				}
+				if tc.userErr == nil && tc.employeeErr == nil {
+					m.tokenRepo.On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil).Once()
+				}
+			}
+
+			tokens, err := auth.Refresh(domain.NewContextWithTenant(context.Background(), "globex"), tc.token)
+
+			m.assertExpectations(t)
+			if tc.expectedErr != nil {
+				require.EqualError(t, errors.Cause(err), tc.expectedErr.Error())
+				require.Empty(t, tokens)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotEmpty(t, tokens.AccessToken)
+			require.NotEqual(t, tc.token, tokens.RefreshToken)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	u, _ := newUser(t)
+	exp := time.Now().Add(time.Minute).Unix()
+
+	p := domain.Principal{Subject: u.ID, Claims: map[string]interface{}{"jti": "access-1", "exp": float64(exp)}}
+	ctx := domain.NewContextWithPrincipal(context.Background(), p)
+
+	t.Run("success", func(t *testing.T) {
+		auth, _, m := newAuth(t)
+		m.tokenRepo.On("RevokeAccessToken", mock.Anything, "access-1", time.Unix(exp, 0)).Return(nil).Once()
+		m.tokenRepo.On("GetRefreshToken", mock.Anything, "refresh-1").
+			Return(domain.RefreshToken{ID: "refresh-1", UserID: u.ID, HashedToken: hash("secret")}, nil).Once()
+		m.tokenRepo.On("RevokeRefreshToken", mock.Anything, "refresh-1", mock.Anything).Return(nil).Once()
+
+		err := auth.Logout(ctx, "refresh-1.secret")
+
+		m.assertExpectations(t)
+		require.NoError(t, err)
+	})
+
+	t.Run("refresh token of another user", func(t *testing.T) {
+		auth, _, m := newAuth(t)
+		m.tokenRepo.On("RevokeAccessToken", mock.Anything, "access-1", time.Unix(exp, 0)).Return(nil).Once()
+		m.tokenRepo.On("GetRefreshToken", mock.Anything, "refresh-2").
+			Return(domain.RefreshToken{ID: "refresh-2", UserID: "user-2", HashedToken: hash("secret")}, nil).Once()
+
+		err := auth.Logout(ctx, "refresh-2.secret")
+
+		m.assertExpectations(t)
+		require.Equal(t, domain.ErrForbidden, errors.Cause(err))
+	})
+
+	t.Run("not authenticated", func(t *testing.T) {
+		auth, _, m := newAuth(t)
+
+		err := auth.Logout(context.Background(), "")
+
+		m.assertExpectations(t)
+		require.Equal(t, domain.ErrUnauthorized, errors.Cause(err))
+	})
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	u, _ := newUser(t)
+
+	t.Run("success", func(t *testing.T) {
+		auth, _, m := newAuth(t)
+		var hashed string
+		m.userRepo.On("GetByEmail", mock.Anything, u.Email).Return(u, nil).Once()
+		m.tokenRepo.On("CreatePasswordResetToken", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				token := args.Get(1).(*domain.PasswordResetToken)
+				token.ID = "reset-1"
+				hashed = token.HashedToken
+			}).
+			Return(nil).Once()
+		m.notifier.On("NotifyPasswordReset", mock.Anything, u, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				token := args.String(2)
+				require.True(t, strings.HasPrefix(token, "reset-1."))
+				require.Equal(t, hashed, hash(strings.TrimPrefix(token, "reset-1.")))
+			}).
+			Return(nil).Once()
+
+		err := auth.RequestPasswordReset(context.Background(), u.Email)
+
+		m.assertExpectations(t)
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		auth, _, m := newAuth(t)
+		m.userRepo.On("GetByEmail", mock.Anything, "jane@example.com").Return(domain.User{}, domain.ErrNotFound).Once()
+
+		err := auth.RequestPasswordReset(context.Background(), "jane@example.com")
+
+		m.assertExpectations(t)
+		require.NoError(t, err)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	usedTime := time.Now().Add(-time.Minute)
+
+	active := domain.PasswordResetToken{ID: "reset-1", UserID: "user-1", HashedToken: hash("secret"), ExpiresTime: time.Now().Add(time.Hour)}
+
+	used := active
+	used.UsedTime = &usedTime
+
+	expired := active
+	expired.ExpiresTime = time.Now().Add(-time.Minute)
+
+	tests := map[string]struct {
+		token       string
+		password    string
+		resetToken  testdata.FuncCall
+		reset       bool
+		expectedErr error
+	}{
+		"success": {
+			token:    "reset-1.secret",
+			password: "new-password",
+			resetToken: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "reset-1"},
+				Output: []interface{}{active, nil},
+			},
+			reset: true,
+		},
+		"used token": {
+			token:    "reset-1.secret",
+			password: "new-password",
+			resetToken: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "reset-1"},
+				Output: []interface{}{used, nil},
+			},
+			expectedErr: domain.ConstraintErrorf("password reset token is not valid"),
+		},
+		"expired token": {
+			token:    "reset-1.secret",
+			password: "new-password",
+			resetToken: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "reset-1"},
+				Output: []interface{}{expired, nil},
+			},
+			expectedErr: domain.ConstraintErrorf("password reset token is not valid"),
+		},
+		"wrong secret": {
+			token:    "reset-1.wrong",
+			password: "new-password",
+			resetToken: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "reset-1"},
+				Output: []interface{}{active, nil},
+			},
+			expectedErr: domain.ConstraintErrorf("password reset token is not valid"),
+		},
+		"short password": {
+			token:       "reset-1.secret",
+			password:    "short",
+			resetToken:  testdata.FuncCall{Called: false},
+			expectedErr: domain.ConstraintErrorf("password must be at least 8 characters"),
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			auth, _, m := newAuth(t)
+			if tc.resetToken.Called {
+				m.tokenRepo.On("GetPasswordResetToken", tc.resetToken.Input...).Return(tc.resetToken.Output...).Once()
+			}
+			if tc.reset {
+				m.tokenRepo.On("UsePasswordResetToken", mock.Anything, "reset-1", mock.Anything).Return(nil).Once()
+				m.userRepo.On("UpdatePassword", mock.Anything, "user-1", mock.MatchedBy(func(hashed string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(tc.password)) == nil
+				})).Return(nil).Once()
+				m.tokenRepo.On("RevokeUserRefreshTokens", mock.Anything, "user-1", mock.Anything).Return(nil).Once()
+			}
+
+			err := auth.ResetPassword(context.Background(), tc.token, tc.password)
+
+			m.assertExpectations(t)
+			require.Equal(t, tc.expectedErr, errors.Cause(err))
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	signer, err := jwtauth.NewSigner(jwtauth.Config{HMACSecret: "s3cr3t"})
+	require.NoError(t, err)
+
+	token, err := signer.Sign(domain.Principal{Subject: "user-1"}, "access-1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		tokenRepo   testdata.FuncCall
+		expectedErr error
+	}{
+		"success": {
+			tokenRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "access-1"},
+				Output: []interface{}{false, nil},
+			},
+		},
+		"revoked": {
+			tokenRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "access-1"},
+				Output: []interface{}{true, nil},
+			},
+			expectedErr: jwtauth.ErrInvalidToken,
+		},
+		"with error check revocation": {
+			tokenRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, "access-1"},
+				Output: []interface{}{false, errors.New("unexpected error")},
+			},
+			expectedErr: jwtauth.ErrInvalidToken,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			auth, _, m := newAuth(t)
+			m.tokenRepo.On("IsAccessTokenRevoked", tc.tokenRepo.Input...).Return(tc.tokenRepo.Output...).Once()
+
+			p, err := auth.Verify(token)
+
+			m.assertExpectations(t)
+			require.Equal(t, tc.expectedErr, errors.Cause(err))
+			if tc.expectedErr == nil {
+				require.Equal(t, "user-1", p.Subject)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Authorization is a user service which only allows admin and hr-admin to manage users.
+// Only admin may grant admin role and a user may get their own account
+type Authorization struct {
+	next domain.UserService
+}
+
+// NewAuthorization will create a user service which only allows admin and hr-admin to manage users
+func NewAuthorization(next domain.UserService) Authorization {
+	return Authorization{
+		next: next,
+	}
+}
+
+// Create will create a user when the caller is admin or hr-admin
+func (a Authorization) Create(ctx context.Context, u *domain.User) (err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	if !p.HasRole(domain.RoleAdmin, domain.RoleHRAdmin) {
+		return domain.ErrForbidden
+	}
+
+	for _, role := range u.Roles {
+		if role == domain.RoleAdmin && !p.HasRole(domain.RoleAdmin) {
+			return domain.ErrForbidden
+		}
+	}
+
+	return a.next.Create(ctx, u)
+}
+
+// Get will get a user when the caller is admin, hr-admin or the user itself
+func (a Authorization) Get(ctx context.Context, id string) (u domain.User, err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		err = domain.ErrUnauthorized
+		return
+	}
+
+	if p.Subject != id && !p.HasRole(domain.RoleAdmin, domain.RoleHRAdmin) {
+		err = domain.ErrForbidden
+		return
+	}
+
+	return a.next.Get(ctx, id)
+}
@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/user/service"
+)
+
+func TestAuthorizationCreate(t *testing.T) {
+	admin := domain.Principal{Subject: "admin", Roles: []domain.Role{domain.RoleAdmin}}
+	hrAdmin := domain.Principal{Subject: "hr", Roles: []domain.Role{domain.RoleHRAdmin}}
+	viewer := domain.Principal{Subject: "viewer", Roles: []domain.Role{domain.RoleViewer}}
+
+	tests := map[string]struct {
+		principal   domain.Principal
+		roles       []domain.Role
+		called      bool
+		expectedErr error
+	}{
+		"admin grants admin":     {principal: admin, roles: []domain.Role{domain.RoleAdmin}, called: true},
+		"hr admin grants viewer": {principal: hrAdmin, roles: []domain.Role{domain.RoleViewer}, called: true},
+		"hr admin grants admin":  {principal: hrAdmin, roles: []domain.Role{domain.RoleAdmin}, expectedErr: domain.ErrForbidden},
+		"viewer":                 {principal: viewer, roles: []domain.Role{domain.RoleViewer}, expectedErr: domain.ErrForbidden},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			ctx := domain.NewContextWithPrincipal(context.Background(), tc.principal)
+			u := domain.User{Roles: tc.roles}
+
+			mockUserService := new(mocks.UserService)
+			if tc.called {
+				mockUserService.On("Create", ctx, &u).Return(nil).Once()
+			}
+
+			err := service.NewAuthorization(mockUserService).Create(ctx, &u)
+
+			mockUserService.AssertExpectations(t)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
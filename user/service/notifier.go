@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+)
+
+// LogNotifier is a password reset notifier writing the token to the log.
+// It is meant for development, the token grants access to the account so the log must not be shared
+type LogNotifier struct{}
+
+// NotifyPasswordReset will log the password reset token of the user
+func (LogNotifier) NotifyPasswordReset(ctx context.Context, u domain.User, token string) (err error) {
+	logger.FromContext(ctx).WithField("user_id", u.ID).Infof("password reset token of %s: %s", u.Email, token)
+	return
+}
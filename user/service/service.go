@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/friendsofgo/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Service is a user service
+type Service struct {
+	userRepo     domain.UserRepository
+	employeeRepo domain.EmployeeRepository
+}
+
+// New will create a new user service
+func New(userRepo domain.UserRepository, employeeRepo domain.EmployeeRepository) Service {
+	return Service{
+		userRepo:     userRepo,
+		employeeRepo: employeeRepo,
+	}
+}
+
+// Create will create a user of an existing employee, the plain password is cleared once it is hashed
+func (s Service) Create(ctx context.Context, u *domain.User) (err error) {
+	if _, err = s.employeeRepo.Get(ctx, u.EmployeeID); err != nil {
+		if errors.Cause(err) == domain.ErrNotFound {
+			err = domain.ConstraintErrorf("employee %s is not found", u.EmployeeID)
+		}
+		return
+	}
+
+	_, err = s.userRepo.GetByEmail(ctx, u.Email)
+	switch {
+	case err == nil:
+		return domain.ConstraintErrorf("email %s is already registered", u.Email)
+	case errors.Cause(err) != domain.ErrNotFound:
+		return
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return
+	}
+
+	u.HashedPassword = string(hashed)
+	u.Password = ""
+
+	if err = s.userRepo.Create(ctx, u); err != nil {
+		err = errors.Wrap(err, "failed to create a user")
+		return
+	}
+
+	return
+}
+
+// Get will return a user
+func (s Service) Get(ctx context.Context, id string) (u domain.User, err error) {
+	return s.userRepo.Get(ctx, id)
+}
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+	"github.com/milhamhidayat/golang-clean-code-v2/user/service"
+)
+
+func TestCreate(t *testing.T) {
+	var employee domain.Employee
+	testdata.UnmarshallGoldenToJSON(t, "employee-1S9XpJCvJbt1plvU36tAcJWS2ZW", &employee)
+
+	tests := map[string]struct {
+		employeeRepo testdata.FuncCall
+		userRepo     map[string]testdata.FuncCall
+		expectedErr  error
+	}{
+		"success": {
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{employee, nil},
+			},
+			userRepo: map[string]testdata.FuncCall{
+				"GetByEmail": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "john@example.com"},
+					Output: []interface{}{domain.User{}, domain.ErrNotFound},
+				},
+				"Create": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, mock.Anything},
+					Output: []interface{}{nil},
+				},
+			},
+		},
+		"unknown employee": {
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{domain.Employee{}, domain.ErrNotFound},
+			},
+			userRepo:    map[string]testdata.FuncCall{},
+			expectedErr: domain.ConstraintErrorf("employee %s is not found", employee.ID),
+		},
+		"email is already registered": {
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{employee, nil},
+			},
+			userRepo: map[string]testdata.FuncCall{
+				"GetByEmail": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "john@example.com"},
+					Output: []interface{}{domain.User{ID: "user-2"}, nil},
+				},
+			},
+			expectedErr: domain.ConstraintErrorf("email john@example.com is already registered"),
+		},
+		"with error create a user": {
+			employeeRepo: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, employee.ID},
+				Output: []interface{}{employee, nil},
+			},
+			userRepo: map[string]testdata.FuncCall{
+				"GetByEmail": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, "john@example.com"},
+					Output: []interface{}{domain.User{}, domain.ErrNotFound},
+				},
+				"Create": testdata.FuncCall{
+					Called: true,
+					Input:  []interface{}{mock.Anything, mock.Anything},
+					Output: []interface{}{errors.New("unexpected error")},
+				},
+			},
+			expectedErr: errors.New("unexpected error"),
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepository)
+			mockEmployeeRepo := new(mocks.EmployeeRepository)
+			mockEmployeeRepo.On("Get", tc.employeeRepo.Input...).Return(tc.employeeRepo.Output...).Once()
+			for name, fn := range tc.userRepo {
+				if fn.Called {
+					mockUserRepo.On(name, fn.Input...).Return(fn.Output...).Once()
+				}
+			}
+
+			u := domain.User{
+				EmployeeID: employee.ID,
+				Email:      "john@example.com",
+				Password:   password,
+				Roles:      []domain.Role{domain.RoleViewer},
+			}
+			err := service.New(mockUserRepo, mockEmployeeRepo).Create(context.Background(), &u)
+
+			mockUserRepo.AssertExpectations(t)
+			mockEmployeeRepo.AssertExpectations(t)
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr.Error(), errors.Cause(err).Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Empty(t, u.Password)
+			require.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.HashedPassword), []byte(password)))
+		})
+	}
+}
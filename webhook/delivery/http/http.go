@@ -0,0 +1,145 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/friendsofgo/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	pkgCursor "github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/validator"
+)
+
+type webhookHandler struct {
+	service domain.WebhookService
+}
+
+// AddWebhookHandler adds the webhook admin handler
+func AddWebhookHandler(e *echo.Echo, service domain.WebhookService) {
+	if service == nil {
+		panic("http: nil webhook service")
+	}
+
+	handler := &webhookHandler{service}
+
+	e.POST("/webhooks", handler.Create)
+	e.GET("/webhooks/:id", handler.Get)
+	e.GET("/webhooks", handler.Fetch)
+	e.DELETE("/webhooks/:id", handler.Delete)
+	e.GET("/webhooks/:id/deliveries", handler.FetchDeliveries)
+	e.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", handler.Redeliver)
+}
+
+func (h webhookHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var w domain.Webhook
+	if err := c.Bind(&w); err != nil {
+		return err
+	}
+
+	if err := validator.Validate(w); err != nil {
+		return err
+	}
+
+	if err := h.service.Create(ctx, &w); err != nil {
+		return errors.Wrap(err, "failed to create a webhook")
+	}
+
+	return c.JSON(http.StatusCreated, w)
+}
+
+func (h webhookHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	w, err := h.service.Get(ctx, c.Param("id"))
+	if err != nil {
+		return errors.Wrap(err, "failed get a webhook")
+	}
+
+	return c.JSON(http.StatusOK, w)
+}
+
+func (h webhookHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	num, err := parseNum(c)
+	if err != nil {
+		return err
+	}
+
+	res, pagination, err := h.service.Fetch(ctx, domain.WebhookFilter{
+		Num:    num,
+		Cursor: c.QueryParam("cursor"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "error fetch webhooks")
+	}
+
+	setCursor(c, pagination)
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h webhookHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.service.Delete(ctx, c.Param("id")); err != nil {
+		return errors.Wrap(err, "failed to delete a webhook")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h webhookHandler) FetchDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	num, err := parseNum(c)
+	if err != nil {
+		return err
+	}
+
+	res, pagination, err := h.service.FetchDeliveries(ctx, c.Param("id"), domain.WebhookDeliveryFilter{
+		Num:    num,
+		Cursor: c.QueryParam("cursor"),
+		Status: c.QueryParam("status"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "error fetch webhook deliveries")
+	}
+
+	setCursor(c, pagination)
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h webhookHandler) Redeliver(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	d, err := h.service.Redeliver(ctx, c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		return errors.Wrap(err, "failed to redeliver a webhook delivery")
+	}
+
+	return c.JSON(http.StatusAccepted, d)
+}
+
+func parseNum(c echo.Context) (num int, err error) {
+	num = 20
+	if numStr := c.QueryParam("num"); numStr != "" {
+		if num, err = strconv.Atoi(numStr); err != nil {
+			err = fmt.Errorf("num query-param is not valid. Got error when parsing value: %v", err)
+			return 0, domain.ConstraintErrorf("%s", err)
+		}
+	}
+
+	return
+}
+
+func setCursor(c echo.Context, pagination domain.Pagination) {
+	c.Response().Header().Set("X-Cursor", pagination.NextCursor)
+	if link := pkgCursor.LinkHeader(*c.Request().URL, pagination.NextCursor, ""); link != "" {
+		c.Response().Header().Set("Link", link)
+	}
+}
@@ -0,0 +1,112 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/middleware"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+	handler "github.com/milhamhidayat/golang-clean-code-v2/webhook/delivery/http"
+)
+
+func TestCreate(t *testing.T) {
+	tests := map[string]struct {
+		reqBody        string
+		webhookService testdata.FuncCall
+		expectedStatus int
+	}{
+		"success": {
+			reqBody: `{"url": "https://partner.example.com/hooks", "events": ["employee.*"]}`,
+			webhookService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mock.Anything},
+				Output: []interface{}{nil},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		"invalid url": {
+			reqBody:        `{"url": "partner", "events": ["employee.*"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown event": {
+			reqBody: `{"url": "https://partner.example.com/hooks", "events": ["employee.promoted"]}`,
+			webhookService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mock.Anything},
+				Output: []interface{}{domain.ConstraintErrorf("unknown event employee.promoted")},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"not admin": {
+			reqBody: `{"url": "https://partner.example.com/hooks", "events": ["*"]}`,
+			webhookService: testdata.FuncCall{
+				Called: true,
+				Input:  []interface{}{mock.Anything, mock.Anything},
+				Output: []interface{}{domain.ErrForbidden},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := testdata.GetEchoServer()
+			e.Use(middleware.ErrorMiddleware())
+
+			mockWebhookService := new(mocks.WebhookService)
+			if tc.webhookService.Called {
+				mockWebhookService.On("Create", tc.webhookService.Input...).Run(func(args mock.Arguments) {
+					args.Get(1).(*domain.Webhook).Secret = "whsec_generated"
+				}).Return(tc.webhookService.Output...).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tc.reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			handler.AddWebhookHandler(e, mockWebhookService)
+
+			e.ServeHTTP(rec, req)
+
+			mockWebhookService.AssertExpectations(t)
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusCreated {
+				var res domain.Webhook
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				require.Equal(t, "whsec_generated", res.Secret)
+			}
+		})
+	}
+}
+
+func TestFetchDeliveries(t *testing.T) {
+	e := testdata.GetEchoServer()
+	e.Use(middleware.ErrorMiddleware())
+
+	mockWebhookService := new(mocks.WebhookService)
+	mockWebhookService.On("FetchDeliveries", mock.Anything, "w1", domain.WebhookDeliveryFilter{Num: 5, Status: domain.WebhookDeliveryDead}).
+		Return([]domain.WebhookDelivery{{ID: "d1", WebhookID: "w1", Status: domain.WebhookDeliveryDead, Attempts: 8}},
+			domain.Pagination{NextCursor: "ZDE="}, nil).Once()
+	handler.AddWebhookHandler(e, mockWebhookService)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/w1/deliveries?num=5&status=dead", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	mockWebhookService.AssertExpectations(t)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "ZDE=", rec.Header().Get("X-Cursor"))
+
+	var res []domain.WebhookDelivery
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Len(t, res, 1)
+	require.Equal(t, domain.WebhookDeliveryDead, res[0].Status)
+}
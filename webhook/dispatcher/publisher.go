@@ -0,0 +1,60 @@
+// Package dispatcher delivers the domain events to the webhooks subscribing to them
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Publisher is a domain.Publisher scheduling a delivery of the event to every webhook of its tenant subscribing to it,
+// the deliveries are sent by Sender
+type Publisher struct {
+	webhooks   domain.WebhookRepository
+	deliveries domain.WebhookDeliveryRepository
+}
+
+// NewPublisher will return a publisher scheduling the webhook deliveries
+func NewPublisher(webhooks domain.WebhookRepository, deliveries domain.WebhookDeliveryRepository) Publisher {
+	return Publisher{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+	}
+}
+
+// Publish schedules the deliveries of the event
+func (p Publisher) Publish(ctx context.Context, e domain.Event) (err error) {
+	ctx = domain.NewContextWithTenant(ctx, e.TenantID)
+
+	subscribers, err := p.webhooks.FetchSubscribers(ctx, e.Type)
+	if err != nil || len(subscribers) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, w := range subscribers {
+		d := domain.WebhookDelivery{
+			WebhookID:       w.ID,
+			TenantID:        e.TenantID,
+			EventID:         e.ID,
+			EventType:       e.Type,
+			Payload:         payload,
+			Status:          domain.WebhookDeliveryPending,
+			NextAttemptTime: &now,
+		}
+		if err = p.deliveries.Create(ctx, &d); err != nil {
+			return errors.Wrapf(err, "failed schedule the delivery of event %s to webhook %s", e.ID, w.ID)
+		}
+	}
+
+	return
+}
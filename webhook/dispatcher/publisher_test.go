@@ -0,0 +1,60 @@
+package dispatcher_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/webhook/dispatcher"
+)
+
+func TestPublish(t *testing.T) {
+	e := domain.Event{
+		ID:       "e1",
+		Type:     domain.EventEmployeeCreated,
+		TenantID: "acme",
+		Data:     json.RawMessage(`{"id":"1"}`),
+	}
+
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockWebhookRepo.On("FetchSubscribers", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.TenantFromContext(ctx) == "acme"
+	}), domain.EventEmployeeCreated).Return([]domain.Webhook{{ID: "w1"}, {ID: "w2"}}, nil).Once()
+
+	var scheduled []domain.WebhookDelivery
+	mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+	mockDeliveryRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		scheduled = append(scheduled, *args.Get(1).(*domain.WebhookDelivery))
+	}).Return(nil).Twice()
+
+	err := dispatcher.NewPublisher(mockWebhookRepo, mockDeliveryRepo).Publish(context.Background(), e)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 2)
+
+	for i, d := range scheduled {
+		require.Equal(t, []string{"w1", "w2"}[i], d.WebhookID)
+		require.Equal(t, "acme", d.TenantID)
+		require.Equal(t, "e1", d.EventID)
+		require.Equal(t, domain.WebhookDeliveryPending, d.Status)
+		require.NotNil(t, d.NextAttemptTime)
+
+		var payload domain.Event
+		require.NoError(t, json.Unmarshal(d.Payload, &payload))
+		require.Equal(t, e.Type, payload.Type)
+		require.JSONEq(t, string(e.Data), string(payload.Data))
+	}
+}
+
+func TestSubscribes(t *testing.T) {
+	w := domain.Webhook{Events: []string{"department.*", domain.EventEmployeeTransferred}}
+
+	require.True(t, w.Subscribes(domain.EventDepartmentDeleted))
+	require.True(t, w.Subscribes(domain.EventEmployeeTransferred))
+	require.False(t, w.Subscribes(domain.EventEmployeeCreated))
+	require.True(t, domain.Webhook{Events: []string{"*"}}.Subscribes(domain.EventEmployeeCreated))
+}
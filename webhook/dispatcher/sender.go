@@ -0,0 +1,211 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/netguard"
+)
+
+// Headers of a webhook delivery
+const (
+	// HeaderID is the id of the delivery, it is the same on every attempt so the receiver can deduplicate them
+	HeaderID        = "Webhook-Id"
+	HeaderEvent     = "Webhook-Event"
+	HeaderTimestamp = "Webhook-Timestamp"
+	// HeaderSignature is sha256=<hex HMAC-SHA256 of <timestamp>.<body> keyed by the webhook secret>
+	HeaderSignature = "Webhook-Signature"
+)
+
+// maxResponseLength bounds the response body kept as the error of a failed attempt
+const maxResponseLength = 512
+
+// SenderConfig is the configuration of the sender
+type SenderConfig struct {
+	// BatchSize is the number of due deliveries sent at a time
+	BatchSize int
+	// MaxAttempts is the number of attempts after which a delivery is dead
+	MaxAttempts int
+	// Backoff is the delay after the first failed attempt, it doubles after every attempt up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Sender sends the due webhook deliveries, a failed delivery is retried with an exponential backoff
+// until it is dead after its last attempt
+type Sender struct {
+	webhooks   domain.WebhookRepository
+	deliveries domain.WebhookDeliveryRepository
+	client     *http.Client
+	cfg        SenderConfig
+}
+
+// NewSender will return a sender of the webhook deliveries, the client shouldn't follow the redirects
+func NewSender(webhooks domain.WebhookRepository, deliveries domain.WebhookDeliveryRepository, client *http.Client, cfg SenderConfig) *Sender {
+	return &Sender{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		client:     client,
+		cfg:        cfg,
+	}
+}
+
+// NewClient will return a http client of the given timeout which doesn't follow the redirects.
+// The connections to loopback, link-local and private addresses are refused unless allowPrivate is set,
+// the address is checked once resolved so a host can't be rebound to an internal address after the webhook is created
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivate {
+		dialer.Control = netguard.Control
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		// no proxy, it would be dialed instead of the webhook
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Sign returns the signature of the body sent at the timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send sends a batch of due deliveries and returns the number of attempts
+func (s *Sender) Send(ctx context.Context) (attempts int, err error) {
+	due, err := s.deliveries.FetchDue(ctx, time.Now(), s.cfg.BatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return attempts, ctx.Err()
+		}
+
+		if err = s.attempt(ctx, d); err != nil {
+			return
+		}
+		attempts++
+	}
+
+	return
+}
+
+// attempt sends the delivery once and stores the outcome
+func (s *Sender) attempt(ctx context.Context, d domain.WebhookDelivery) (err error) {
+	ctx = domain.NewContextWithTenant(ctx, d.TenantID)
+
+	d.Attempts++
+	d.ResponseStatus = 0
+	d.LastError = ""
+
+	w, err := s.webhooks.Get(ctx, d.WebhookID)
+	switch {
+	case errors.Cause(err) == domain.ErrNotFound:
+		// the deliveries of a deleted webhook are dead at once
+		d.LastError = "webhook is deleted"
+		d.Attempts = s.cfg.MaxAttempts
+	case err != nil:
+		return
+	default:
+		d.ResponseStatus, err = s.post(ctx, w, d)
+		if err != nil {
+			d.LastError = err.Error()
+		}
+	}
+
+	switch {
+	case d.LastError == "" && d.ResponseStatus >= 200 && d.ResponseStatus < 300:
+		d.Status = domain.WebhookDeliverySucceeded
+		d.NextAttemptTime = nil
+	case d.Attempts >= s.cfg.MaxAttempts:
+		d.Status = domain.WebhookDeliveryDead
+		d.NextAttemptTime = nil
+		logger.FromContext(ctx).Warnf("webhook delivery %s is dead after %d attempts: %s", d.ID, d.Attempts, d.LastError)
+	default:
+		next := time.Now().Add(s.backoff(d.Attempts))
+		d.NextAttemptTime = &next
+	}
+
+	return s.deliveries.Update(ctx, d)
+}
+
+// post sends the payload of the delivery signed by the secret of the webhook, the body of a non 2xx response is returned
+// as the error
+func (s *Sender) post(ctx context.Context, w domain.Webhook, d domain.WebhookDelivery) (status int, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "employee-webhook")
+	req.Header.Set(HeaderID, d.ID)
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, d.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	status = res.StatusCode
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxResponseLength))
+	// the rest of the body is drained so the connection is reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, 1<<20))
+
+	if status < 200 || status >= 300 {
+		err = fmt.Errorf("webhook responded %d: %s", status, bytes.TrimSpace(body))
+	}
+
+	return
+}
+
+// backoff returns the delay before the attempt following the given one
+func (s *Sender) backoff(attempts int) time.Duration {
+	delay := s.cfg.Backoff
+	for i := 1; i < attempts && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if s.cfg.MaxBackoff > 0 && delay > s.cfg.MaxBackoff {
+		delay = s.cfg.MaxBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,168 @@
+package dispatcher_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/testdata"
+	"github.com/milhamhidayat/golang-clean-code-v2/webhook/dispatcher"
+)
+
+var senderConfig = dispatcher.SenderConfig{
+	BatchSize:   10,
+	MaxAttempts: 3,
+	Backoff:     time.Minute,
+	MaxBackoff:  3 * time.Minute,
+}
+
+func newDelivery(attempts int) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:        "d1",
+		WebhookID: "w1",
+		TenantID:  "acme",
+		EventID:   "e1",
+		EventType: domain.EventEmployeeTransferred,
+		Payload:   json.RawMessage(`{"id":"e1","type":"employee.transferred"}`),
+		Status:    domain.WebhookDeliveryPending,
+		Attempts:  attempts,
+	}
+}
+
+// send sends the delivery to the webhook and returns the stored outcome, the test servers listen on loopback
+func send(t *testing.T, w domain.Webhook, webhookErr error, d domain.WebhookDelivery) (res domain.WebhookDelivery) {
+	return sendWith(t, dispatcher.NewClient(time.Second, true), w, webhookErr, d)
+}
+
+func sendWith(t *testing.T, client *http.Client, w domain.Webhook, webhookErr error, d domain.WebhookDelivery) (res domain.WebhookDelivery) {
+	t.Helper()
+
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockWebhookRepo.On("Get", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.TenantFromContext(ctx) == "acme"
+	}), "w1").Return(w, webhookErr).Once()
+
+	mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+	mockDeliveryRepo.On("FetchDue", mock.Anything, mock.Anything, 10).Return([]domain.WebhookDelivery{d}, nil).Once()
+	mockDeliveryRepo.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		res = args.Get(1).(domain.WebhookDelivery)
+	}).Return(nil).Once()
+
+	attempts, err := dispatcher.NewSender(mockWebhookRepo, mockDeliveryRepo, client, senderConfig).
+		Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, attempts)
+	mockDeliveryRepo.AssertExpectations(t)
+
+	return
+}
+
+func TestSendSigned(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := newDelivery(0)
+	res := send(t, domain.Webhook{ID: "w1", URL: server.URL, Secret: "whsec_secret"}, nil, d)
+
+	require.Equal(t, domain.WebhookDeliverySucceeded, res.Status)
+	require.Equal(t, 1, res.Attempts)
+	require.Equal(t, http.StatusNoContent, res.ResponseStatus)
+	require.Nil(t, res.NextAttemptTime)
+
+	require.Equal(t, string(d.Payload), string(body))
+	require.Equal(t, "d1", received.Header.Get(dispatcher.HeaderID))
+	require.Equal(t, domain.EventEmployeeTransferred, received.Header.Get(dispatcher.HeaderEvent))
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(dispatcher.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	require.Equal(t, dispatcher.Sign("whsec_secret", timestamp, body), received.Header.Get(dispatcher.HeaderSignature))
+}
+
+func TestSendRetried(t *testing.T) {
+	server, closeServer := testdata.MockServer(t, map[string]testdata.HTTPCall{
+		"POST /hooks": {
+			Method:       http.MethodPost,
+			Status:       http.StatusServiceUnavailable,
+			ExpectedResp: []byte(`{"error":"maintenance"}`),
+		},
+	})
+	defer closeServer()
+
+	w := domain.Webhook{ID: "w1", URL: server.URL + "/hooks", Secret: "whsec_secret"}
+
+	t.Run("backoff doubles", func(t *testing.T) {
+		before := time.Now()
+		res := send(t, w, nil, newDelivery(1))
+
+		require.Equal(t, domain.WebhookDeliveryPending, res.Status)
+		require.Equal(t, 2, res.Attempts)
+		require.Equal(t, http.StatusServiceUnavailable, res.ResponseStatus)
+		require.Equal(t, `webhook responded 503: {"error":"maintenance"}`, res.LastError)
+		require.NotNil(t, res.NextAttemptTime)
+		require.WithinDuration(t, before.Add(2*time.Minute), *res.NextAttemptTime, 5*time.Second)
+	})
+
+	t.Run("dead after the last attempt", func(t *testing.T) {
+		res := send(t, w, nil, newDelivery(2))
+
+		require.Equal(t, domain.WebhookDeliveryDead, res.Status)
+		require.Equal(t, 3, res.Attempts)
+		require.Nil(t, res.NextAttemptTime)
+	})
+
+	t.Run("redirect is not followed", func(t *testing.T) {
+		redirect := httptest.NewServer(http.RedirectHandler(server.URL+"/hooks", http.StatusFound))
+		defer redirect.Close()
+
+		res := send(t, domain.Webhook{ID: "w1", URL: redirect.URL, Secret: "whsec_secret"}, nil, newDelivery(0))
+		require.Equal(t, domain.WebhookDeliveryPending, res.Status)
+		require.Equal(t, http.StatusFound, res.ResponseStatus)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		res := send(t, domain.Webhook{ID: "w1", URL: "http://127.0.0.1:1/hooks", Secret: "whsec_secret"}, nil, newDelivery(0))
+		require.Equal(t, domain.WebhookDeliveryPending, res.Status)
+		require.Zero(t, res.ResponseStatus)
+		require.NotEmpty(t, res.LastError)
+	})
+}
+
+func TestSendDeletedWebhook(t *testing.T) {
+	res := send(t, domain.Webhook{}, domain.ErrNotFound, newDelivery(0))
+
+	require.Equal(t, domain.WebhookDeliveryDead, res.Status)
+	require.Equal(t, "webhook is deleted", res.LastError)
+}
+
+func TestSendPrivateAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	res := sendWith(t, dispatcher.NewClient(time.Second, false),
+		domain.Webhook{ID: "w1", URL: server.URL, Secret: "whsec_secret"}, nil, newDelivery(0))
+
+	require.False(t, called)
+	require.Equal(t, domain.WebhookDeliveryPending, res.Status)
+	require.Zero(t, res.ResponseStatus)
+	require.Contains(t, res.LastError, "address is not public")
+}
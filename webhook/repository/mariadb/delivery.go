@@ -0,0 +1,248 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/segmentio/ksuid"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// errDuplicateEntry is the mysql error number of a duplicate unique key
+const errDuplicateEntry = 1062
+
+// DeliveryRepository implement all webhook delivery repository method from interface
+type DeliveryRepository struct {
+	DB *sql.DB
+}
+
+// NewDeliveryRepository return new webhook delivery repository
+func NewDeliveryRepository(db *sql.DB) DeliveryRepository {
+	return DeliveryRepository{
+		DB: db,
+	}
+}
+
+var deliveryColumns = []string{"id", "webhook_id", "tenant_id", "event_id", "event_type", "payload", "status", "attempts",
+	"response_status", "last_error", "next_attempt_time", "created_time", "updated_time"}
+
+// Create is a repository to insert a webhook delivery, an event already delivered to the webhook is ignored
+// since the events are published at least once
+func (r DeliveryRepository) Create(ctx context.Context, d *domain.WebhookDelivery) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if d.ID == "" {
+		d.ID = ksuid.New().String()
+	}
+
+	d.CreatedTime = localTime
+	d.UpdatedTime = localTime
+
+	query, args, err := sq.Insert("webhook_deliveries").
+		Columns("id", "webhook_id", "tenant_id", "event_id", "event_type", "payload", "status", "attempts",
+			"next_attempt_time", "created_time", "updated_time").
+		Values(d.ID, d.WebhookID, d.TenantID, d.EventID, d.EventType, string(d.Payload), d.Status, d.Attempts,
+			d.NextAttemptTime, d.CreatedTime, d.UpdatedTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	_, err = r.DB.ExecContext(ctx, query, args...)
+	if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+		return nil
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed insert a webhook delivery")
+	}
+
+	return
+}
+
+// Fetch is a repository to fetch the deliveries of a webhook of the tenant, the latest first
+func (r DeliveryRepository) Fetch(ctx context.Context, webhookID string, filter domain.WebhookDeliveryFilter) (deliveries []domain.WebhookDelivery, pagination domain.Pagination, err error) {
+	qSelect := sq.Select(deliveryColumns...).
+		From("webhook_deliveries").
+		Where(sq.Eq{"webhook_id": webhookID, "tenant_id": domain.TenantFromContext(ctx)}).
+		OrderBy("id desc")
+
+	if filter.Status != "" {
+		qSelect = qSelect.Where(sq.Eq{"status": filter.Status})
+	}
+
+	if filter.Cursor != "" {
+		var decodedCursor string
+		if decodedCursor, err = cursor.DecodeBase64(filter.Cursor); err != nil {
+			err = domain.ConstraintErrorf("cursor is not valid")
+			return
+		}
+		qSelect = qSelect.Where(sq.Lt{"id": decodedCursor})
+	}
+
+	if filter.Num > 0 {
+		qSelect = qSelect.Limit(uint64(filter.Num))
+	}
+
+	deliveries, err = r.fetch(ctx, qSelect)
+	if err != nil {
+		return
+	}
+
+	pagination.NextCursor = filter.Cursor
+	if len(deliveries) > 0 {
+		pagination.NextCursor = cursor.EncodeBase64(deliveries[len(deliveries)-1].ID)
+	}
+
+	return
+}
+
+// Get is a repository to get a delivery of a webhook of the tenant
+func (r DeliveryRepository) Get(ctx context.Context, webhookID, id string) (d domain.WebhookDelivery, err error) {
+	query, args, err := sq.Select(deliveryColumns...).
+		From("webhook_deliveries").
+		Where(sq.Eq{"id": id, "webhook_id": webhookID, "tenant_id": domain.TenantFromContext(ctx)}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	d, err = scanDelivery(r.DB.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+// FetchDue is a repository to fetch the pending deliveries of every tenant due at the time, the oldest first
+func (r DeliveryRepository) FetchDue(ctx context.Context, now time.Time, num int) (deliveries []domain.WebhookDelivery, err error) {
+	return r.fetch(ctx, sq.Select(deliveryColumns...).
+		From("webhook_deliveries").
+		Where(sq.Eq{"status": domain.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_time": now}).
+		OrderBy("next_attempt_time asc", "id asc").
+		Limit(uint64(num)))
+}
+
+// Update is a repository to store the outcome of a delivery attempt
+func (r DeliveryRepository) Update(ctx context.Context, d domain.WebhookDelivery) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	responseStatus := sql.NullInt64{Int64: int64(d.ResponseStatus), Valid: d.ResponseStatus != 0}
+	lastError := sql.NullString{String: d.LastError, Valid: d.LastError != ""}
+
+	query, args, err := sq.Update("webhook_deliveries").
+		SetMap(sq.Eq{
+			"status":            d.Status,
+			"attempts":          d.Attempts,
+			"response_status":   responseStatus,
+			"last_error":        lastError,
+			"next_attempt_time": d.NextAttemptTime,
+			"updated_time":      localTime,
+		}).
+		Where(sq.Eq{"id": d.ID}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed update a webhook delivery")
+		return
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if count == 0 {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+func (r DeliveryRepository) fetch(ctx context.Context, qSelect sq.SelectBuilder) (deliveries []domain.WebhookDelivery, err error) {
+	query, args, err := qSelect.ToSql()
+	if err != nil {
+		return
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed fetch webhook deliveries")
+		return
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.FromContext(ctx).Errorf("failed to close webhook delivery rows: %v", err)
+		}
+	}()
+
+	deliveries = make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if d, err = scanDelivery(rows); err != nil {
+			return
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	err = rows.Err()
+	return
+}
+
+func scanDelivery(row scanner) (d domain.WebhookDelivery, err error) {
+	var (
+		payload         string
+		responseStatus  sql.NullInt64
+		lastError       sql.NullString
+		nextAttemptTime mysql.NullTime
+	)
+
+	err = row.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.TenantID,
+		&d.EventID,
+		&d.EventType,
+		&payload,
+		&d.Status,
+		&d.Attempts,
+		&responseStatus,
+		&lastError,
+		&nextAttemptTime,
+		&d.CreatedTime,
+		&d.UpdatedTime,
+	)
+	if err != nil {
+		return
+	}
+
+	d.Payload = []byte(payload)
+	d.ResponseStatus = int(responseStatus.Int64)
+	d.LastError = lastError.String
+	if nextAttemptTime.Valid {
+		d.NextAttemptTime = &nextAttemptTime.Time
+	}
+
+	return
+}
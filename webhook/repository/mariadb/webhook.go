@@ -0,0 +1,248 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/friendsofgo/errors"
+	"github.com/segmentio/ksuid"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/cursor"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/logger"
+	ntime "github.com/milhamhidayat/golang-clean-code-v2/pkg/time"
+)
+
+// Repository implement all webhook repository method from interface
+type Repository struct {
+	DB *sql.DB
+}
+
+// New return new webhook repository
+func New(db *sql.DB) Repository {
+	return Repository{
+		DB: db,
+	}
+}
+
+var columns = []string{"id", "url", "events", "secret", "created_time", "updated_time"}
+
+// Create is a repository to insert a webhook
+func (r Repository) Create(ctx context.Context, w *domain.Webhook) (err error) {
+	localTime, err := ntime.GetLocalTime()
+	if err != nil {
+		return
+	}
+
+	if w.ID == "" {
+		w.ID = ksuid.New().String()
+	}
+
+	w.CreatedTime = localTime
+	w.UpdatedTime = localTime
+
+	query, args, err := sq.Insert("webhooks").
+		Columns("id", "tenant_id", "url", "events", "secret", "created_time", "updated_time").
+		Values(w.ID, domain.TenantFromContext(ctx), w.URL, strings.Join(w.Events, ","), w.Secret, w.CreatedTime, w.UpdatedTime).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	if _, err = r.DB.ExecContext(ctx, query, args...); err != nil {
+		err = errors.Wrap(err, "failed insert a webhook")
+	}
+
+	return
+}
+
+// Fetch is a repository to fetch the webhooks of the tenant
+func (r Repository) Fetch(ctx context.Context, filter domain.WebhookFilter) (webhooks []domain.Webhook, pagination domain.Pagination, err error) {
+	qSelect := sq.Select(columns...).
+		From("webhooks").
+		Where(sq.Eq{"tenant_id": domain.TenantFromContext(ctx)}).
+		OrderBy("id desc")
+
+	if filter.Cursor != "" {
+		var decodedCursor string
+		if decodedCursor, err = cursor.DecodeBase64(filter.Cursor); err != nil {
+			err = domain.ConstraintErrorf("cursor is not valid")
+			return
+		}
+		qSelect = qSelect.Where(sq.Lt{"id": decodedCursor})
+	}
+
+	if filter.Num > 0 {
+		qSelect = qSelect.Limit(uint64(filter.Num))
+	}
+
+	webhooks, err = r.fetch(ctx, qSelect)
+	if err != nil {
+		return
+	}
+
+	pagination.NextCursor = filter.Cursor
+	if len(webhooks) > 0 {
+		pagination.NextCursor = cursor.EncodeBase64(webhooks[len(webhooks)-1].ID)
+	}
+
+	return
+}
+
+// Get is a repository to get a webhook of the tenant
+func (r Repository) Get(ctx context.Context, id string) (w domain.Webhook, err error) {
+	query, args, err := sq.Select(columns...).
+		From("webhooks").
+		Where(sq.Eq{"id": id, "tenant_id": domain.TenantFromContext(ctx)}).
+		ToSql()
+	if err != nil {
+		return
+	}
+
+	w, err = scan(r.DB.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		err = domain.ErrNotFound
+	}
+
+	return
+}
+
+// Delete is a repository to delete a webhook of the tenant with its deliveries
+func (r Repository) Delete(ctx context.Context, id string) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	query, args, err := sq.Delete("webhooks").
+		Where(sq.Eq{"id": id, "tenant_id": domain.TenantFromContext(ctx)}).
+		ToSql()
+	if err != nil {
+		r.rollback(ctx, tx)
+		return
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.rollback(ctx, tx)
+		err = errors.Wrap(err, "failed delete a webhook")
+		return
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		r.rollback(ctx, tx)
+		return
+	}
+
+	if count == 0 {
+		r.rollback(ctx, tx)
+		err = domain.ErrNotFound
+		return
+	}
+
+	query, args, err = sq.Delete("webhook_deliveries").
+		Where(sq.Eq{"webhook_id": id}).
+		ToSql()
+	if err != nil {
+		r.rollback(ctx, tx)
+		return
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		r.rollback(ctx, tx)
+		err = errors.Wrap(err, "failed delete the deliveries of a webhook")
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// FetchSubscribers is a repository to fetch the webhooks of the tenant subscribing to the event type
+func (r Repository) FetchSubscribers(ctx context.Context, eventType string) (webhooks []domain.Webhook, err error) {
+	// the event patterns are matched once the few webhooks of the tenant are fetched
+	all, err := r.fetch(ctx, sq.Select(columns...).
+		From("webhooks").
+		Where(sq.Eq{"tenant_id": domain.TenantFromContext(ctx)}).
+		OrderBy("id asc"))
+	if err != nil {
+		return
+	}
+
+	for _, w := range all {
+		if w.Subscribes(eventType) {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return
+}
+
+func (r Repository) fetch(ctx context.Context, qSelect sq.SelectBuilder) (webhooks []domain.Webhook, err error) {
+	query, args, err := qSelect.ToSql()
+	if err != nil {
+		return
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed fetch webhooks")
+		return
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.FromContext(ctx).Errorf("failed to close webhook rows: %v", err)
+		}
+	}()
+
+	webhooks = make([]domain.Webhook, 0)
+	for rows.Next() {
+		var w domain.Webhook
+		if w, err = scan(rows); err != nil {
+			return
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	err = rows.Err()
+	return
+}
+
+func (r Repository) rollback(ctx context.Context, tx *sql.Tx) {
+	err := tx.Rollback()
+	if err != nil && err != sql.ErrTxDone {
+		logger.FromContext(ctx).Errorf("failed to rollback webhook transaction: %v", err)
+	}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (w domain.Webhook, err error) {
+	var events string
+
+	err = row.Scan(
+		&w.ID,
+		&w.URL,
+		&events,
+		&w.Secret,
+		&w.CreatedTime,
+		&w.UpdatedTime,
+	)
+	if err != nil {
+		return
+	}
+
+	w.Events = make([]string, 0)
+	if events != "" {
+		w.Events = strings.Split(events, ",")
+	}
+
+	return
+}
@@ -0,0 +1,118 @@
+package mariadb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	mariadb "github.com/milhamhidayat/golang-clean-code-v2/driver/mariadb"
+	repo "github.com/milhamhidayat/golang-clean-code-v2/webhook/repository/mariadb"
+)
+
+type webhookSuite struct {
+	mariadb.DBSuite
+}
+
+func TestWebhookSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped for short testing")
+	}
+	suite.Run(t, new(webhookSuite))
+}
+
+func (w *webhookSuite) SetupTest() {
+	for _, table := range []string{"webhooks", "webhook_deliveries"} {
+		_, err := w.DB.Exec("TRUNCATE " + table)
+		require.NoError(w.T(), err)
+	}
+}
+
+func (w *webhookSuite) TestSubscribers() {
+	webhookRepo := repo.New(w.DB)
+	acme := domain.NewContextWithTenant(context.Background(), "acme")
+
+	employees := domain.Webhook{URL: "https://partner.example.com/employees", Events: []string{"employee.*"}, Secret: "secret"}
+	transfers := domain.Webhook{URL: "https://partner.example.com/transfers", Events: []string{domain.EventEmployeeTransferred}, Secret: "secret"}
+	other := domain.Webhook{URL: "https://other.example.com/hooks", Events: []string{"*"}, Secret: "secret"}
+	require.NoError(w.T(), webhookRepo.Create(acme, &employees))
+	require.NoError(w.T(), webhookRepo.Create(acme, &transfers))
+	require.NoError(w.T(), webhookRepo.Create(context.Background(), &other))
+
+	res, err := webhookRepo.FetchSubscribers(acme, domain.EventEmployeeTransferred)
+	require.NoError(w.T(), err)
+	require.Len(w.T(), res, 2)
+
+	res, err = webhookRepo.FetchSubscribers(acme, domain.EventEmployeeCreated)
+	require.NoError(w.T(), err)
+	require.Len(w.T(), res, 1)
+	require.Equal(w.T(), employees.ID, res[0].ID)
+	require.Equal(w.T(), "secret", res[0].Secret)
+
+	_, err = webhookRepo.Get(acme, other.ID)
+	require.Equal(w.T(), domain.ErrNotFound, err)
+}
+
+func (w *webhookSuite) TestDeliveries() {
+	webhookRepo := repo.New(w.DB)
+	deliveryRepo := repo.NewDeliveryRepository(w.DB)
+	ctx := context.Background()
+
+	webhook := domain.Webhook{URL: "https://partner.example.com/hooks", Events: []string{"*"}, Secret: "secret"}
+	require.NoError(w.T(), webhookRepo.Create(ctx, &webhook))
+
+	now := time.Now()
+	d := domain.WebhookDelivery{
+		WebhookID:       webhook.ID,
+		TenantID:        domain.DefaultTenantID,
+		EventID:         "e1",
+		EventType:       domain.EventEmployeeCreated,
+		Payload:         []byte(`{"id":"e1"}`),
+		Status:          domain.WebhookDeliveryPending,
+		NextAttemptTime: &now,
+	}
+	require.NoError(w.T(), deliveryRepo.Create(ctx, &d))
+
+	w.T().Run("event is delivered once to a webhook", func(t *testing.T) {
+		again := d
+		again.ID = ""
+		require.NoError(t, deliveryRepo.Create(ctx, &again))
+
+		res, _, err := deliveryRepo.Fetch(ctx, webhook.ID, domain.WebhookDeliveryFilter{Num: 10})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+	})
+
+	w.T().Run("due deliveries", func(t *testing.T) {
+		res, err := deliveryRepo.FetchDue(ctx, now.Add(time.Second), 10)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.JSONEq(t, `{"id":"e1"}`, string(res[0].Payload))
+
+		d.Status = domain.WebhookDeliveryDead
+		d.Attempts = 8
+		d.ResponseStatus = 503
+		d.LastError = "webhook responded 503"
+		d.NextAttemptTime = nil
+		require.NoError(t, deliveryRepo.Update(ctx, d))
+
+		res, err = deliveryRepo.FetchDue(ctx, now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+
+		dead, _, err := deliveryRepo.Fetch(ctx, webhook.ID, domain.WebhookDeliveryFilter{Status: domain.WebhookDeliveryDead})
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		require.Equal(t, 503, dead[0].ResponseStatus)
+	})
+
+	w.T().Run("deliveries are deleted with the webhook", func(t *testing.T) {
+		require.NoError(t, webhookRepo.Delete(ctx, webhook.ID))
+
+		_, err := deliveryRepo.Get(ctx, webhook.ID, d.ID)
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+}
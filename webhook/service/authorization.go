@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+)
+
+// Authorization is a webhook service which only allows admin to manage webhooks
+type Authorization struct {
+	next domain.WebhookService
+}
+
+// NewAuthorization will create a webhook service which only allows admin to manage webhooks
+func NewAuthorization(next domain.WebhookService) Authorization {
+	return Authorization{
+		next: next,
+	}
+}
+
+func authorize(ctx context.Context) (err error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	if !p.HasRole(domain.RoleAdmin) {
+		return domain.ErrForbidden
+	}
+
+	return
+}
+
+// Create will create a webhook when the caller is admin
+func (a Authorization) Create(ctx context.Context, w *domain.Webhook) (err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Create(ctx, w)
+}
+
+// Fetch will fetch webhooks when the caller is admin
+func (a Authorization) Fetch(ctx context.Context, filter domain.WebhookFilter) (webhooks []domain.Webhook, pagination domain.Pagination, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Fetch(ctx, filter)
+}
+
+// Get will get a webhook when the caller is admin
+func (a Authorization) Get(ctx context.Context, id string) (w domain.Webhook, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Get(ctx, id)
+}
+
+// Delete will delete a webhook when the caller is admin
+func (a Authorization) Delete(ctx context.Context, id string) (err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Delete(ctx, id)
+}
+
+// FetchDeliveries will fetch the deliveries of a webhook when the caller is admin
+func (a Authorization) FetchDeliveries(ctx context.Context, webhookID string, filter domain.WebhookDeliveryFilter) (deliveries []domain.WebhookDelivery, pagination domain.Pagination, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.FetchDeliveries(ctx, webhookID, filter)
+}
+
+// Redeliver will redeliver a webhook delivery when the caller is admin
+func (a Authorization) Redeliver(ctx context.Context, webhookID, deliveryID string) (d domain.WebhookDelivery, err error) {
+	if err = authorize(ctx); err != nil {
+		return
+	}
+
+	return a.next.Redeliver(ctx, webhookID, deliveryID)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/pkg/netguard"
+)
+
+// SecretPrefix is the prefix of a generated webhook secret
+const SecretPrefix = "whsec_"
+
+// Service is a webhook service
+type Service struct {
+	repo         domain.WebhookRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	allowPrivate bool
+}
+
+// New will create a new webhook service, the webhooks to loopback, link-local and private addresses
+// are rejected unless allowPrivate is set
+func New(repo domain.WebhookRepository, deliveryRepo domain.WebhookDeliveryRepository, allowPrivate bool) Service {
+	return Service{
+		repo:         repo,
+		deliveryRepo: deliveryRepo,
+		allowPrivate: allowPrivate,
+	}
+}
+
+// Create will create a webhook, the secret is generated when it's not given
+func (s Service) Create(ctx context.Context, w *domain.Webhook) (err error) {
+	if err = s.validateURL(ctx, w.URL); err != nil {
+		return
+	}
+
+	if err = validateEvents(w.Events); err != nil {
+		return
+	}
+
+	if w.Secret == "" {
+		if w.Secret, err = generateSecret(); err != nil {
+			return
+		}
+	}
+
+	if err = s.repo.Create(ctx, w); err != nil {
+		err = errors.Wrap(err, "failed to create a webhook")
+	}
+
+	return
+}
+
+// Fetch will return the webhooks without their secret
+func (s Service) Fetch(ctx context.Context, filter domain.WebhookFilter) (webhooks []domain.Webhook, pagination domain.Pagination, err error) {
+	webhooks, pagination, err = s.repo.Fetch(ctx, filter)
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return
+}
+
+// Get will return a webhook without its secret
+func (s Service) Get(ctx context.Context, id string) (w domain.Webhook, err error) {
+	w, err = s.repo.Get(ctx, id)
+	w.Secret = ""
+	return
+}
+
+// Delete will delete a webhook and its deliveries
+func (s Service) Delete(ctx context.Context, id string) (err error) {
+	return s.repo.Delete(ctx, id)
+}
+
+// FetchDeliveries will return the deliveries of a webhook
+func (s Service) FetchDeliveries(ctx context.Context, webhookID string, filter domain.WebhookDeliveryFilter) (deliveries []domain.WebhookDelivery, pagination domain.Pagination, err error) {
+	switch filter.Status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliverySucceeded, domain.WebhookDeliveryDead:
+	default:
+		err = domain.ConstraintErrorf("status must be one of %s, %s, %s",
+			domain.WebhookDeliveryPending, domain.WebhookDeliverySucceeded, domain.WebhookDeliveryDead)
+		return
+	}
+
+	if _, err = s.repo.Get(ctx, webhookID); err != nil {
+		return
+	}
+
+	return s.deliveryRepo.Fetch(ctx, webhookID, filter)
+}
+
+// Redeliver will schedule a delivery again with a fresh count of attempts, a pending delivery is left as is
+func (s Service) Redeliver(ctx context.Context, webhookID, deliveryID string) (d domain.WebhookDelivery, err error) {
+	d, err = s.deliveryRepo.Get(ctx, webhookID, deliveryID)
+	if err != nil {
+		return
+	}
+
+	if d.Status == domain.WebhookDeliveryPending {
+		return
+	}
+
+	now := time.Now()
+	d.Status = domain.WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptTime = &now
+
+	if err = s.deliveryRepo.Update(ctx, d); err != nil {
+		err = errors.Wrap(err, "failed to redeliver a webhook delivery")
+	}
+
+	return
+}
+
+func (s Service) validateURL(ctx context.Context, rawURL string) (err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return domain.ConstraintErrorf("url must be a http or https url")
+	}
+
+	if s.allowPrivate {
+		return
+	}
+
+	if err = netguard.CheckHost(ctx, u.Hostname()); err != nil {
+		return domain.ConstraintErrorf("url must not be a loopback, link-local or private address")
+	}
+
+	return
+}
+
+func validateEvents(events []string) (err error) {
+	if len(events) == 0 {
+		return domain.ConstraintErrorf("webhook must subscribe to at least one event")
+	}
+
+	for _, event := range events {
+		valid := event == "*"
+		for _, v := range domain.EventTypes {
+			if event == v || event == v[:strings.Index(v, ".")]+".*" {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return domain.ConstraintErrorf("unknown event %s", event)
+		}
+	}
+
+	return
+}
+
+func generateSecret() (secret string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+
+	return SecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
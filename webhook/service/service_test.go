@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milhamhidayat/golang-clean-code-v2/domain"
+	"github.com/milhamhidayat/golang-clean-code-v2/domain/mocks"
+	"github.com/milhamhidayat/golang-clean-code-v2/webhook/service"
+)
+
+func TestCreate(t *testing.T) {
+	tests := map[string]struct {
+		webhook       domain.Webhook
+		allowPrivate  bool
+		called        bool
+		expectedError error
+	}{
+		"success": {
+			webhook: domain.Webhook{URL: "https://partner.example.com/hooks", Events: []string{domain.EventEmployeeTransferred, "department.*"}},
+			called:  true,
+		},
+		"every event": {
+			webhook: domain.Webhook{URL: "https://partner.example.com/hooks", Events: []string{"*"}},
+			called:  true,
+		},
+		"unknown event": {
+			webhook:       domain.Webhook{URL: "https://partner.example.com/hooks", Events: []string{"employee.promoted"}},
+			expectedError: domain.ConstraintErrorf("unknown event employee.promoted"),
+		},
+		"no event": {
+			webhook:       domain.Webhook{URL: "https://partner.example.com/hooks"},
+			expectedError: domain.ConstraintErrorf("webhook must subscribe to at least one event"),
+		},
+		"not a http url": {
+			webhook:       domain.Webhook{URL: "ftp://partner.example.com/hooks", Events: []string{"*"}},
+			expectedError: domain.ConstraintErrorf("url must be a http or https url"),
+		},
+		"no host": {
+			webhook:       domain.Webhook{URL: "https:///hooks", Events: []string{"*"}},
+			expectedError: domain.ConstraintErrorf("url must be a http or https url"),
+		},
+		"metadata address": {
+			webhook:       domain.Webhook{URL: "http://169.254.169.254/latest/meta-data", Events: []string{"*"}},
+			expectedError: domain.ConstraintErrorf("url must not be a loopback, link-local or private address"),
+		},
+		"loopback host": {
+			webhook:       domain.Webhook{URL: "http://localhost:6060/debug/pprof", Events: []string{"*"}},
+			expectedError: domain.ConstraintErrorf("url must not be a loopback, link-local or private address"),
+		},
+		"private address": {
+			webhook:       domain.Webhook{URL: "http://10.0.0.1/hooks", Events: []string{"*"}},
+			expectedError: domain.ConstraintErrorf("url must not be a loopback, link-local or private address"),
+		},
+		"ipv6 loopback": {
+			webhook:       domain.Webhook{URL: "http://[::1]/hooks", Events: []string{"*"}},
+			expectedError: domain.ConstraintErrorf("url must not be a loopback, link-local or private address"),
+		},
+		"private address allowed": {
+			webhook:      domain.Webhook{URL: "http://10.0.0.1/hooks", Events: []string{"*"}},
+			allowPrivate: true,
+			called:       true,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			mockWebhookRepo := new(mocks.WebhookRepository)
+			if tc.called {
+				mockWebhookRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			w := tc.webhook
+			err := service.New(mockWebhookRepo, new(mocks.WebhookDeliveryRepository), tc.allowPrivate).Create(context.Background(), &w)
+
+			mockWebhookRepo.AssertExpectations(t)
+			if tc.expectedError != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(w.Secret, service.SecretPrefix))
+		})
+	}
+
+	t.Run("given secret is kept", func(t *testing.T) {
+		mockWebhookRepo := new(mocks.WebhookRepository)
+		mockWebhookRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		w := domain.Webhook{URL: "https://partner.example.com/hooks", Events: []string{"*"}, Secret: "partner-chosen-secret"}
+		require.NoError(t, service.New(mockWebhookRepo, new(mocks.WebhookDeliveryRepository), false).Create(context.Background(), &w))
+		require.Equal(t, "partner-chosen-secret", w.Secret)
+	})
+}
+
+func TestFetch(t *testing.T) {
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockWebhookRepo.On("Fetch", mock.Anything, domain.WebhookFilter{Num: 10}).
+		Return([]domain.Webhook{{ID: "1", Secret: "secret"}}, domain.Pagination{}, nil).Once()
+	mockWebhookRepo.On("Get", mock.Anything, "1").Return(domain.Webhook{ID: "1", Secret: "secret"}, nil).Once()
+
+	s := service.New(mockWebhookRepo, new(mocks.WebhookDeliveryRepository), false)
+
+	webhooks, _, err := s.Fetch(context.Background(), domain.WebhookFilter{Num: 10})
+	require.NoError(t, err)
+	require.Empty(t, webhooks[0].Secret)
+
+	w, err := s.Get(context.Background(), "1")
+	require.NoError(t, err)
+	require.Empty(t, w.Secret)
+}
+
+func TestFetchDeliveries(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockWebhookRepo := new(mocks.WebhookRepository)
+		mockWebhookRepo.On("Get", mock.Anything, "1").Return(domain.Webhook{ID: "1"}, nil).Once()
+		mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+		filter := domain.WebhookDeliveryFilter{Num: 10, Status: domain.WebhookDeliveryDead}
+		mockDeliveryRepo.On("Fetch", mock.Anything, "1", filter).
+			Return([]domain.WebhookDelivery{{ID: "d1", Status: domain.WebhookDeliveryDead}}, domain.Pagination{}, nil).Once()
+
+		deliveries, _, err := service.New(mockWebhookRepo, mockDeliveryRepo, false).FetchDeliveries(context.Background(), "1", filter)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+	})
+
+	t.Run("unknown webhook", func(t *testing.T) {
+		mockWebhookRepo := new(mocks.WebhookRepository)
+		mockWebhookRepo.On("Get", mock.Anything, "1").Return(domain.Webhook{}, domain.ErrNotFound).Once()
+
+		_, _, err := service.New(mockWebhookRepo, new(mocks.WebhookDeliveryRepository), false).
+			FetchDeliveries(context.Background(), "1", domain.WebhookDeliveryFilter{})
+		require.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		_, _, err := service.New(new(mocks.WebhookRepository), new(mocks.WebhookDeliveryRepository), false).
+			FetchDeliveries(context.Background(), "1", domain.WebhookDeliveryFilter{Status: "failed"})
+		require.EqualError(t, err, "status must be one of pending, succeeded, dead")
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	t.Run("dead delivery", func(t *testing.T) {
+		mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+		mockDeliveryRepo.On("Get", mock.Anything, "1", "d1").
+			Return(domain.WebhookDelivery{ID: "d1", Status: domain.WebhookDeliveryDead, Attempts: 8}, nil).Once()
+		mockDeliveryRepo.On("Update", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Status == domain.WebhookDeliveryPending && d.Attempts == 0 && d.NextAttemptTime != nil
+		})).Return(nil).Once()
+
+		d, err := service.New(new(mocks.WebhookRepository), mockDeliveryRepo, false).Redeliver(context.Background(), "1", "d1")
+		require.NoError(t, err)
+		require.Equal(t, domain.WebhookDeliveryPending, d.Status)
+		mockDeliveryRepo.AssertExpectations(t)
+	})
+
+	t.Run("pending delivery is left as is", func(t *testing.T) {
+		mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+		mockDeliveryRepo.On("Get", mock.Anything, "1", "d1").
+			Return(domain.WebhookDelivery{ID: "d1", Status: domain.WebhookDeliveryPending, Attempts: 2}, nil).Once()
+
+		d, err := service.New(new(mocks.WebhookRepository), mockDeliveryRepo, false).Redeliver(context.Background(), "1", "d1")
+		require.NoError(t, err)
+		require.Equal(t, 2, d.Attempts)
+		mockDeliveryRepo.AssertExpectations(t)
+	})
+
+	t.Run("error update", func(t *testing.T) {
+		mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+		mockDeliveryRepo.On("Get", mock.Anything, "1", "d1").
+			Return(domain.WebhookDelivery{ID: "d1", Status: domain.WebhookDeliveryDead}, nil).Once()
+		mockDeliveryRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("unexpected error")).Once()
+
+		_, err := service.New(new(mocks.WebhookRepository), mockDeliveryRepo, false).Redeliver(context.Background(), "1", "d1")
+		require.EqualError(t, err, "failed to redeliver a webhook delivery: unexpected error")
+	})
+}